@@ -0,0 +1,27 @@
+package dotenv
+
+import "fmt"
+
+//RuleAlias is the Rule of the Problems passed to a Sourcer's Warn when a name in
+//its Aliases is defined or read.
+const RuleAlias = "alias"
+
+//alias returns the name that name is an alias of in s.Aliases, or name if it is
+//not an alias.
+func (s *Sourcer) alias(name string) string {
+	if newName, ok := s.Aliases[name]; ok {
+		return newName
+	}
+	return name
+}
+
+//aliasProblem returns the Problem for the alias oldName of newName, which is
+//defined or read, as verb, at location.
+func aliasProblem(location Location, oldName, newName, verb string) *Problem {
+	return &Problem{
+		Location: location,
+		Name:     oldName,
+		Rule:     RuleAlias,
+		Message:  fmt.Sprintf("%q is %v, but deprecated: it is an alias of %q", oldName, verb, newName),
+	}
+}
@@ -0,0 +1,57 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_Aliases(t *testing.T) {
+	problems := []*Problem{}
+	s := NewSourcerWith(
+		WithInterpolate(true),
+		WithAlias("OLD", "NEW"),
+		WithAlias("A", "B"),
+		WithWarn(func(problem *Problem) {
+			problems = append(problems, problem)
+		}),
+	)
+	input := "export OLD=old\nC=${OLD}-$NEW\n"
+
+	nameVars, err := s.NameVars(strings.NewReader(input))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"NEW", "old"}, {"C", "old-old"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+	want := &Problem{Location{1, 8}, "OLD", RuleAlias, `"OLD" is defined, but deprecated: it is an alias of "NEW"`}
+	if len(problems) != 1 || !reflect.DeepEqual(problems[0], want) {
+		t.Errorf("problems = %v", problems)
+	}
+
+	problems = problems[:0]
+	env, err := s.Env(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := env.Names(); !reflect.DeepEqual(names, []string{"NEW", "C"}) {
+		t.Errorf("Names() = %v", names)
+	}
+	if v, err := env.Get("OLD"); v != "old" || err != nil {
+		t.Errorf("Get(OLD) = %v, %v", v, err)
+	}
+	if v, err := env.Get("C"); v != "old-old" || err != nil {
+		t.Errorf("Get(C) = %v, %v", v, err)
+	}
+	if _, err := env.Get("A"); !reflect.DeepEqual(err, ErrMissingVariables{"B"}) {
+		t.Errorf("Get(A) error = %v", err)
+	}
+	want = &Problem{Location{}, "OLD", RuleAlias, `"OLD" is read, but deprecated: it is an alias of "NEW"`}
+	if len(problems) != 3 || !reflect.DeepEqual(problems[1], want) || problems[2].Name != "A" {
+		t.Errorf("problems = %v", problems)
+	}
+
+	clone := s.Clone()
+	clone.Aliases["D"] = "E"
+	if _, ok := s.Aliases["D"]; ok {
+		t.Error("Clone must copy Aliases")
+	}
+}
@@ -0,0 +1,68 @@
+package dotenv
+
+//Reference is a reference to a variable in a value of a Document.
+type Reference struct {
+	//Name is the referenced name.
+	Name string
+
+	//Location is the location of the reference.
+	Location Location
+}
+
+//Analysis is the result of Document.Analyze.
+type Analysis struct {
+	//Unused contains the names defined in the Document that are neither used by
+	//the application nor referenced by any value, in the order of their first
+	//definition.
+	Unused []string
+
+	//Undefined contains every reference to a name that is not defined before the
+	//reference in the Document, in order. Such references are resolved against
+	//the process's environment when sourcing.
+	Undefined []Reference
+}
+
+//Analyze reports the defined but unused variables and the references to undefined
+//variables in d.
+//used contains the names the application reads from its environment. If used is
+//nil, then Unused is not computed and is empty.
+//References are only found if the Sourcer d was parsed with has Interpolate set.
+func (d *Document) Analyze(used []string) *Analysis {
+	analysis := &Analysis{Unused: []string{}, Undefined: []Reference{}}
+
+	isUsed := map[string]bool{}
+	for _, name := range used {
+		isUsed[name] = true
+	}
+	defined := map[string]bool{}
+	for i, line := range d.lines {
+		if !line.isVariable {
+			continue
+		}
+		if d.sourcer.Interpolate {
+			for _, ref := range references(line.rawValue, d.sourcer.InterpolatePercent) {
+				if ref.name == "" {
+					continue
+				}
+				//a variable referencing itself does not use its own definition.
+				if ref.name != line.name {
+					isUsed[ref.name] = true
+				}
+				if !defined[ref.name] {
+					location := Location{i + 1, line.valueColumn(ref.start)}
+					analysis.Undefined = append(analysis.Undefined, Reference{ref.name, location})
+				}
+			}
+		}
+		defined[line.name] = true
+	}
+
+	if used != nil {
+		for _, name := range d.Names() {
+			if !isUsed[name] {
+				analysis.Unused = append(analysis.Unused, name)
+			}
+		}
+	}
+	return analysis
+}
@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const analysisSource = `HOST=localhost
+URL=http://$HOST:$PORT/$$
+PORT=8080
+UNUSED=1
+SELF=$SELF:x
+`
+
+func TestDocument_Analyze(t *testing.T) {
+	s := NewDefault()
+	s.Interpolate = true
+	d, err := s.ParseDocument(strings.NewReader(analysisSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	analysis := d.Analyze([]string{"URL", "PORT"})
+	want := &Analysis{
+		Unused: []string{"UNUSED", "SELF"},
+		Undefined: []Reference{
+			{"PORT", Location{2, 18}},
+			{"SELF", Location{5, 6}},
+		},
+	}
+	if !reflect.DeepEqual(analysis, want) {
+		t.Errorf("analysis = %v WANT %v", analysis, want)
+	}
+
+	analysis = d.Analyze(nil)
+	want.Unused = []string{}
+	if !reflect.DeepEqual(analysis, want) {
+		t.Errorf("analysis = %v WANT %v", analysis, want)
+	}
+}
+
+func TestDocument_Analyze_noInterpolate(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader(analysisSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	analysis := d.Analyze([]string{"URL"})
+	want := &Analysis{
+		Unused:    []string{"HOST", "PORT", "UNUSED", "SELF"},
+		Undefined: []Reference{},
+	}
+	if !reflect.DeepEqual(analysis, want) {
+		t.Errorf("analysis = %v WANT %v", analysis, want)
+	}
+}
@@ -0,0 +1,12 @@
+#!/bin/sh
+
+#builds, vets, and tests each nested module, whose packages depend on external
+#modules. They are built with the enclosing dotenv module of go.work.
+
+retval=0
+
+for dir in metrics/prometheus trace/otel keychain; do
+    (cd ${dir} && go build ./... && go vet ./... && go test ./...) || retval=$?;
+done;
+
+exit $retval
@@ -0,0 +1,101 @@
+package dotenv
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+)
+
+//VariablesString is the same as Variables except that it parses all of input at
+//once. Lines are sliced from input instead of being copied, so the names and
+//values of the returned Variables share memory with input.
+//Unlike Variables, lines are not limited in length.
+//This is faster than Variables for tools that parse many inputs, such as linters
+//and indexers.
+func (s *Sourcer) VariablesString(input string) ([]*Variable, error) {
+	return s.variablesString(input, nil, "")
+}
+
+//VariablesReaderAt is the same as VariablesString for the size bytes of r, which
+//are read with a single copy, e.g. for an *os.File and the size of the file.
+//If r has a Name method, such as *os.File, then its result is used in the error
+//for binary input.
+//An error is returned if size is negative or does not fit in an int.
+func (s *Sourcer) VariablesReaderAt(r io.ReaderAt, size int64) ([]*Variable, error) {
+	if size < 0 || size > math.MaxInt {
+		return nil, fmt.Errorf("dotenv: invalid input size %d", size)
+	}
+	buf := &strings.Builder{}
+	buf.Grow(int(size))
+	if _, err := io.Copy(buf, io.NewSectionReader(r, 0, size)); err != nil {
+		return nil, err
+	}
+	return s.variablesString(buf.String(), r, "")
+}
+
+//variablesString returns all variable definitions in input with File set to
+//path. in is the source of input used for its name, and may be nil.
+func (s *Sourcer) variablesString(input string, in interface{}, path string) ([]*Variable, error) {
+	s, _, dialectErr := s.dialectFor(strings.NewReader(input))
+	if dialectErr != nil {
+		return nil, dialectErr
+	}
+	result := []*Variable{}
+	defined := map[string]string{}
+	err := scanString(input, in, func(lineNumber int, line string) error {
+		err := s.visitLine(context.Background(), lineNumber, line, defined, func(variable *Variable) error {
+			variable.File = path
+			result = append(result, variable)
+			return nil
+		})
+		if err != nil {
+			return &ErrSourcing{lineNumber, err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//scanString is the same as scanLines for input, except that lines are slices of
+//input. in is the source of input used for its name, and may be nil.
+func scanString(input string, in interface{}, fn func(lineNumber int, line string) error) error {
+	head := bufio.NewReaderSize(strings.NewReader(input), sniffLength)
+	if err := checkEncrypted(head); err != nil {
+		return err
+	}
+	offset := 0
+	for lineNumber := 1; len(input) > 0; lineNumber++ {
+		line := input
+		if i := strings.IndexByte(input, '\n'); i >= 0 {
+			line, input = input[:i], input[i+1:]
+		} else {
+			input = ""
+		}
+		if err := checkBinary(line, offset, in); err != nil {
+			return err
+		}
+		offset += len(line) + 1
+		line = strings.TrimSuffix(line, "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		if err := fn(lineNumber, line); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//namedInput is the name of an input that is not read through an *os.File.
+type namedInput string
+
+//Name returns n.
+func (n namedInput) Name() string {
+	return string(n)
+}
@@ -0,0 +1,118 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_VariablesString(t *testing.T) {
+	tests := []string{
+		"",
+		"\n",
+		"A=a",
+		"A=a\n",
+		"A=a\r\nB=\"b\" #comment\r\n\r\n#C=c\nD=d",
+		"\ufeffA=a\n\nB=b\n\n",
+		"A=a\nB",
+		"A=a\nB=\"b",
+		"\x00\x01",
+		"Salted__A=a",
+		"#dotenv-version: 2\nA = a\nB=${A}",
+	}
+	s := NewSourcerWith(WithVersionDirective())
+	for i, test := range tests {
+		want, wantErr := s.Variables(strings.NewReader(test))
+		variables, err := s.VariablesString(test)
+		if !reflect.DeepEqual(variables, want) || !reflect.DeepEqual(err, wantErr) {
+			t.Errorf("%v: variables, err = %v, %v want %v, %v", i, variables, err, want, wantErr)
+		}
+	}
+}
+
+func TestSourcer_VariablesString_longLine(t *testing.T) {
+	value := strings.Repeat("a", 100000)
+	variables, err := NewDefault().VariablesString("A=" + value + "\nB=b")
+	if err != nil || len(variables) != 2 || variables[0].Value != value || variables[1].Line != 2 {
+		t.Errorf("variables, err = %v, %v", len(variables), err)
+	}
+}
+
+func TestSourcer_VariablesReaderAt(t *testing.T) {
+	s := NewDefault()
+	variables, err := s.VariablesReaderAt(strings.NewReader("A=a\nB=b"), 7)
+	if err != nil || !reflect.DeepEqual(variables, []*Variable{
+		{Name: "A", Value: "a", Line: 1},
+		{Name: "B", Value: "b", Line: 2},
+	}) {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+
+	for _, size := range []int64{-1, math.MinInt64} {
+		if variables, err := s.VariablesReaderAt(strings.NewReader("A=a"), size); variables != nil || err == nil {
+			t.Errorf("VariablesReaderAt(%v) = %v, %v", size, variables, err)
+		}
+	}
+}
+
+func TestSourcer_VariablesReaderAt_file(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	files := map[string]string{"a.env": "A=a\nB=\"b\"\n", "empty.env": "", "binary.env": "\x00"}
+	for name, contents := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewDefault()
+
+	for name, contents := range files {
+		path := filepath.Join(dir, name)
+		want, wantErr := s.Variables(strings.NewReader(contents))
+		if wantErr != nil {
+			wantErr = &ErrBinaryInput{path}
+		}
+		file, err := os.Open(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		variables, err := s.VariablesReaderAt(file, int64(len(contents)))
+		file.Close()
+		if !reflect.DeepEqual(variables, want) || !reflect.DeepEqual(err, wantErr) {
+			t.Errorf("%v: variables, err = %v, %v want %v, %v", name, variables, err, want, wantErr)
+		}
+	}
+}
+
+func BenchmarkSourcer_Variables(b *testing.B) {
+	s := NewDefault()
+	source := benchmarkSource(10000)
+	b.SetBytes(int64(len(source)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Variables(strings.NewReader(source)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSourcer_VariablesString(b *testing.B) {
+	s := NewDefault()
+	source := benchmarkSource(10000)
+	b.SetBytes(int64(len(source)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.VariablesString(source); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+//DiskCache is an encrypted, on-disk cache of values fetched from remote sources.
+//It allows a process to start with recently fetched values when a backend is
+//briefly unavailable. See DiskCache.Resolver.
+//
+//Each value is stored in its own file in Dir, named by a hash of its namespace and
+//reference, and encrypted with AES-GCM using Key.
+type DiskCache struct {
+	//Dir is the directory that contains the cache files. It is created if it
+	//does not exist.
+	Dir string
+
+	//Key is the AES key used to encrypt cache files. It must be 16, 24, or 32
+	//bytes long.
+	Key []byte
+
+	//TTL is the duration for which a cached value is used without contacting
+	//the backend.
+	TTL time.Duration
+
+	//StaleIfError allows a cached value older than TTL to be used when the
+	//backend fails.
+	StaleIfError bool
+
+	//MaxStale is the maximum age beyond TTL of a value used by StaleIfError.
+	//Zero means no maximum.
+	MaxStale time.Duration
+
+	//OnError, if not nil, is called with every error reading or writing a cache
+	//file, other than a missing file, e.g. to log that caching is not working.
+	OnError func(err error)
+
+	//now returns the current time. It is time.Now if nil.
+	now func() time.Time
+}
+
+//ErrCacheKey is an error that occurs when a DiskCache's Key has an invalid length.
+var ErrCacheKey = errors.New("dotenv: cache key must be 16, 24, or 32 bytes")
+
+//diskCacheEntry is the decrypted contents of a cache file.
+type diskCacheEntry struct {
+	Value  string    `json:"value"`
+	Stored time.Time `json:"stored"`
+}
+
+//Resolver returns a Resolver that resolves references with resolver and caches
+//the results in c under namespace, which should be unique among the Resolvers
+//sharing c, e.g. the scheme resolver is registered with.
+//
+//A value cached less than c.TTL ago is returned without calling resolver.
+//Otherwise resolver is called, and a successful result is cached. If it fails
+//and c.StaleIfError is true, then a cached value that is not older than
+//c.TTL + c.MaxStale is returned instead of the error.
+//Errors reading or writing the cache are passed to c.OnError and otherwise
+//ignored, so that the cache never prevents resolution.
+//c.Key is validated once, and if it is invalid, then the returned Resolver
+//always returns ErrCacheKey without calling resolver.
+func (c *DiskCache) Resolver(namespace string, resolver Resolver) Resolver {
+	if _, err := c.aead(); err != nil {
+		return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+			return "", err
+		})
+	}
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		entry, age, cached, err := c.load(namespace, ref)
+		c.reportError(err)
+		if cached && age < c.TTL {
+			return entry.Value, nil
+		}
+		value, err := resolver.Resolve(ctx, ref)
+		if err == nil {
+			c.reportError(c.store(namespace, ref, value))
+			return value, nil
+		}
+		if cached && c.StaleIfError && (c.MaxStale == 0 || age < c.TTL+c.MaxStale) {
+			return entry.Value, nil
+		}
+		return "", err
+	})
+}
+
+//reportError calls c.OnError with err if both are not nil.
+func (c *DiskCache) reportError(err error) {
+	if err != nil && c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+//path returns the path of the cache file for ref in namespace.
+func (c *DiskCache) path(namespace, ref string) string {
+	sum := sha256.Sum256([]byte(namespace + "\x00" + ref))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+//aead returns the AEAD cipher for c.Key.
+func (c *DiskCache) aead() (cipher.AEAD, error) {
+	block, err := aes.NewCipher(c.Key)
+	if err != nil {
+		return nil, ErrCacheKey
+	}
+	return cipher.NewGCM(block)
+}
+
+//load returns the cached entry for ref in namespace and its age.
+//ok is false if there is no such entry or it cannot be read, in which case err
+//is the error that occurred, unless the entry does not exist.
+func (c *DiskCache) load(namespace, ref string) (entry *diskCacheEntry, age time.Duration, ok bool, err error) {
+	aead, err := c.aead()
+	if err != nil {
+		return nil, 0, false, err
+	}
+	path := c.path(namespace, ref)
+	sealed, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, 0, false, nil
+	}
+	if err != nil {
+		return nil, 0, false, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, 0, false, fmt.Errorf("dotenv: cache file %v is truncated", path)
+	}
+	nonce, sealed := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	//the reference is authenticated so that files cannot be swapped.
+	plain, err := aead.Open(nil, nonce, sealed, []byte(namespace+"\x00"+ref))
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("dotenv: cache file %v: %w", path, err)
+	}
+	entry = &diskCacheEntry{}
+	if err := json.Unmarshal(plain, entry); err != nil {
+		return nil, 0, false, fmt.Errorf("dotenv: cache file %v: %w", path, err)
+	}
+	return entry, c.timeNow().Sub(entry.Stored), true, nil
+}
+
+//store caches value for ref in namespace.
+func (c *DiskCache) store(namespace, ref, value string) error {
+	aead, err := c.aead()
+	if err != nil {
+		return err
+	}
+	plain, err := json.Marshal(&diskCacheEntry{value, c.timeNow()})
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	sealed := aead.Seal(nonce, nonce, plain, []byte(namespace+"\x00"+ref))
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return err
+	}
+	path := c.path(namespace, ref)
+	temp := path + ".tmp"
+	if err := ioutil.WriteFile(temp, sealed, 0600); err != nil {
+		return err
+	}
+	return os.Rename(temp, path)
+}
+
+//timeNow returns the current time.
+func (c *DiskCache) timeNow() time.Time {
+	if c.now == nil {
+		return time.Now()
+	}
+	return c.now()
+}
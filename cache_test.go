@@ -0,0 +1,122 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDiskCache_Resolver(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	cache := &DiskCache{
+		Dir:          filepath.Join(dir, "cache"),
+		Key:          bytes.Repeat([]byte{1}, 32),
+		TTL:          time.Minute,
+		StaleIfError: true,
+		MaxStale:     time.Hour,
+		now:          func() time.Time { return now },
+	}
+	calls := 0
+	var backendErr error
+	resolver := cache.Resolver("vault", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		calls++
+		return "secret-" + ref, backendErr
+	}))
+	resolve := func() (string, error) {
+		return resolver.Resolve(context.Background(), "db")
+	}
+
+	if value, err := resolve(); value != "secret-db" || err != nil || calls != 1 {
+		t.Errorf("Resolve() = %q, %v, calls = %v", value, err, calls)
+	}
+	files, _ := ioutil.ReadDir(cache.Dir)
+	if len(files) != 1 {
+		t.Fatalf("files = %v", files)
+	}
+	contents, _ := ioutil.ReadFile(filepath.Join(cache.Dir, files[0].Name()))
+	if bytes.Contains(contents, []byte("secret-db")) {
+		t.Error("cache file must be encrypted")
+	}
+
+	now = now.Add(30 * time.Second)
+	if value, _ := resolve(); value != "secret-db" || calls != 1 {
+		t.Error("fresh value must be served from the cache")
+	}
+
+	now = now.Add(time.Minute)
+	backendErr = errors.New("unavailable")
+	if value, err := resolve(); value != "secret-db" || err != nil || calls != 2 {
+		t.Errorf("stale Resolve() = %q, %v, calls = %v", value, err, calls)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, err := resolve(); err != backendErr {
+		t.Errorf("err = %v, want too stale", err)
+	}
+
+	other := &DiskCache{Dir: cache.Dir, Key: bytes.Repeat([]byte{2}, 32), StaleIfError: true}
+	otherResolver := other.Resolver("vault", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return "", backendErr
+	}))
+	if _, err := otherResolver.Resolve(context.Background(), "db"); err != backendErr {
+		t.Error("cache files must not be readable with another key")
+	}
+}
+
+func TestDiskCache_invalidKey(t *testing.T) {
+	cache := &DiskCache{Dir: "unused", Key: []byte("short"), TTL: time.Hour}
+	if err := cache.store("ns", "ref", "value"); err != ErrCacheKey {
+		t.Errorf("err = %v", err)
+	}
+	calls := 0
+	resolver := cache.Resolver("ns", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		calls++
+		return "value", nil
+	}))
+	if value, err := resolver.Resolve(context.Background(), "ref"); value != "" || err != ErrCacheKey || calls != 0 {
+		t.Errorf("Resolve() = %q, %v, calls = %v", value, err, calls)
+	}
+}
+
+func TestDiskCache_OnError(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "file")
+	if err := ioutil.WriteFile(file, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	errs := []error{}
+	cache := &DiskCache{
+		Dir:     filepath.Join(file, "cache"),
+		Key:     bytes.Repeat([]byte{1}, 32),
+		TTL:     time.Hour,
+		OnError: func(err error) { errs = append(errs, err) },
+	}
+	resolver := cache.Resolver("ns", ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return "value", nil
+	}))
+	if value, err := resolver.Resolve(context.Background(), "ref"); value != "value" || err != nil {
+		t.Errorf("Resolve() = %q, %v", value, err)
+	}
+	//both loading and storing fail, since Dir is below a regular file.
+	if len(errs) != 2 {
+		t.Errorf("errs = %v", errs)
+	}
+}
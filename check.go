@@ -0,0 +1,179 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+//Rules of the Problems reported by Checker.Check in addition to those reported by
+//its Linters.
+const (
+	RuleParse               = "parse"
+	RuleSchemaRequired      = "schema-required"
+	RuleSchemaInvalid       = "schema-invalid"
+	RuleExampleMissing      = "example-missing"
+	RuleExampleUndocumented = "example-undocumented"
+)
+
+//Checker verifies files without sourcing them. It is intended to be the single
+//gate for environment file hygiene in continuous integration.
+//All fields other than Sourcer are optional and enable their checks when set.
+type Checker struct {
+	//Sourcer is used to parse files. If it is nil, then NewDefault() is used.
+	Sourcer *Sourcer
+
+	//Schema is used to check that required variables are defined and that values
+	//are valid.
+	Schema *Schema
+
+	//Example is the path of an example file, such as .env.example, that must
+	//define the same names as checked files.
+	Example string
+
+	//Linters are run on checked files.
+	Linters []Linter
+}
+
+//CheckReport is the machine readable result of Checker.Check. It is an error so
+//that it can be returned from Check, and it can be encoded as JSON.
+type CheckReport struct {
+	//Path is the path of the checked file.
+	Path string `json:"path"`
+
+	//Problems contains all problems found, sorted by location. Problems that do
+	//not concern a line in the checked file, such as missing variables, have the
+	//zero Location and appear first.
+	Problems []*Problem `json:"problems"`
+}
+
+//Error is the error implementation for CheckReport.
+func (r *CheckReport) Error() string {
+	return fmt.Sprintf("dotenv: %v has %v problem(s)", r.Path, len(r.Problems))
+}
+
+//Check parses the file at path and checks it against all of c's checks.
+//All lines are parsed even if some cannot be, and each line error is reported as
+//a Problem with the Rule RuleParse.
+//If any problems are found, then a *CheckReport is returned. If the file at path
+//or c.Example cannot be read or c.Example cannot be parsed, then that error is
+//returned.
+//Otherwise, the returned error is nil.
+func (c *Checker) Check(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return c.check(path, file)
+}
+
+//check checks the contents of the file at path, which are read from in, as
+//described by Check.
+func (c *Checker) check(path string, in io.Reader) error {
+	s := c.Sourcer
+	if s == nil {
+		s = NewDefault()
+	}
+	report := &CheckReport{Path: path, Problems: []*Problem{}}
+
+	doc, err := s.parseDocument(in, func(lineError *ErrSourcing) error {
+		report.Problems = append(report.Problems, &Problem{
+			Location: Location{lineError.Line, 1},
+			Rule:     RuleParse,
+			Message:  lineError.LineError.Error(),
+		})
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	linters := append([]Linter{}, c.Linters...)
+	if c.Schema != nil {
+		linters = append(linters, c.lintSchema)
+	}
+	if c.Example != "" {
+		exampleLinter, err := c.exampleLinter(s)
+		if err != nil {
+			return err
+		}
+		linters = append(linters, exampleLinter)
+	}
+	report.Problems = append(report.Problems, doc.Lint(linters...)...)
+	if len(report.Problems) == 0 {
+		return nil
+	}
+	sortProblems(report.Problems)
+	return report
+}
+
+//lintSchema is a Linter that checks d against c.Schema.
+func (c *Checker) lintSchema(d *Document) []*Problem {
+	problems := []*Problem{}
+	for _, v := range c.Schema.Vars {
+		i := d.index(v.Name)
+		if i < 0 {
+			if v.Required && !v.HasDefault {
+				problems = append(problems, &Problem{
+					Name:    v.Name,
+					Rule:    RuleSchemaRequired,
+					Message: fmt.Sprintf("required variable %q is not defined", v.Name),
+				})
+			}
+			continue
+		}
+		line := d.lines[i]
+		if err := v.Check(line.value); err != nil {
+			problems = append(problems, &Problem{
+				Location: Location{i + 1, line.valueColumn(0)},
+				Name:     v.Name,
+				Rule:     RuleSchemaInvalid,
+				Message:  err.(*ErrInvalidValue).Err.Error(),
+			})
+		}
+	}
+	return problems
+}
+
+//exampleLinter returns a Linter that compares the names defined in a Document
+//with those defined in c.Example.
+func (c *Checker) exampleLinter(s *Sourcer) (Linter, error) {
+	file, err := os.Open(c.Example)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	example, err := s.ParseDocument(file)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(d *Document) []*Problem {
+		problems := []*Problem{}
+		for _, name := range example.Names() {
+			if d.index(name) < 0 {
+				problems = append(problems, &Problem{
+					Name:    name,
+					Rule:    RuleExampleMissing,
+					Message: fmt.Sprintf("%q is defined in %v but not here", name, c.Example),
+				})
+			}
+		}
+		documented := map[string]bool{}
+		for _, name := range example.Names() {
+			documented[name] = true
+		}
+		for i, line := range d.lines {
+			if line.isVariable && !documented[line.name] && d.index(line.name) == i {
+				problems = append(problems, &Problem{
+					Location: Location{i + 1, line.nameOffset + 1},
+					Name:     line.name,
+					Rule:     RuleExampleUndocumented,
+					Message:  fmt.Sprintf("%q is not defined in %v", line.name, c.Example),
+				})
+			}
+		}
+		return problems
+	}, nil
+}
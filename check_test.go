@@ -0,0 +1,97 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCheckReport_Error(t *testing.T) {
+	report := &CheckReport{".env", []*Problem{{}, {}}}
+	if report.Error() != "dotenv: .env has 2 problem(s)" {
+		t.Errorf("report.Error() = %q", report.Error())
+	}
+}
+
+func TestChecker_Check_clean(t *testing.T) {
+	paths := writeLayerFiles(t, "PORT=8080\n", "PORT=3000\n")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	c := &Checker{
+		Schema:  &Schema{Vars: []*SchemaVar{{Name: "PORT", Type: TypeInt, Required: true}}},
+		Example: paths[1],
+		Linters: []Linter{NewEntropyDetector().Lint},
+	}
+	if err := c.Check(paths[0]); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestChecker_Check_problems(t *testing.T) {
+	paths := writeLayerFiles(t,
+		"PORT=http\nbad line\nEXTRA=1\nSECRET=wJalrXUtnFEMI/K7MDENG/bPxRfiCYEXAMPLEKEY\n",
+		"PORT=3000\nNAME=gopher\nSECRET=\n",
+	)
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	c := &Checker{
+		Schema: &Schema{Vars: []*SchemaVar{
+			{Name: "PORT", Type: TypeInt},
+			{Name: "NAME", Type: TypeString, Required: true},
+		}},
+		Example: paths[1],
+		Linters: []Linter{NewEntropyDetector().Lint},
+	}
+	err := c.Check(paths[0])
+	report, ok := err.(*CheckReport)
+	if !ok {
+		t.Fatalf("err = %v", err)
+	}
+	if report.Path != paths[0] {
+		t.Fail()
+	}
+	rules := []string{}
+	for _, problem := range report.Problems {
+		rules = append(rules, problem.Name+" "+problem.Rule)
+	}
+	want := []string{
+		"NAME schema-required",
+		"NAME example-missing",
+		"PORT schema-invalid",
+		" parse",
+		"EXTRA example-undocumented",
+		"SECRET high-entropy",
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules = %v WANT %v", rules, want)
+	}
+
+	encoded, err := json.Marshal(report.Problems[3])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(encoded) != `{"location":{"line":2,"column":1},"rule":"parse","message":"line does not contain a variable definition \"bad line\""}` {
+		t.Errorf("encoded = %s", encoded)
+	}
+}
+
+func TestChecker_Check_errors(t *testing.T) {
+	paths := writeLayerFiles(t, "A=1\n", "bad line\n")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	if err := (&Checker{}).Check(paths[0] + ".missing"); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+	if err := (&Checker{Example: paths[0] + ".missing"}).Check(paths[0]); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+	err := (&Checker{Example: paths[1]}).Check(paths[0])
+	if _, ok := err.(*ErrSourcing); !ok {
+		t.Errorf("err = %v", err)
+	}
+	if err := (&Checker{}).Check(paths[0]); err != nil {
+		t.Error(err)
+	}
+}
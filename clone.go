@@ -0,0 +1,110 @@
+package dotenv
+
+import (
+	"context"
+	"io"
+)
+
+//Clone returns a copy of s that can be changed without affecting s.
+//The Resolvers and Aliases maps are copied. Functions and interfaces, such as
+//TransformValue, Resolvers, and Metrics, are shared, so they must themselves be
+//safe for concurrent use if the copies are used concurrently.
+func (s *Sourcer) Clone() *Sourcer {
+	clone := *s
+	if s.Resolvers != nil {
+		clone.Resolvers = make(map[string]Resolver, len(s.Resolvers))
+		for scheme, resolver := range s.Resolvers {
+			clone.Resolvers[scheme] = resolver
+		}
+	}
+	if s.Aliases != nil {
+		clone.Aliases = make(map[string]string, len(s.Aliases))
+		for oldName, newName := range s.Aliases {
+			clone.Aliases[oldName] = newName
+		}
+	}
+	return &clone
+}
+
+//FrozenSourcer is an immutable Sourcer configuration.
+//Since its configuration cannot change, a FrozenSourcer is safe for concurrent
+//use by multiple goroutines, provided that the functions and interfaces it was
+//configured with are. Use With to derive a changed Sourcer for a single call.
+type FrozenSourcer struct {
+	sourcer *Sourcer
+}
+
+//Freeze returns a FrozenSourcer with a copy of the current configuration of s.
+//Later changes to s do not affect the FrozenSourcer.
+func (s *Sourcer) Freeze() *FrozenSourcer {
+	return &FrozenSourcer{s.Clone()}
+}
+
+//Sourcer returns a copy of the configuration of f that can be changed.
+func (f *FrozenSourcer) Sourcer() *Sourcer {
+	return f.sourcer.Clone()
+}
+
+//With returns a copy of the configuration of f that is changed by opts.
+func (f *FrozenSourcer) With(opts ...Option) *Sourcer {
+	s := f.Sourcer()
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+//Source is the same as Sourcer.Source.
+func (f *FrozenSourcer) Source(in io.Reader, opts ...Option) error {
+	return f.sourcer.Source(in, opts...)
+}
+
+//SourceFile is the same as Sourcer.SourceFile.
+func (f *FrozenSourcer) SourceFile(path string, opts ...Option) error {
+	return f.sourcer.SourceFile(path, opts...)
+}
+
+//SourceFileContext is the same as Sourcer.SourceFileContext.
+func (f *FrozenSourcer) SourceFileContext(ctx context.Context, path string, opts ...Option) error {
+	return f.sourcer.SourceFileContext(ctx, path, opts...)
+}
+
+//NameVars is the same as Sourcer.NameVars.
+func (f *FrozenSourcer) NameVars(in io.Reader, opts ...Option) ([][2]string, error) {
+	return f.sourcer.NameVars(in, opts...)
+}
+
+//Visit is the same as Sourcer.Visit.
+func (f *FrozenSourcer) Visit(in io.Reader, visit func(variable *Variable) error) error {
+	return f.sourcer.Visit(in, visit)
+}
+
+//LookupIn is the same as Sourcer.LookupIn.
+func (f *FrozenSourcer) LookupIn(in io.Reader, name string) (string, bool, error) {
+	return f.sourcer.LookupIn(in, name)
+}
+
+//LookupLastIn is the same as Sourcer.LookupLastIn.
+func (f *FrozenSourcer) LookupLastIn(in io.Reader, name string) (string, bool, error) {
+	return f.sourcer.LookupLastIn(in, name)
+}
+
+//Variables is the same as Sourcer.Variables.
+func (f *FrozenSourcer) Variables(in io.Reader, opts ...Option) ([]*Variable, error) {
+	return f.sourcer.Variables(in, opts...)
+}
+
+//VariablesFile is the same as Sourcer.VariablesFile.
+func (f *FrozenSourcer) VariablesFile(path string, opts ...Option) ([]*Variable, error) {
+	return f.sourcer.VariablesFile(path, opts...)
+}
+
+//Env is the same as Sourcer.Env.
+func (f *FrozenSourcer) Env(in io.Reader) (*Env, error) {
+	return f.sourcer.Env(in)
+}
+
+//ParseDocument is the same as Sourcer.ParseDocument.
+func (f *FrozenSourcer) ParseDocument(in io.Reader) (*Document, error) {
+	return f.sourcer.ParseDocument(in)
+}
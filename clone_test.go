@@ -0,0 +1,82 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestSourcer_Clone(t *testing.T) {
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"file": ResolveFile}
+	clone := s.Clone()
+	clone.Comment = ";"
+	clone.Resolvers["counting"] = &countingResolver{}
+	if s.Comment != DefaultComment || len(s.Resolvers) != 1 || len(clone.Resolvers) != 2 {
+		t.Errorf("s = %v", s)
+	}
+	if NewDefault().Clone().Resolvers != nil {
+		t.Error("Clone() must keep nil Resolvers nil")
+	}
+}
+
+func TestSourcer_Freeze(t *testing.T) {
+	s := NewDefault()
+	f := s.Freeze()
+	s.Comment = ";"
+
+	source := "A=a # comment\nB=${A}"
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			nameVars, err := f.NameVars(strings.NewReader(source))
+			if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}, {"B", "${A}"}}) {
+				t.Errorf("nameVars, err = %v, %v", nameVars, err)
+			}
+			nameVars, err = f.With(WithInterpolate(false)).NameVars(strings.NewReader(source))
+			if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}, {"B", "a"}}) {
+				t.Errorf("nameVars, err = %v, %v", nameVars, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	changed := f.Sourcer()
+	changed.Comment = ""
+	if f.sourcer.Comment != DefaultComment {
+		t.Error("Sourcer() must return a copy")
+	}
+}
+
+func TestFrozenSourcer_methods(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_FROZEN_A=a\n")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	defer os.Unsetenv("GOGOLFING_DOTENV_FROZEN_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_FROZEN_B")
+	f := NewDefault().Freeze()
+
+	if err := f.SourceFile(paths[0]); err != nil || os.Getenv("GOGOLFING_DOTENV_FROZEN_A") != "a" {
+		t.Errorf("SourceFile() = %v", err)
+	}
+	if err := f.Source(strings.NewReader("GOGOLFING_DOTENV_FROZEN_B=b")); err != nil || os.Getenv("GOGOLFING_DOTENV_FROZEN_B") != "b" {
+		t.Errorf("Source() = %v", err)
+	}
+	if variables, err := f.VariablesFile(paths[0]); err != nil || len(variables) != 1 || variables[0].File != paths[0] {
+		t.Errorf("VariablesFile() = %v, %v", variables, err)
+	}
+	if variables, err := f.Variables(strings.NewReader("A=a")); err != nil || len(variables) != 1 {
+		t.Errorf("Variables() = %v, %v", variables, err)
+	}
+	if env, err := f.Env(strings.NewReader("A=a")); err != nil || len(env.Names()) != 1 {
+		t.Errorf("Env() = %v, %v", env, err)
+	}
+	if d, err := f.ParseDocument(strings.NewReader("A=a")); err != nil || d.String() != "A=a\n" {
+		t.Errorf("ParseDocument() = %v, %v", d, err)
+	}
+}
@@ -0,0 +1,73 @@
+//Command dotenv-gen generates a typed Go configuration struct from an annotated
+//.env.example file. See dotenv.Document.Schema() for the annotation syntax and
+//dotenv.GenerateConfig() for the generated declarations.
+//With -format markdown or -format jsonschema, it instead generates a Markdown
+//reference table with dotenv.GenerateMarkdown() or a JSON Schema with
+//dotenv.GenerateJSONSchema().
+//
+//It is intended to be run by go generate, e.g.
+//
+//	//go:generate dotenv-gen -in .env.example -out config_gen.go
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func main() {
+	in := flag.String("in", ".env.example", "path of the annotated input file")
+	out := flag.String("out", "", "path of the generated file; standard output if empty")
+	pkg := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file")
+	typeName := flag.String("type", dotenv.DefaultGenerateType, "name of the generated struct type")
+	format := flag.String("format", "go", "output format: go, markdown, or jsonschema")
+	flag.Parse()
+
+	options := &dotenv.GenerateOptions{Package: *pkg, Type: *typeName, Generator: "dotenv-gen"}
+	if err := run(*in, *out, *format, options); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out, format string, options *dotenv.GenerateOptions) error {
+	if format != "go" && format != "markdown" && format != "jsonschema" {
+		return fmt.Errorf("unknown format %q", format)
+	}
+	file, err := os.Open(in)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	doc, err := dotenv.NewDefault().ParseDocument(file)
+	if err != nil {
+		return err
+	}
+	schema, err := doc.Schema()
+	if err != nil {
+		return err
+	}
+
+	buf := &bytes.Buffer{}
+	switch format {
+	case "markdown":
+		err = dotenv.GenerateMarkdown(buf, schema)
+	case "jsonschema":
+		err = dotenv.GenerateJSONSchema(buf, schema)
+	default:
+		err = dotenv.GenerateConfig(buf, schema, options)
+	}
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return ioutil.WriteFile(out, buf.Bytes(), 0644)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["add"] = &command{
+		usage:   "NAME [-preset name] [-type type] [-required] [-secret] [-default value] [-pattern regexp] [-desc text] [-example value] [file ...]",
+		summary: "add a documented variable to .env and .env.example",
+		run:     runAdd,
+	}
+}
+
+//runAdd adds a documented variable stub to files, the project's .env and schema
+//file by default.
+func runAdd(args []string) error {
+	flags := newFlagSet("add")
+	preset := presetFlag(flags, "files")
+	typ := flags.String("type", string(dotenv.TypeString), "the type of the value, one of string, int, float, bool, duration, or url")
+	required := flags.Bool("required", false, "the variable must be defined")
+	secret := flags.Bool("secret", false, "the value is a secret")
+	def := flags.String("default", "", "the default value")
+	pattern := flags.String("pattern", "", "the regular expression values must match")
+	desc := flags.String("desc", "", "the description of the variable")
+	example := flags.String("example", "", "the value written to the files")
+	//NAME may precede the flags, as in dotenv add NAME -type url.
+	name := ""
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	flags.Parse(args)
+	files := flags.Args()
+	if name == "" && len(files) > 0 {
+		name, files = files[0], files[1:]
+	}
+	if name == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if len(files) == 0 {
+		schema := ".env.example"
+		if project.Schema != "" {
+			schema = project.Schema
+		}
+		files = []string{project.Path(".env"), project.Path(schema)}
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	v := &dotenv.SchemaVar{
+		Name:        name,
+		Type:        dotenv.Type(*typ),
+		Required:    *required,
+		Default:     *def,
+		HasDefault:  isFlagSet(flags, "default"),
+		Pattern:     *pattern,
+		Secret:      *secret,
+		Description: *desc,
+		Example:     *example,
+	}
+	//a document with only v checks its annotations as Document.Schema would.
+	d, err := s.ParseDocument(strings.NewReader(""))
+	if err != nil {
+		return err
+	}
+	if err := d.Add(v); err != nil {
+		return err
+	}
+	if _, err := d.Schema(); err != nil {
+		return err
+	}
+	return s.AddFiles(v, files...)
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+func init() {
+	commands["completion"] = &command{
+		usage:   "bash|zsh|fish",
+		summary: "print the shell completion script for dotenv",
+		run:     runCompletion,
+	}
+	//__complete is called by the completion scripts and is not listed.
+	hiddenCommands["__complete"] = &command{
+		usage:   "word ...",
+		summary: "print the completions of the last word of a command line",
+		run:     runComplete,
+	}
+}
+
+//completionScripts contains the completion scripts by shell. They call dotenv
+//__complete with the words following dotenv, and complete files if it prints
+//nothing.
+var completionScripts = map[string]string{
+	"bash": `_dotenv() {
+	local IFS=$'\n'
+	COMPREPLY=($(dotenv __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+	if [ ${#COMPREPLY[@]} -eq 0 ]; then
+		COMPREPLY=($(compgen -f -- "${COMP_WORDS[COMP_CWORD]}"))
+	fi
+}
+complete -F _dotenv dotenv
+`,
+	"zsh": `#compdef dotenv
+_dotenv() {
+	local -a candidates
+	candidates=(${(f)"$(dotenv __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+	if (( ${#candidates} )); then
+		compadd -- $candidates
+	else
+		_files
+	fi
+}
+compdef _dotenv dotenv
+`,
+	"fish": `function __dotenv_complete
+	dotenv __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c dotenv -a '(__dotenv_complete)'
+`,
+}
+
+//nameArgs contains the number of leading arguments that are variable names by
+//command.
+var nameArgs = map[string]int{
+	"get":    1,
+	"rename": 2,
+}
+
+//runCompletion prints the completion script for a shell.
+func runCompletion(args []string) error {
+	flags := newFlagSet("completion")
+	flags.Parse(args)
+	script, ok := completionScripts[flags.Arg(0)]
+	if flags.NArg() != 1 || !ok {
+		flags.Usage()
+		os.Exit(2)
+	}
+	fmt.Print(script)
+	return nil
+}
+
+//runComplete prints the completions of the last of args, which are the words
+//of a command line following dotenv, as written by complete.
+func runComplete(args []string) error {
+	return complete(os.Stdout, args)
+}
+
+//complete writes the completions of the last of args, which are the words of a
+//command line following dotenv, to w, one per line. The first word completes to
+//command names, and the name arguments of commands complete to the names of the
+//project's variables. Nothing is written for other words, so that the shell
+//completes files instead.
+func complete(w io.Writer, args []string) error {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	word := args[len(args)-1]
+	candidates := []string{}
+	if len(args) == 1 {
+		for name := range commands {
+			candidates = append(candidates, name)
+		}
+	} else if arg := countPositional(args[1 : len(args)-1]); arg < nameArgs[args[0]] && !strings.HasPrefix(word, "-") {
+		completions, err := project.Completions("")
+		if err != nil {
+			return err
+		}
+		for _, completion := range completions {
+			candidates = append(candidates, completion.Name)
+		}
+	}
+	sort.Strings(candidates)
+	for _, candidate := range candidates {
+		if strings.HasPrefix(candidate, word) {
+			fmt.Fprintln(w, candidate)
+		}
+	}
+	return nil
+}
+
+//countPositional returns the number of positional arguments in args, which are
+//the words following a command. Flags are assumed to be given as -flag or
+//-flag=value, except for -preset, which takes the following word as its value.
+func countPositional(args []string) int {
+	n := 0
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "-preset" || args[i] == "--preset":
+			i++
+		case strings.HasPrefix(args[i], "-"):
+		default:
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func TestCountPositional(t *testing.T) {
+	tests := []struct {
+		args []string
+		want int
+	}{
+		{nil, 0},
+		{[]string{"A"}, 1},
+		{[]string{"A", "B", ".env"}, 3},
+		{[]string{"-n", "A"}, 1},
+		{[]string{"-preset", "files", "A"}, 1},
+		{[]string{"--preset", "files"}, 0},
+		{[]string{"-preset=files", "A"}, 1},
+		{[]string{"A", "-preset"}, 1},
+	}
+	for _, test := range tests {
+		if n := countPositional(test.args); n != test.want {
+			t.Errorf("countPositional(%q) = %v WANT %v", test.args, n, test.want)
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("DB_HOST=h\nDB_PORT=1\nAPI_URL=u\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if project, err = dotenv.FindProject(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer func() { project = nil }()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"r"}, []string{"rename", "rotate", "run"}},
+		{[]string{"ren"}, []string{"rename"}},
+		{[]string{"rotate"}, []string{"rotate"}},
+		{[]string{"get", ""}, []string{"API_URL", "DB_HOST", "DB_PORT"}},
+		{[]string{"get", "DB_"}, []string{"DB_HOST", "DB_PORT"}},
+		{[]string{"get", "-preset", "files", "DB_H"}, []string{"DB_HOST"}},
+		{[]string{"get", "DB_HOST", ""}, nil},
+		{[]string{"get", "-"}, nil},
+		{[]string{"rename", "DB_HOST", "API"}, []string{"API_URL"}},
+		{[]string{"rename", "DB_HOST", "NEW", ""}, nil},
+		{[]string{"rotate", ""}, nil},
+	}
+	for _, test := range tests {
+		out := &strings.Builder{}
+		if err := complete(out, test.args); err != nil {
+			t.Errorf("complete(%q) err = %v", test.args, err)
+		}
+		if got := strings.Fields(out.String()); strings.Join(got, " ") != strings.Join(test.want, " ") {
+			t.Errorf("complete(%q) = %q WANT %q", test.args, got, test.want)
+		}
+	}
+}
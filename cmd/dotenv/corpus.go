@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["corpus"] = &command{
+		usage:   "[-preset name] [-out dir] dir",
+		summary: "parse a directory of fixtures into normalized JSON results",
+		run:     runCorpus,
+	}
+}
+
+//runCorpus parses the fixtures in a directory and writes their results as JSON,
+//either as lines to standard output or as a file per fixture.
+func runCorpus(args []string) error {
+	flags := newFlagSet("corpus")
+	preset := presetFlag(flags, "fixtures")
+	out := flags.String("out", "", "write the result of each fixture to `dir`, replacing its .env suffix with .json")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	results, err := s.ParseCorpus(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetEscapeHTML(false)
+		for _, result := range results {
+			if err := encoder.Encode(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, result := range results {
+		if err := writeCorpusResult(*out, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//writeCorpusResult writes result as indented JSON to its file within dir.
+func writeCorpusResult(dir string, result *dotenv.CorpusResult) error {
+	path := filepath.Join(dir, filepath.FromSlash(strings.TrimSuffix(result.File, ".env")+".json"))
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["debug"] = &command{
+		usage:   "[-preset name] [-environ] [-reveal] [file ...]",
+		summary: "print the effective variables with their origins",
+		run:     runDebug,
+	}
+}
+
+//runDebug prints the effective variables from sourcing files, those of the
+//project by default, with secret looking values masked.
+func runDebug(args []string) error {
+	flags := newFlagSet("debug")
+	preset := presetFlag(flags, "files")
+	environ := flags.Bool("environ", false, "include variables from the process's environment")
+	reveal := flags.Bool("reveal", false, "do not mask secret looking values")
+	flags.Parse(args)
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	files := flags.Args()
+	if len(files) == 0 {
+		files = project.Paths()
+	}
+	return dotenv.DumpEffective(os.Stdout, &dotenv.DumpOptions{
+		Sourcer: s,
+		Files:   files,
+		Environ: *environ,
+		Reveal:  *reveal,
+	})
+}
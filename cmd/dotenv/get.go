@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["get"] = &command{
+		usage:   "[-preset name] NAME",
+		summary: "print the effective value of a variable of the project",
+		run:     runGet,
+	}
+}
+
+//runGet prints the effective value of a variable from the layered files of the
+//project.
+func runGet(args []string) error {
+	flags := newFlagSet("get")
+	preset := presetFlag(flags, "files")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	paths, err := project.ExistingPaths()
+	if err != nil {
+		return err
+	}
+	env, err := s.EnvFiles(paths...)
+	if err != nil {
+		return err
+	}
+	value, err := env.Get(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	fmt.Println(value)
+	return nil
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["grep-value"] = &command{
+		usage:   "[-preset name] [-reveal] needle [file ...]",
+		summary: "list the variables whose values contain a string",
+		run:     runGrepValue,
+	}
+}
+
+//runGrepValue prints the variables of files, those of the project by default,
+//whose values contain a needle, with the rest of their values masked.
+func runGrepValue(args []string) error {
+	flags := newFlagSet("grep-value")
+	preset := presetFlag(flags, "files")
+	reveal := flags.Bool("reveal", false, "do not mask the parts of values that do not match")
+	flags.Parse(args)
+	if flags.NArg() < 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	needle, files := flags.Arg(0), flags.Args()[1:]
+	if len(files) == 0 {
+		files = project.Paths()
+	}
+	for _, path := range files {
+		file, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		env, err := s.Env(file)
+		file.Close()
+		if err != nil {
+			return fmt.Errorf("%v: %v", path, err)
+		}
+		for _, name := range dotenv.FindValue(env, needle) {
+			value, _ := env.Get(name)
+			if !*reveal {
+				value = dotenv.MaskExcept(value, needle)
+			}
+			fmt.Printf("%v:%v=%v\n", path, name, value)
+		}
+	}
+	return nil
+}
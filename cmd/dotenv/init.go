@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["init"] = &command{
+		usage:   "[-preset name] [-schema path] [file]",
+		summary: "prompt for the variables a file is missing from the schema",
+		run:     runInit,
+	}
+}
+
+//runInit prompts for the values of the variables that are documented in the
+//schema, but not defined in file, and adds them to file.
+func runInit(args []string) error {
+	flags := newFlagSet("init")
+	preset := presetFlag(flags, "files")
+	schemaPath := flags.String("schema", "", "the file whose comments define the schema, the project's schema or .env.example by default")
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	path := project.Path(".env")
+	if flags.NArg() == 1 {
+		path = flags.Arg(0)
+	}
+	if *schemaPath == "" {
+		*schemaPath = project.Path(".env.example")
+		if project.Schema != "" {
+			*schemaPath = project.Path(project.Schema)
+		}
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	schema, err := readSchema(s, *schemaPath)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	d, err := s.ParseDocument(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("%v: %w", path, err)
+	}
+
+	missing := schema.Missing(d)
+	if len(missing) == 0 {
+		fmt.Fprintf(os.Stderr, "%v defines all variables of %v\n", path, *schemaPath)
+		return nil
+	}
+	in := bufio.NewReader(os.Stdin)
+	for _, v := range missing {
+		value, ok, err := prompt(in, v)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+		d.Set(v.Name, value)
+		if v.Description != "" {
+			d.SetComment(v.Name, v.Description)
+		}
+	}
+	return os.WriteFile(path, []byte(d.String()), 0600)
+}
+
+//readSchema parses the Schema from the file at path with s.
+func readSchema(s *dotenv.Sourcer, path string) (*dotenv.Schema, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	d, err := s.ParseDocument(file)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return d.Schema()
+}
+
+//prompt asks for the value of v on standard error and reads it from in until it
+//is valid. Secret values are not echoed if standard input is a terminal, and a
+//warning is written if echoing cannot be disabled, e.g. without stty.
+//ok is false if v is optional and no value was entered, in which case it is
+//left undefined.
+func prompt(in *bufio.Reader, v *dotenv.SchemaVar) (value string, ok bool, err error) {
+	secret := v.Secret || dotenv.IsSecretLike(v.Name, v.Example)
+	for {
+		var restore func()
+		if secret && isTerminal(os.Stdin) {
+			if restore, err = disableEcho(); err != nil {
+				fmt.Fprintf(os.Stderr, "warning: %v is echoed while it is typed: %v\n", v.Name, err)
+			}
+		}
+		fmt.Fprint(os.Stderr, v.Name)
+		if v.Description != "" {
+			fmt.Fprintf(os.Stderr, " (%v)", v.Description)
+		}
+		if v.HasDefault {
+			fmt.Fprintf(os.Stderr, " [%v]", v.Default)
+		}
+		fmt.Fprint(os.Stderr, ": ")
+
+		value, err = readLine(in)
+		if restore != nil {
+			restore()
+			fmt.Fprintln(os.Stderr)
+		}
+		if err != nil {
+			return "", false, err
+		}
+		if value == "" && v.HasDefault {
+			value = v.Default
+		}
+		if value == "" && !v.Required {
+			return "", false, nil
+		}
+		if value == "" {
+			fmt.Fprintf(os.Stderr, "%v is required\n", v.Name)
+			continue
+		}
+		if err := v.Check(value); err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			continue
+		}
+		return value, true, nil
+	}
+}
+
+//readLine reads a line from in without its line ending.
+func readLine(in *bufio.Reader) (string, error) {
+	line, err := in.ReadString('\n')
+	if errors.Is(err, io.EOF) && line == "" {
+		return "", errors.New("unexpected end of input")
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+//isTerminal determines whether or not file is a terminal.
+func isTerminal(file *os.File) bool {
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+//disableEcho disables echoing on the terminal of standard input with stty and
+//returns the function that enables it again. If dotenv is interrupted or
+//terminated before, then echoing is enabled again and it exits.
+func disableEcho() (restore func(), err error) {
+	if _, err := exec.LookPath("stty"); err != nil {
+		return nil, err
+	}
+	if err := stty("-echo"); err != nil {
+		return nil, err
+	}
+	signals, done := make(chan os.Signal, 1), make(chan struct{})
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-signals:
+			stty("echo")
+			fmt.Fprintln(os.Stderr)
+			os.Exit(130)
+		case <-done:
+		}
+	}()
+	return func() {
+		signal.Stop(signals)
+		close(done)
+		stty("echo")
+	}, nil
+}
+
+//stty runs stty with arg on the terminal of standard input.
+func stty(arg string) error {
+	cmd := exec.Command("stty", arg)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
@@ -0,0 +1,102 @@
+//Command dotenv is a command line tool for working with dotenv files.
+//
+//Usage:
+//
+//	dotenv <command> [flags] [arguments]
+//
+//Run dotenv help to list the commands, and dotenv <command> -h for the flags of
+//a command.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gogolfing/dotenv"
+)
+
+//command is a subcommand of dotenv.
+type command struct {
+	//usage is the one line synopsis of the command's arguments.
+	usage string
+
+	//summary is a short description of the command.
+	summary string
+
+	//run runs the command with its arguments, i.e. those following its name.
+	run func(args []string) error
+}
+
+//commands contains all subcommands by name.
+var commands = map[string]*command{}
+
+//hiddenCommands contains the subcommands by name that are not listed by
+//printUsage, such as those called by scripts.
+var hiddenCommands = map[string]*command{}
+
+//project is the Project of the working directory, whose configuration provides
+//the defaults of commands.
+var project *dotenv.Project
+
+func main() {
+	if len(os.Args) < 2 {
+		printUsage()
+		os.Exit(2)
+	}
+	name := os.Args[1]
+	if name == "help" || name == "-h" || name == "--help" {
+		printUsage()
+		return
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		cmd, ok = hiddenCommands[name]
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "dotenv: unknown command %q\n", name)
+		printUsage()
+		os.Exit(2)
+	}
+	var err error
+	if project, err = dotenv.FindProject("."); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv:", err)
+		os.Exit(1)
+	}
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintln(os.Stderr, "dotenv:", err)
+		os.Exit(1)
+	}
+}
+
+//printUsage writes the list of commands to standard error.
+func printUsage() {
+	fmt.Fprintln(os.Stderr, "usage: dotenv <command> [flags] [arguments]\n\ncommands:")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10v %v\n", name, commands[name].summary)
+	}
+}
+
+//newFlagSet returns a FlagSet for the command name whose usage message includes
+//the command's synopsis.
+func newFlagSet(name string) *flag.FlagSet {
+	flags := flag.NewFlagSet(name, flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintf(flags.Output(), "usage: dotenv %v %v\n", name, commands[name].usage)
+		flags.PrintDefaults()
+	}
+	return flags
+}
+
+//presetFlag defines the -preset flag of flags, which selects the preset that
+//files, as named by what, are parsed with. It defaults to the preset of project.
+func presetFlag(flags *flag.FlagSet, what string) *string {
+	return flags.String("preset", project.Preset, "the preset to parse "+what+" with, one of "+strings.Join(dotenv.Presets(), ", "))
+}
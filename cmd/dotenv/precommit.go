@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["pre-commit"] = &command{
+		usage:   "[-preset name] [-example path] [dir]",
+		summary: "check staged environment files from a git pre-commit hook",
+		run:     runPreCommit,
+	}
+}
+
+//runPreCommit checks the staged environment files of the repository at dir, or
+//the current directory, with the schema and linters of the project, and prints
+//their problems. Without linters in the project, files are checked for
+//plaintext secrets.
+func runPreCommit(args []string) error {
+	flags := newFlagSet("pre-commit")
+	preset := presetFlag(flags, "files")
+	example := flags.String("example", ".env.example", "the example file that staged files must match, ignored if it does not exist")
+	flags.Parse(args)
+	if flags.NArg() > 1 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	dir := "."
+	if flags.NArg() == 1 {
+		dir = flags.Arg(0)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	c, err := project.Checker()
+	if err != nil {
+		return err
+	}
+	c.Sourcer = s
+	if len(c.Linters) == 0 {
+		c.Linters = []dotenv.Linter{dotenv.NewEntropyDetector().Lint}
+	}
+	if _, err := os.Stat(*example); err == nil {
+		c.Example = *example
+	} else if isFlagSet(flags, "example") {
+		return err
+	}
+	return c.PreCommit(dir, os.Stdout)
+}
+
+//isFlagSet determines whether or not the flag name was set on the command line.
+func isFlagSet(flags *flag.FlagSet, name string) bool {
+	set := false
+	flags.Visit(func(f *flag.Flag) {
+		set = set || f.Name == name
+	})
+	return set
+}
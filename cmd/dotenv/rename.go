@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["rename"] = &command{
+		usage:   "[-preset name] [-n] old new file|pattern ...",
+		summary: "rename a variable and the references to it across files",
+		run:     runRename,
+	}
+}
+
+//runRename renames a variable in files and prints the diff of the change.
+func runRename(args []string) error {
+	flags := newFlagSet("rename")
+	preset := presetFlag(flags, "files")
+	dryRun := flags.Bool("n", false, "only print the diff without changing any file")
+	flags.Parse(args)
+	if flags.NArg() < 3 {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	plan, err := s.RenameFiles(flags.Arg(0), flags.Arg(1), flags.Args()[2:]...)
+	if err != nil {
+		return err
+	}
+	fmt.Print(plan.Diff())
+	for _, file := range plan.Files {
+		for _, location := range file.Skipped {
+			fmt.Fprintf(os.Stderr, "%v:%v:%v: reference not renamed\n", file.Path, location.Line, location.Column)
+		}
+	}
+	if *dryRun {
+		return nil
+	}
+	return plan.Apply()
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["rotate"] = &command{
+		usage:   "[-preset name] -old keyfile -new keyfile file ...",
+		summary: "re-encrypt the encrypted values of files with a new key",
+		run:     runRotate,
+	}
+}
+
+//runRotate re-encrypts the enc: values of files in place. Either all files are
+//changed or none of them are, as with rename.
+func runRotate(args []string) error {
+	flags := newFlagSet("rotate")
+	preset := presetFlag(flags, "files")
+	oldPath := flags.String("old", "", "the file containing the base64 encoded current key")
+	newPath := flags.String("new", "", "the file containing the base64 encoded new key")
+	flags.Parse(args)
+	if flags.NArg() == 0 || *oldPath == "" || *newPath == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	s, err := dotenv.Preset(*preset)
+	if err != nil {
+		return err
+	}
+	oldKey, err := readKey(*oldPath)
+	if err != nil {
+		return err
+	}
+	newKey, err := readKey(*newPath)
+	if err != nil {
+		return err
+	}
+	//every file is rotated before any is written, so that a file that cannot be
+	//rotated leaves all of them unchanged.
+	plan := &dotenv.RenamePlan{}
+	for _, path := range flags.Args() {
+		file, err := rotateFile(s, path, oldKey, newKey)
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		if file.New != file.Old {
+			plan.Files = append(plan.Files, file)
+		}
+	}
+	return plan.Apply()
+}
+
+//readKey returns the key that is base64 encoded in the file at path.
+func readKey(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+}
+
+//rotateFile returns the change that rotating the encrypted values of the file at
+//path from oldKey to newKey makes to it, keeping its byte order mark and final
+//newline.
+func rotateFile(s *dotenv.Sourcer, path string, oldKey, newKey []byte) (*dotenv.FileRename, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	d, err := s.ParseDocument(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	d.FinalNewline = dotenv.FinalNewlinePreserve
+	if err := dotenv.Rotate(d, oldKey, newKey); err != nil {
+		return nil, err
+	}
+	text := d.String()
+	if bytes.HasPrefix(data, []byte("\ufeff")) {
+		text = "\ufeff" + text
+	}
+	return &dotenv.FileRename{Path: path, Old: string(data), New: text}, nil
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["run"] = &command{
+		usage:   "[-preset name] [-profile name] [-watch] [-policy restart|signal|ignore] -- command [arguments]",
+		summary: "run a command with the variables of the project",
+		run:     runRun,
+	}
+}
+
+//restartPolicies contains the values of the -policy flag of run.
+var restartPolicies = map[string]dotenv.RestartPolicy{
+	"restart": dotenv.RestartOnChange,
+	"signal":  dotenv.SignalOnChange,
+	"ignore":  dotenv.IgnoreChange,
+}
+
+//runRun loads the project, with the profile selected by -profile if any, and
+//runs the command with its variables. With -watch, the files of the project are
+//watched and changes are applied to the command as determined by -policy.
+//Interrupts and terminations stop the command, and dotenv exits with its exit
+//code.
+func runRun(args []string) error {
+	flags := newFlagSet("run")
+	newRunner := runnerFlags(flags)
+	flags.Parse(args)
+	if flags.NArg() == 0 {
+		flags.Usage()
+		os.Exit(2)
+	}
+	runner, err := newRunner()
+	if err != nil {
+		return err
+	}
+	runner.Name, runner.Args = flags.Arg(0), flags.Args()[1:]
+	runner.Stdin, runner.Stdout, runner.Stderr = os.Stdin, os.Stdout, os.Stderr
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return exitRunner(runner.Run(ctx))
+}
+
+//runnerFlags adds the flags of commands that run processes with a Runner to
+//flags. The returned function returns the Runner of the project selected by the
+//flags, and must be called after flags are parsed. It exits with a usage error
+//if -policy is invalid.
+func runnerFlags(flags *flag.FlagSet) func() (*dotenv.Runner, error) {
+	preset := presetFlag(flags, "files")
+	profile := flags.String("profile", "", "the `name` of the project's profile to load")
+	watch := flags.Bool("watch", false, "watch the files and apply changes to the processes")
+	policy := flags.String("policy", "restart", "what to do when the files change: restart the processes, send them SIGHUP with signal, or ignore")
+	return func() (*dotenv.Runner, error) {
+		restartPolicy, ok := restartPolicies[*policy]
+		if !ok {
+			flags.Usage()
+			os.Exit(2)
+		}
+		p, err := project.WithProfile(*profile)
+		if err != nil {
+			return nil, err
+		}
+		selected := *p
+		selected.Preset = *preset
+		runner := &dotenv.Runner{Load: selected.Load, Policy: restartPolicy}
+		if *watch {
+			runner.Files = selected.Paths()
+			runner.OnReload = func(changed bool, err error) {
+				if err != nil {
+					fmt.Fprintln(os.Stderr, "dotenv: reload:", err)
+				} else if changed {
+					fmt.Fprintf(os.Stderr, "dotenv: reloaded (%v)\n", *policy)
+				}
+			}
+		}
+		return runner, nil
+	}
+}
+
+//exitRunner exits with the exit code of the process if err is an
+//*exec.ExitError, or with 1 if the Runner was interrupted, and returns err
+//otherwise.
+func exitRunner(err error) error {
+	var exitErr *exec.ExitError
+	switch {
+	case errors.As(err, &exitErr):
+		os.Exit(exitErr.ExitCode())
+	case errors.Is(err, context.Canceled):
+		os.Exit(1)
+	}
+	return err
+}
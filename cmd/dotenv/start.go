@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func init() {
+	commands["start"] = &command{
+		usage:   "[-preset name] [-profile name] [-watch] [-policy restart|signal|ignore] [-f Procfile] [process...]",
+		summary: "run the processes of a Procfile with the variables of the project",
+		run:     runStart,
+	}
+}
+
+//runStart runs the processes of the Procfile, or only those named as arguments,
+//with the variables of the project, like foreman start. The output of each
+//process is prefixed with its name. When any process exits, the others are
+//stopped and dotenv exits with its exit code.
+func runStart(args []string) error {
+	flags := newFlagSet("start")
+	newRunner := runnerFlags(flags)
+	procfile := flags.String("f", "Procfile", "the `path` of the Procfile, relative to the project")
+	flags.Parse(args)
+
+	file, err := os.Open(project.Path(*procfile))
+	if err != nil {
+		return err
+	}
+	processes, err := dotenv.ParseProcfile(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		byName := map[string]*dotenv.Process{}
+		for _, process := range processes {
+			byName[process.Name] = process
+		}
+		processes = processes[:0:0]
+		for _, name := range flags.Args() {
+			process, ok := byName[name]
+			if !ok {
+				flags.Usage()
+				os.Exit(2)
+			}
+			processes = append(processes, process)
+		}
+	}
+	runner, err := newRunner()
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	err = runner.StartProcesses(ctx, processes, os.Stdout)
+	if err != nil && ctx.Err() == nil {
+		fmt.Fprintln(os.Stderr, "dotenv:", err)
+	}
+	return exitRunner(err)
+}
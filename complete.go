@@ -0,0 +1,126 @@
+package dotenv
+
+import (
+	"os"
+	"sort"
+	"strings"
+)
+
+//CompletionSchema is the source of Completions for names in the Schema of a
+//Project.
+const CompletionSchema = "schema"
+
+//Completion is a candidate variable name for completion, as returned from
+//Project.Completions.
+type Completion struct {
+	//Name is the name of the variable.
+	Name string `json:"name"`
+
+	//Description is the description of the variable in the Schema of the
+	//Project, if any.
+	Description string `json:"description,omitempty"`
+
+	//Sources contains where Name was found, which are CompletionSchema and the
+	//paths of the files defining it, in the order of the Project's layers.
+	Sources []string `json:"sources"`
+}
+
+//Completions returns the names that start with prefix from the Schema of p, its
+//Schema file, such as .env.example, and the files of its layers that exist, for
+//completion in editors and interactive commands. Completions are sorted by
+//name.
+//If the Schema or a file cannot be read or parsed, then that error is returned.
+func (p *Project) Completions(prefix string) ([]*Completion, error) {
+	s, err := p.Sourcer()
+	if err != nil {
+		return nil, err
+	}
+	byName := map[string]*Completion{}
+	add := func(name, source string) *Completion {
+		if !strings.HasPrefix(name, prefix) {
+			return nil
+		}
+		completion, ok := byName[name]
+		if !ok {
+			completion = &Completion{Name: name, Sources: []string{}}
+			byName[name] = completion
+		}
+		if len(completion.Sources) == 0 || completion.Sources[len(completion.Sources)-1] != source {
+			completion.Sources = append(completion.Sources, source)
+		}
+		return completion
+	}
+
+	schema, err := p.LoadSchema()
+	if err != nil {
+		return nil, err
+	}
+	paths := p.Paths()
+	if schema != nil {
+		for _, v := range schema.Vars {
+			if completion := add(v.Name, CompletionSchema); completion != nil {
+				completion.Description = v.Description
+			}
+		}
+		paths = append([]string{p.Path(p.Schema)}, paths...)
+	}
+	for _, path := range paths {
+		variables, err := s.VariablesFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables {
+			add(variable.Name, path)
+		}
+	}
+
+	result := make([]*Completion, 0, len(byName))
+	for _, completion := range byName {
+		result = append(result, completion)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+//CompletionsAt returns the Completions for the cursor at location in d, which is
+//being edited. If the cursor follows the start of a name at the beginning of a
+//line, then the names that are not yet defined in d are returned. If it follows
+//"$" or "${" and the start of a name in a value, then all names are returned for
+//the reference. Otherwise, nil is returned.
+func (p *Project) CompletionsAt(d *Document, location Location) ([]*Completion, error) {
+	if _, ok := d.Offset(location); !ok {
+		return nil, nil
+	}
+	before := d.lines[location.Line-1].raw[:location.Column-1]
+	start := len(before)
+	for start > 0 && isNameByte(before[start-1], false) {
+		start--
+	}
+	prefix, context := before[start:], strings.TrimLeft(before[:start], SpaceTab)
+	if d.sourcer.Export != "" && strings.HasPrefix(context, d.sourcer.Export) {
+		context = strings.TrimLeft(context[len(d.sourcer.Export):], SpaceTab)
+	}
+
+	switch {
+	case context == "":
+		completions, err := p.Completions(prefix)
+		if err != nil {
+			return nil, err
+		}
+		result := []*Completion{}
+		for _, completion := range completions {
+			if i := d.index(completion.Name); i < 0 || i == location.Line-1 {
+				result = append(result, completion)
+			}
+		}
+		return result, nil
+	case strings.HasSuffix(before[:start], "${") || strings.HasSuffix(before[:start], "$"):
+		return p.Completions(prefix)
+	}
+	return nil, nil
+}
@@ -0,0 +1,66 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProject_Completions(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(ProjectFile, "FILES=.env .env.local\nSCHEMA=.env.example\n")
+	write(".env.example", "#the database\nDB_URL=\nAPI_KEY=\n")
+	write(".env", "DB_URL=postgres://\nDB_POOL=5\n")
+	p, err := FindProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	example, base := filepath.Join(dir, ".env.example"), filepath.Join(dir, ".env")
+
+	completions, err := p.Completions("DB_")
+	want := []*Completion{
+		{"DB_POOL", "", []string{base}},
+		{"DB_URL", "the database", []string{CompletionSchema, example, base}},
+	}
+	if err != nil || !reflect.DeepEqual(completions, want) {
+		t.Errorf("completions, err = %v, %v", completions, err)
+	}
+
+	d, lineErrors, err := NewDefault().ParseDocumentPartial(strings.NewReader("export DB\nDB_URL=x\nA=${D\nB=b\n"))
+	if err != nil || len(lineErrors) != 1 || lineErrors[0].Line != 1 {
+		t.Fatalf("lineErrors, err = %v, %v", lineErrors, err)
+	}
+	tests := []struct {
+		location Location
+		want     []string
+	}{
+		{Location{1, 10}, []string{"DB_POOL"}},
+		{Location{1, 8}, []string{"API_KEY", "DB_POOL"}},
+		{Location{2, 3}, []string{"DB_POOL", "DB_URL"}},
+		{Location{3, 6}, []string{"DB_POOL", "DB_URL"}},
+		{Location{4, 3}, nil},
+		{Location{9, 1}, nil},
+	}
+	for _, test := range tests {
+		completions, err := p.CompletionsAt(d, test.location)
+		var names []string
+		for _, completion := range completions {
+			names = append(names, completion.Name)
+		}
+		if err != nil || !reflect.DeepEqual(names, test.want) {
+			t.Errorf("CompletionsAt(%v) = %v, %v", test.location, names, err)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+//CorpusResult is the normalized result of parsing a single fixture file of a
+//corpus, as returned from ParseCorpus. It is intended to be encoded as JSON and
+//diffed against the results of other implementations for the same fixture, so it
+//contains only what every implementation can produce.
+type CorpusResult struct {
+	//File is the path of the fixture relative to the corpus directory, with "/"
+	//separators.
+	File string `json:"file"`
+
+	//Env maps every name defined in the fixture to the value of its last
+	//definition. It is empty if Error is not empty.
+	Env map[string]string `json:"env"`
+
+	//Error is the message of the error that occurred while parsing the fixture,
+	//if any. Since messages differ between implementations, comparisons should
+	//only depend on whether or not Error is empty, and on Line.
+	Error string `json:"error,omitempty"`
+
+	//Line is the line number (1-based) of the line Error occurred on, or 0 if it
+	//did not occur on a line.
+	Line int `json:"line,omitempty"`
+}
+
+//ParseCorpus parses every fixture in the directory dir and its subdirectories
+//with a copy of s, and returns a CorpusResult for each of them, in lexical order
+//of their paths. Fixtures are regular files named .env or whose names end in
+//".env", e.g. "quotes/escapes.env".
+//
+//Errors while parsing a fixture, including those that prevent parsing such as an
+//ErrBinaryInput, are part of its result. If dir cannot be walked or a fixture
+//cannot be opened, then that error is returned.
+//
+//So that results do not depend on the process's environment, references that
+//are not defined in a fixture expand to the empty string unless s.Lookup is set.
+//Names passed through by s.PassThrough are still looked up in the process's
+//environment.
+func (s *Sourcer) ParseCorpus(dir string) ([]*CorpusResult, error) {
+	s = s.Clone()
+	if s.Lookup == nil {
+		s.Lookup = LookupMap(nil)
+	}
+
+	paths := []string{}
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() && strings.HasSuffix(entry.Name(), ".env") {
+			paths = append(paths, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	result := make([]*CorpusResult, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return nil, err
+		}
+		fixture, err := s.parseFixture(path)
+		if err != nil {
+			return nil, err
+		}
+		fixture.File = filepath.ToSlash(rel)
+		result = append(result, fixture)
+	}
+	return result, nil
+}
+
+//parseFixture returns the result of parsing the fixture at path, without File.
+func (s *Sourcer) parseFixture(path string) (*CorpusResult, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	result := &CorpusResult{Env: map[string]string{}}
+	nameVars, err := s.NameVars(file)
+	if err != nil {
+		result.Error = err.Error()
+		var sourcingErr *ErrSourcing
+		if errors.As(err, &sourcingErr) {
+			result.Error, result.Line = sourcingErr.LineError.Error(), sourcingErr.Line
+		}
+		return result, nil
+	}
+	for _, nameVar := range nameVars {
+		result.Env[nameVar[0]] = nameVar[1]
+	}
+	return result, nil
+}
@@ -0,0 +1,56 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSourcer_ParseCorpus(t *testing.T) {
+	dir := t.TempDir()
+	fixtures := map[string]string{
+		".env":             "A=1\nA=2\n",
+		"quotes/basic.env": "B=\"b c\"\nC=${UNDEFINED}x\n",
+		"errors/bad.env":   "D=d\nnot a variable\n",
+		"README.md":        "ignored",
+		"expected.json":    "{}",
+	}
+	for name, content := range fixtures {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0700)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.Setenv("UNDEFINED", "defined")
+	defer os.Unsetenv("UNDEFINED")
+
+	s := NewDefault()
+	s.Interpolate = true
+	results, err := s.ParseCorpus(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*CorpusResult{
+		{File: ".env", Env: map[string]string{"A": "2"}},
+		{File: "errors/bad.env", Env: map[string]string{}, Error: ErrNonVariableLine("not a variable").Error(), Line: 2},
+		{File: "quotes/basic.env", Env: map[string]string{"B": "b c", "C": "x"}},
+	}
+	if !reflect.DeepEqual(results, want) {
+		t.Errorf("results = %v WANT %v", results, want)
+	}
+	if s.Lookup != nil {
+		t.Error("ParseCorpus must not change s")
+	}
+
+	data, err := json.Marshal(results[0])
+	if err != nil || string(data) != `{"file":".env","env":{"A":"2"}}` {
+		t.Errorf("data, err = %s, %v", data, err)
+	}
+
+	if _, err := s.ParseCorpus(filepath.Join(dir, "missing")); err == nil {
+		t.Error("missing directory")
+	}
+}
@@ -0,0 +1,102 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//CredentialsDirectory is the name of the environment variable that systemd sets
+//to the directory containing a service's credentials, i.e. those configured with
+//LoadCredential=, SetCredential=, and similar directives.
+const CredentialsDirectory = "CREDENTIALS_DIRECTORY"
+
+//Credentials reads every credential in dir and returns them as Variables, in
+//order of name. Each regular file in dir is one credential, whose name is the
+//file's name and whose value is the file's contents with a single trailing
+//newline removed. File is set to the path of the file and Line is 0.
+//Hidden files and directories are skipped.
+//
+//If dir is empty, then the value of CredentialsDirectory in the process's
+//environment is used. If that is not set either, then the process is not running
+//with credentials and an empty slice is returned.
+//If a file's name is not a valid name in s, then an ErrInvalidName is returned.
+//Names are not changed by StripPrefix or OSSuffix, and values are not
+//interpolated or resolved, although s.Policy is applied.
+func (s *Sourcer) Credentials(dir string) ([]*Variable, error) {
+	if dir == "" {
+		dir = os.Getenv(CredentialsDirectory)
+	}
+	result := []*Variable{}
+	if dir == "" {
+		return result, nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") {
+			continue
+		}
+		if s.isNameInvalid(name) {
+			return nil, ErrInvalidName(name)
+		}
+		path := filepath.Join(dir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		value := trimNewline(string(data))
+		if s.Policy != nil {
+			if err := s.Policy(name, value); err != nil {
+				return nil, err
+			}
+		}
+		result = append(result, &Variable{Name: name, Value: value, File: path})
+	}
+	return result, nil
+}
+
+//SourceFilesWithCredentials sources the files in paths like SourceFiles() and
+//then sets the credentials in dir, as returned from Credentials(), so that a
+//credential takes precedence over every definition of its name in paths.
+//While the files are sourced, interpolated references to a credential's name
+//that are not defined earlier in the same file are resolved to the credential's
+//value before s.Lookup is used. This allows, for example, a database URL in a
+//file to reference a password that is only available as a credential.
+//The credentials are read before any file is sourced, so if an error occurs
+//while reading them, then nothing is set.
+//With s.NoOverride, the files and credentials are set as a single call, so
+//credentials still take precedence over the files.
+func (s *Sourcer) SourceFilesWithCredentials(dir string, paths ...string) error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	credentials, err := s.Credentials(dir)
+	if err != nil {
+		return err
+	}
+	values := map[string]string{}
+	for _, credential := range credentials {
+		values[credential.Name] = credential.Value
+	}
+
+	lookup := s.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	withCredentials := *s
+	withCredentials.Lookup = LookupChain(LookupMap(values), lookup)
+	st := s.newSetter()
+	if err := withCredentials.sourceFiles(st, paths); err != nil {
+		return err
+	}
+	for _, credential := range credentials {
+		if _, err := st.setenv(credential.Name, credential.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
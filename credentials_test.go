@@ -0,0 +1,89 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSourcer_Credentials(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"B":       "b\n",
+		"A":       "a\nmulti\n\n",
+		".hidden": "h",
+	})
+	if err := os.Mkdir(filepath.Join(dir, "sub"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	variables, err := NewDefault().Credentials(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Variable{
+		{Name: "A", Value: "a\nmulti\n", File: filepath.Join(dir, "A")},
+		{Name: "B", Value: "b", File: filepath.Join(dir, "B")},
+	}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("variables = %v WANT %v", variables, want)
+	}
+
+	os.Setenv(CredentialsDirectory, dir)
+	variables, err = NewDefault().Credentials("")
+	os.Unsetenv(CredentialsDirectory)
+	if err != nil || len(variables) != 2 {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+
+	variables, err = NewDefault().Credentials("")
+	if err != nil || len(variables) != 0 {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+}
+
+func TestSourcer_Credentials_errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"A#B": "a"})
+	if _, err := NewDefault().Credentials(dir); err != ErrInvalidName("A#B") {
+		t.Errorf("err = %v", err)
+	}
+
+	dir2 := writeFiles(t, map[string]string{"A": "changeme"})
+	s := NewDefault()
+	s.Policy = ForbidValues("changeme")
+	if _, err := s.Credentials(dir2); err == nil {
+		t.Error("Credentials() must apply Policy")
+	}
+
+	if _, err := NewDefault().Credentials(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_SourceFilesWithCredentials(t *testing.T) {
+	skipUnlessSetenv(t)
+	dir := writeFiles(t, map[string]string{
+		"GOGOLFING_DOTENV_CRED_PASSWORD": "secret\n",
+	})
+	paths := writeLayerFiles(t,
+		"GOGOLFING_DOTENV_CRED_URL=db://u:${GOGOLFING_DOTENV_CRED_PASSWORD}@host\nGOGOLFING_DOTENV_CRED_PASSWORD=dev",
+	)
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	defer os.Unsetenv("GOGOLFING_DOTENV_CRED_URL")
+	defer os.Unsetenv("GOGOLFING_DOTENV_CRED_PASSWORD")
+
+	s := NewDefault()
+	s.Interpolate = true
+	if err := s.SourceFilesWithCredentials(dir, paths...); err != nil {
+		t.Fatal(err)
+	}
+	if v := os.Getenv("GOGOLFING_DOTENV_CRED_URL"); v != "db://u:secret@host" {
+		t.Errorf("URL = %q", v)
+	}
+	if v := os.Getenv("GOGOLFING_DOTENV_CRED_PASSWORD"); v != "secret" {
+		t.Errorf("PASSWORD = %q", v)
+	}
+	if s.Lookup != nil {
+		t.Error("SourceFilesWithCredentials() must not modify s")
+	}
+}
@@ -0,0 +1,127 @@
+package dotenv
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//csvHeader is the header record written by EncodeCSV and EncodeTSV.
+var csvHeader = []string{"name", "value"}
+
+//csvFormulaPrefixes contains the characters that make spreadsheet applications
+//evaluate a field as a formula when it starts with one of them.
+const csvFormulaPrefixes = "=+-@\t\r"
+
+//csvEscape is the prefix that stops a spreadsheet application from evaluating a
+//field as a formula.
+const csvEscape = "'"
+
+//EncodeCSV writes nameVars to w as comma separated values with a name,value
+//header record. Fields are quoted as described by encoding/csv.
+//
+//Values are meant to be opened with spreadsheet applications, which evaluate
+//fields starting with =, +, -, @, a tab, or a carriage return as formulas, so
+//such values are written with a leading single quote, which the applications
+//do not display, e.g. =1+1 is written as '=1+1 and displayed as text. Values that
+//already start with a single quote followed by one of those characters, or by
+//another single quote, get one more single quote, so that DecodeCSV removes
+//exactly one and every value is read back unchanged.
+func EncodeCSV(w io.Writer, nameVars [][2]string) error {
+	return encodeDelimited(w, nameVars, ',')
+}
+
+//DecodeCSV reads comma separated name,value records from r, as written by
+//EncodeCSV, and removes the single quote that EncodeCSV adds to values.
+//If header is true, then the first record must be a name,value header record,
+//which is case-insensitive and is skipped. Otherwise every record is a variable.
+//Every record must contain exactly two fields.
+//If a name is invalid, then an *ErrSourcing with an ErrInvalidName is returned.
+func DecodeCSV(r io.Reader, header bool) ([][2]string, error) {
+	return decodeDelimited(r, ',', header)
+}
+
+//EncodeTSV is the same as EncodeCSV except that fields are separated by tabs.
+func EncodeTSV(w io.Writer, nameVars [][2]string) error {
+	return encodeDelimited(w, nameVars, '\t')
+}
+
+//DecodeTSV is the same as DecodeCSV except that fields are separated by tabs.
+func DecodeTSV(r io.Reader, header bool) ([][2]string, error) {
+	return decodeDelimited(r, '\t', header)
+}
+
+//encodeDelimited writes nameVars to w with fields separated by comma.
+func encodeDelimited(w io.Writer, nameVars [][2]string, comma rune) error {
+	writer := csv.NewWriter(w)
+	writer.Comma = comma
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, nameVar := range nameVars {
+		if err := writer.Write([]string{nameVar[0], escapeCSVFormula(nameVar[1])}); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+//decodeDelimited reads name,value records from r with fields separated by comma,
+//the first of which is a header record if header is true.
+func decodeDelimited(r io.Reader, comma rune, header bool) ([][2]string, error) {
+	reader := csv.NewReader(r)
+	reader.Comma = comma
+	reader.FieldsPerRecord = len(csvHeader)
+
+	s := NewDefault()
+	result := [][2]string{}
+	for first := true; ; first = false {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return result, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if first && header {
+			if !strings.EqualFold(record[0], csvHeader[0]) || !strings.EqualFold(record[1], csvHeader[1]) {
+				return nil, fmt.Errorf("dotenv: first record %q is not the header record %q", record, csvHeader)
+			}
+			continue
+		}
+		if s.isNameInvalid(record[0]) {
+			line, _ := reader.FieldPos(0)
+			return nil, &ErrSourcing{line, ErrInvalidName(record[0])}
+		}
+		result = append(result, [2]string{record[0], unescapeCSVFormula(record[1])})
+	}
+}
+
+//escapeCSVFormula returns value with csvEscape prepended if a spreadsheet
+//application would evaluate it as a formula, or if it would otherwise be changed
+//by unescapeCSVFormula.
+func escapeCSVFormula(value string) string {
+	if (value != "" && strings.ContainsRune(csvFormulaPrefixes, rune(value[0]))) || isEscapedCSVFormula(value) {
+		return csvEscape + value
+	}
+	return value
+}
+
+//unescapeCSVFormula returns value without the csvEscape prepended by
+//escapeCSVFormula.
+func unescapeCSVFormula(value string) string {
+	if isEscapedCSVFormula(value) {
+		return value[len(csvEscape):]
+	}
+	return value
+}
+
+//isEscapedCSVFormula determines whether or not value starts with csvEscape
+//followed by one of csvFormulaPrefixes or by another csvEscape.
+func isEscapedCSVFormula(value string) bool {
+	rest := strings.TrimPrefix(value, csvEscape)
+	return rest != value && rest != "" &&
+		(strings.ContainsRune(csvFormulaPrefixes, rune(rest[0])) || strings.HasPrefix(rest, csvEscape))
+}
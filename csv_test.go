@@ -0,0 +1,125 @@
+package dotenv
+
+import (
+	"encoding/csv"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeCSV(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeCSV(out, [][2]string{{"A", "a"}, {"B", "b,c"}, {"C", "say \"hi\"\nbye"}})
+	if err != nil {
+		t.Error(err)
+	}
+	want := "name,value\nA,a\nB,\"b,c\"\nC,\"say \"\"hi\"\"\nbye\"\n"
+	if out.String() != want {
+		t.Errorf("out = %q WANT %q", out.String(), want)
+	}
+}
+
+func TestEncodeTSV(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeTSV(out, [][2]string{{"A", "a b"}, {"B", "b\tc"}})
+	if err != nil {
+		t.Error(err)
+	}
+	want := "name\tvalue\nA\ta b\nB\t\"b\tc\"\n"
+	if out.String() != want {
+		t.Errorf("out = %q WANT %q", out.String(), want)
+	}
+}
+
+func TestDecodeCSV_roundTrip(t *testing.T) {
+	nameVars := [][2]string{{"A", "a"}, {"B", "b,c"}, {"C", "say \"hi\"\nbye"}, {"D", ""}}
+	out := &strings.Builder{}
+	if err := EncodeCSV(out, nameVars); err != nil {
+		t.Error(err)
+	}
+	result, err := DecodeCSV(strings.NewReader(out.String()), true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(result, nameVars) {
+		t.Errorf("result = %q WANT %q", result, nameVars)
+	}
+}
+
+func TestEncodeCSV_formulas(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"=1+1", "'=1+1"},
+		{"+1", "'+1"},
+		{"-1", "'-1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\tx", "'\tx"},
+		{"'=1+1", "''=1+1"},
+		{"''", "'''"},
+		{"'quoted'", "'quoted'"},
+		{"'", "'"},
+		{"a=b", "a=b"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		out := &strings.Builder{}
+		if err := EncodeCSV(out, [][2]string{{"A", test.value}}); err != nil {
+			t.Fatal(err)
+		}
+		if want := "name,value\nA," + test.want + "\n"; out.String() != want {
+			t.Errorf("EncodeCSV(%q) = %q WANT %q", test.value, out.String(), want)
+		}
+		result, err := DecodeCSV(strings.NewReader(out.String()), true)
+		if err != nil || !reflect.DeepEqual(result, [][2]string{{"A", test.value}}) {
+			t.Errorf("DecodeCSV(%q) = %q, %v", out.String(), result, err)
+		}
+	}
+}
+
+func TestDecodeCSV_header(t *testing.T) {
+	result, err := DecodeCSV(strings.NewReader("name,value\nA,a\n"), false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(result, [][2]string{{"name", "value"}, {"A", "a"}}) {
+		t.Errorf("result = %q", result)
+	}
+
+	result, err = DecodeCSV(strings.NewReader("A,a\nName,Value\n"), false)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(result, [][2]string{{"A", "a"}, {"Name", "Value"}}) {
+		t.Errorf("result = %q", result)
+	}
+
+	if result, err := DecodeCSV(strings.NewReader("A,a\n"), true); err == nil {
+		t.Errorf("result = %q, want an error for the missing header", result)
+	}
+	if result, err := DecodeCSV(strings.NewReader(""), true); err != nil || len(result) != 0 {
+		t.Errorf("result, err = %q, %v", result, err)
+	}
+}
+
+func TestDecodeTSV(t *testing.T) {
+	result, err := DecodeTSV(strings.NewReader("NAME\tVALUE\nA\ta,b\n"), true)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(result, [][2]string{{"A", "a,b"}}) {
+		t.Errorf("result = %q", result)
+	}
+}
+
+func TestDecodeCSV_errors(t *testing.T) {
+	_, err := DecodeCSV(strings.NewReader("name,value\nA,a\n\"B C\",b\n"), true)
+	if !reflect.DeepEqual(err, &ErrSourcing{3, ErrInvalidName("B C")}) {
+		t.Errorf("err = %v", err)
+	}
+
+	_, err = DecodeCSV(strings.NewReader("A,a,extra\n"), false)
+	if parseErr, ok := err.(*csv.ParseError); !ok || parseErr.Err != csv.ErrFieldCount {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+//RuleDeprecated is the Rule of the Problems reported by LintDeprecated and passed
+//to a Sourcer's Warn.
+const RuleDeprecated = "deprecated"
+
+//deprecatedPrefix starts the text of a comment that deprecates the variable
+//defined on its line.
+const deprecatedPrefix = "deprecated:"
+
+//deprecation returns the message of the deprecated annotation of parsed, which s
+//parsed from raw, and the column (1-based) the annotation starts at. The
+//annotation is the comment of the line, and its message is the text following
+//"deprecated:". message is empty if parsed does not have an annotation or its
+//message is empty.
+func (s *Sourcer) deprecation(raw string, parsed parsedLine) (message string, column int) {
+	message, ok := annotation(parsed.comment, deprecatedPrefix)
+	if !parsed.hasComment || !ok {
+		return "", 0
+	}
+	return message, len(raw) - len(parsed.comment) - len(s.Comment) + 1
+}
+
+//annotation returns the text following prefix in comment, with surrounding
+//whitespace removed, and whether or not comment starts with prefix after
+//leading whitespace.
+func annotation(comment, prefix string) (string, bool) {
+	comment = strings.TrimLeft(comment, SpaceTab)
+	if !strings.HasPrefix(comment, prefix) {
+		return "", false
+	}
+	return strings.Trim(comment[len(prefix):], SpaceTab), true
+}
+
+//deprecatedProblem returns the Problem for the variable name, deprecated with
+//message, at location.
+func deprecatedProblem(location Location, name, verb, message string) *Problem {
+	return &Problem{
+		Location: location,
+		Name:     name,
+		Rule:     RuleDeprecated,
+		Message:  fmt.Sprintf("%q is %v, but deprecated: %v", name, verb, message),
+	}
+}
+
+//warn calls s.Warn with problem if it is not nil.
+func (s *Sourcer) warn(problem *Problem) {
+	if s.Warn != nil {
+		s.Warn(problem)
+	}
+}
+
+//LintDeprecated reports every definition in d that is annotated as deprecated
+//with a comment of the form "#deprecated: use NEW_NAME" on its line, so that
+//files still defining old names can be found while teams migrate to new ones.
+//It is a Linter.
+func LintDeprecated(d *Document) []*Problem {
+	problems := []*Problem{}
+	for i, line := range d.lines {
+		if !line.isVariable {
+			continue
+		}
+		if message, column := d.sourcer.deprecation(line.raw, line.parsedLine); message != "" {
+			problems = append(problems, deprecatedProblem(Location{i + 1, column}, line.name, "defined", message))
+		}
+	}
+	return problems
+}
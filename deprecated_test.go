@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintDeprecated(t *testing.T) {
+	input := `OLD=a #deprecated: use NEW
+NEW=b # deprecated:
+#deprecated: use C
+C=c #deprecated
+D=d #not deprecated: really
+`
+	d, err := NewDefault().ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := LintDeprecated(d)
+	want := []*Problem{
+		{Location{1, 7}, "OLD", RuleDeprecated, `"OLD" is defined, but deprecated: use NEW`},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v", problems)
+	}
+}
+
+func TestSourcer_Warn_deprecated(t *testing.T) {
+	problems := []*Problem{}
+	s := NewSourcerWith(WithWarn(func(problem *Problem) {
+		problems = append(problems, problem)
+	}))
+	input := "A=a\nOLD=b    #deprecated: use NEW \n"
+
+	variables, err := s.Variables(strings.NewReader(input))
+	if err != nil || len(variables) != 2 || variables[0].Deprecated != "" || variables[1].Deprecated != "use NEW" {
+		t.Fatalf("variables, err = %v, %v", variables, err)
+	}
+	want := &Problem{Location{2, 10}, "OLD", RuleDeprecated, `"OLD" is defined, but deprecated: use NEW`}
+	if len(problems) != 1 || !reflect.DeepEqual(problems[0], want) {
+		t.Errorf("problems = %v", problems)
+	}
+
+	problems = problems[:0]
+	env, err := s.Env(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := env.Get("A"); v != "a" || err != nil || len(problems) != 1 {
+		t.Errorf("Get(A) = %v, %v, problems = %v", v, err, problems)
+	}
+	for i := 0; i < 2; i++ {
+		if v, err := env.Get("OLD"); v != "b" || err != nil {
+			t.Errorf("Get(OLD) = %v, %v", v, err)
+		}
+	}
+	want = &Problem{Location{2, 10}, "OLD", RuleDeprecated, `"OLD" is read, but deprecated: use NEW`}
+	if len(problems) != 3 || !reflect.DeepEqual(problems[1], want) || !reflect.DeepEqual(problems[2], want) {
+		t.Errorf("problems = %v", problems)
+	}
+}
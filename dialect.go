@@ -0,0 +1,102 @@
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+//ErrUnknownDialect is a line error that occurs when a version directive names a
+//dialect that does not exist. See Sourcer.VersionDirective.
+type ErrUnknownDialect string
+
+//Error is the error implementation for ErrUnknownDialect.
+func (e ErrUnknownDialect) Error() string {
+	return fmt.Sprintf("unknown dialect %q", string(e))
+}
+
+//dialects contains the dialects that can be selected with a version directive, by
+//name. Each function changes the syntax fields of a Sourcer that has the syntax
+//of NewDefault().
+var dialects = map[string]func(s *Sourcer){
+	"1": func(s *Sourcer) {},
+	"2": func(s *Sourcer) {
+		s.EmptyExport = true
+		s.SpaceAroundEqual = true
+		s.Interpolate = true
+	},
+}
+
+//versionDirectivePattern matches a version directive and captures the dialect's
+//name in either its first or second group.
+var versionDirectivePattern = regexp.MustCompile(`^#\s*(?:dotenv-version:\s*(\S+)|syntax\s*=\s*(\S+))\s*$`)
+
+//maxVersionDirective is the maximum length of a line that is checked for a
+//version directive.
+const maxVersionDirective = 256
+
+//withDialect returns a copy of s whose syntax fields, i.e. Comment, Quote,
+//Export, EmptyExport, SpaceAroundEqual, Unquote, Interpolate, InterpolatePercent,
+//PassThrough, PassThroughStrict, and LineParser, are those of the dialect name.
+//If name is not a dialect, then an ErrUnknownDialect is returned.
+func (s *Sourcer) withDialect(name string) (*Sourcer, error) {
+	apply, ok := dialects[name]
+	if !ok {
+		return nil, ErrUnknownDialect(name)
+	}
+	syntax := NewDefault()
+	result := *s
+	result.Comment, result.Quote, result.Export, result.Unquote = syntax.Comment, syntax.Quote, syntax.Export, syntax.Unquote
+	result.EmptyExport, result.SpaceAroundEqual = false, false
+	result.Interpolate, result.InterpolatePercent = false, false
+	result.PassThrough, result.PassThroughStrict = false, false
+	result.LineParser = nil
+	apply(&result)
+	return &result, nil
+}
+
+//dialectFor returns the Sourcer to parse in with, which is s with the dialect
+//selected by a version directive on the first line of in if s.VersionDirective
+//is true, and s itself otherwise. The returned io.Reader must be read instead of
+//in, and includes the directive's line.
+//If the directive names an unknown dialect, then s and an *ErrSourcing for the
+//first line are returned.
+func (s *Sourcer) dialectFor(in io.Reader) (*Sourcer, io.Reader, *ErrSourcing) {
+	if !s.VersionDirective {
+		return s, in, nil
+	}
+	buffered := bufio.NewReader(in)
+	var reader io.Reader = buffered
+	if named, ok := in.(interface{ Name() string }); ok {
+		reader = &namedReader{buffered, named.Name()}
+	}
+	head, _ := buffered.Peek(maxVersionDirective)
+	line := string(head)
+	if i := strings.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	line = strings.TrimPrefix(strings.TrimSuffix(line, "\r"), byteOrderMark)
+	match := versionDirectivePattern.FindStringSubmatch(line)
+	if match == nil {
+		return s, reader, nil
+	}
+	dialect, err := s.withDialect(match[1] + match[2])
+	if err != nil {
+		return s, reader, &ErrSourcing{1, err}
+	}
+	return dialect, reader, nil
+}
+
+//namedReader is a bufio.Reader that keeps the name of the file it reads, so that
+//errors about the file's contents can name it.
+type namedReader struct {
+	*bufio.Reader
+	name string
+}
+
+//Name returns the name of the file.
+func (r *namedReader) Name() string {
+	return r.name
+}
@@ -0,0 +1,86 @@
+package dotenv
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrUnknownDialect_Error(t *testing.T) {
+	if ErrUnknownDialect("3").Error() != `unknown dialect "3"` {
+		t.Fail()
+	}
+}
+
+func TestSourcer_VersionDirective(t *testing.T) {
+	s := &Sourcer{VersionDirective: true, Comment: ";"}
+	cases := []struct {
+		source string
+		want   [][2]string
+	}{
+		{"#dotenv-version: 1\nA=a\nB = b", nil},
+		{"#dotenv-version: 2\nA=a\nB = ${A}b\nexport\n", [][2]string{{"A", "a"}, {"B", "ab"}}},
+		{"\ufeff# syntax = 2\r\nA = a\r\n", [][2]string{{"A", "a"}}},
+		{"#syntax=1\nA=\"a # b\"\n", [][2]string{{"A", "a # b"}}},
+		{"A=a ;b\n#syntax=2\n", [][2]string{{"A", "a"}, {"#syntax", "2"}}},
+	}
+	for _, c := range cases {
+		nameVars, err := s.NameVars(strings.NewReader(c.source))
+		if c.want == nil {
+			if err == nil {
+				t.Errorf("NameVars(%q) err = nil", c.source)
+			}
+			continue
+		}
+		if err != nil || !reflect.DeepEqual(nameVars, c.want) {
+			t.Errorf("NameVars(%q) = %v, %v WANT %v", c.source, nameVars, err, c.want)
+		}
+	}
+
+	//the directive is an ordinary comment when VersionDirective is false.
+	nameVars, err := NewDefault().NameVars(strings.NewReader("#dotenv-version: 2\nA = a"))
+	if err == nil {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestSourcer_VersionDirective_unknown(t *testing.T) {
+	s := NewDefault()
+	s.VersionDirective = true
+	source := "#dotenv-version: 3\nA=a\n"
+	want := &ErrSourcing{1, ErrUnknownDialect("3")}
+	if _, err := s.NameVars(strings.NewReader(source)); !reflect.DeepEqual(err, want) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.Env(strings.NewReader(source)); !reflect.DeepEqual(err, want) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.ParseDocument(strings.NewReader(source)); !reflect.DeepEqual(err, want) {
+		t.Errorf("err = %v", err)
+	}
+	nameVars, lineErrors, err := s.NameVarsBestEffort(strings.NewReader(source))
+	if err != nil || !reflect.DeepEqual(lineErrors, []*ErrSourcing{want}) || len(nameVars) != 1 {
+		t.Errorf("NameVarsBestEffort() = %v, %v, %v", nameVars, lineErrors, err)
+	}
+}
+
+func TestSourcer_VersionDirective_files(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.env":   "#dotenv-version: 2\nA = a\ninclude b.env\n",
+		"b.env":   "B=${A}b\n",
+		"bin.env": "#dotenv-version: 2\n\x00",
+	})
+
+	s := NewDefault()
+	s.VersionDirective = true
+	s.Include = "include"
+	variables, err := s.VariablesFile(filepath.Join(dir, "a.env"))
+	if err != nil || len(variables) != 2 || variables[1].Value != "${A}b" {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+	s.Include = ""
+	if _, err := s.VariablesFile(filepath.Join(dir, "bin.env")); !reflect.DeepEqual(err, &ErrBinaryInput{filepath.Join(dir, "bin.env")}) {
+		t.Errorf("err = %v", err)
+	}
+}
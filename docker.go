@@ -0,0 +1,102 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+//DecodeDockerInspect decodes the JSON output of docker inspect, or docker
+//container inspect, from in and returns the Env of each container by name, with
+//the leading slash removed, e.g. "web" for "/web".
+//Each Env contains the entries of the container's Config.Env array, with the
+//line of each variable being the 1-based index of its entry. Values are used
+//exactly as they appear.
+//Objects without a Name, such as those from docker image inspect, are keyed by
+//their Id instead.
+//If in cannot be decoded, then that error is returned.
+func DecodeDockerInspect(in io.Reader) (map[string]*Env, error) {
+	objects := []struct {
+		ID     string `json:"Id"`
+		Name   string
+		Config struct {
+			Env []string
+		}
+	}{}
+	if err := json.NewDecoder(in).Decode(&objects); err != nil {
+		return nil, err
+	}
+	result := map[string]*Env{}
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Name, "/")
+		if name == "" {
+			name = object.ID
+		}
+		result[name] = environEnv(object.Config.Env)
+	}
+	return result, nil
+}
+
+//DecodeComposeConfig decodes the JSON output of docker compose config
+//--format json from in and returns the Env of each service by name.
+//Each Env contains the service's environment, which may be either a map of names
+//to values or an array of NAME=VALUE entries. Variables of a map are added in
+//order of name, and the line of each variable is the 1-based index of its entry.
+//Names whose value is null, i.e. that are passed through from the shell running
+//docker compose, are ignored since they have no value in the configuration.
+//Values are used exactly as they appear.
+//If in cannot be decoded, then that error is returned.
+func DecodeComposeConfig(in io.Reader) (map[string]*Env, error) {
+	config := struct {
+		Services map[string]struct {
+			Environment json.RawMessage
+		}
+	}{}
+	if err := json.NewDecoder(in).Decode(&config); err != nil {
+		return nil, err
+	}
+	result := map[string]*Env{}
+	for name, service := range config.Services {
+		env, err := composeEnvironment(service.Environment)
+		if err != nil {
+			return nil, fmt.Errorf("dotenv: environment of service %q: %v", name, err)
+		}
+		result[name] = env
+	}
+	return result, nil
+}
+
+//composeEnvironment decodes the environment of a docker compose service.
+func composeEnvironment(raw json.RawMessage) (*Env, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return newValueEnv(), nil
+	}
+	environ := []string{}
+	if raw[0] == '[' {
+		if err := json.Unmarshal(raw, &environ); err != nil {
+			return nil, err
+		}
+		return environEnv(environ), nil
+	}
+
+	values := map[string]*string{}
+	if err := json.Unmarshal(raw, &values); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	env := newValueEnv()
+	for i, name := range names {
+		if values[name] != nil {
+			env.addValue(name, *values[name], i+1)
+		}
+	}
+	return env, nil
+}
@@ -0,0 +1,73 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+//envValues returns the values of all variables in env.
+func envValues(t *testing.T, env *Env) map[string]string {
+	result := map[string]string{}
+	for _, name := range env.Names() {
+		value, err := env.Get(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		result[name] = value
+	}
+	return result
+}
+
+func TestDecodeDockerInspect(t *testing.T) {
+	in := `[
+  {"Id": "abc", "Name": "/web", "Config": {"Env": ["PATH=/bin", "A=a=b", "EMPTY="]}},
+  {"Id": "def", "Config": {"Env": null}}
+]`
+	envs, err := DecodeDockerInspect(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(envs) != 2 || len(envs["def"].Names()) != 0 {
+		t.Errorf("envs = %v", envs)
+	}
+	want := map[string]string{"PATH": "/bin", "A": "a=b", "EMPTY": ""}
+	if values := envValues(t, envs["web"]); !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v WANT %v", values, want)
+	}
+
+	if _, err := DecodeDockerInspect(strings.NewReader(`{}`)); err == nil {
+		t.Fail()
+	}
+}
+
+func TestDecodeComposeConfig(t *testing.T) {
+	in := `{
+  "name": "app",
+  "services": {
+    "web": {"image": "nginx", "environment": {"B": "b", "A": "a", "SHELL_ONLY": null}},
+    "worker": {"environment": ["C=c", "D"]},
+    "db": {"image": "postgres"}
+  }
+}`
+	envs, err := DecodeComposeConfig(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := envs["web"].Names(); !reflect.DeepEqual(names, []string{"A", "B"}) {
+		t.Errorf("names = %v", names)
+	}
+	if values := envValues(t, envs["worker"]); !reflect.DeepEqual(values, map[string]string{"C": "c"}) {
+		t.Errorf("values = %v", values)
+	}
+	if len(envs["db"].Names()) != 0 {
+		t.Fail()
+	}
+
+	_, err = DecodeComposeConfig(strings.NewReader(`{"services": {"web": {"environment": 1}}}`))
+	if err == nil || !strings.Contains(err.Error(), `service "web"`) {
+		t.Errorf("err = %v", err)
+	}
+}
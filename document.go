@@ -0,0 +1,707 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+//Document is an editable, line based representation of an input that preserves
+//the empty lines, comments, and formatting that are ignored when sourcing.
+//Lines that are not changed through a Document's methods are written exactly as
+//they were parsed.
+//
+//A Document deals with variable definitions as they are written in the input.
+//Sourcer options that change names or values after parsing, such as StripPrefix
+//and TransformValue, are not applied.
+//Changed values are quoted with strconv.Quote() when required, so a Document
+//should only be written for Sourcers whose Unquote is compatible with it, such
+//as those returned from NewDefault(). Comments can only be changed if the
+//Sourcer's Comment is not empty.
+//
+//The fields of a Document control how it is written. ParseDocument sets
+//LineEnding to that of the input, so that documents parsed from files written on
+//Windows are written back with "\r\n" line endings.
+type Document struct {
+	//LineEnding ends each line that is written, and is either LF or CRLF.
+	//ParseDocument sets it to the line ending of the first line of the input.
+	//An empty LineEnding means LF.
+	LineEnding string
+
+	//FinalNewline determines whether or not the last line that is written is
+	//followed by LineEnding.
+	FinalNewline FinalNewline
+
+	//CollapseBlankLines causes consecutive lines that are empty or contain only
+	//whitespace to be written as a single empty line, and such lines at the
+	//beginning and end of the Document to be omitted.
+	CollapseBlankLines bool
+
+	//Env is the effective environment the Document is a layer of, e.g. as
+	//returned from LoadProject, which At uses to report the effective values of
+	//variables and where they are defined.
+	//A nil Env means that effective values are not reported.
+	Env *Env
+
+	sourcer *Sourcer
+	lines   []*documentLine
+
+	//endsWithNewline is true if the input the Document was parsed from ended with a
+	//line ending.
+	endsWithNewline bool
+}
+
+const (
+	//LF is the line ending "\n".
+	LF = "\n"
+
+	//CRLF is the line ending "\r\n".
+	CRLF = "\r\n"
+)
+
+//FinalNewline determines whether or not the last line of a Document is followed
+//by a line ending when it is written.
+type FinalNewline int
+
+const (
+	//FinalNewlineAlways causes the last line to be followed by a line ending.
+	FinalNewlineAlways FinalNewline = iota
+
+	//FinalNewlinePreserve causes the last line to be followed by a line ending
+	//only if the last line of the input the Document was parsed from was.
+	FinalNewlinePreserve
+
+	//FinalNewlineNever causes the last line not to be followed by a line ending.
+	FinalNewlineNever
+)
+
+//documentLine is a single line of a Document.
+type documentLine struct {
+	//raw is the text of the line.
+	raw string
+
+	//isVariable is true if the line defines a variable, in which case the parsed
+	//fields are meaningful.
+	isVariable bool
+
+	parsedLine
+}
+
+//ParseDocument attempts to parse all lines from in into a Document.
+//If an error occurs while parsing, then that *ErrSourcing is returned.
+func (s *Sourcer) ParseDocument(in io.Reader) (*Document, error) {
+	return s.parseDocument(in, func(lineError *ErrSourcing) error {
+		return lineError
+	})
+}
+
+//ParseDocumentPartial is the same as ParseDocument except that lines that cannot
+//be parsed are kept as lines that do not define a variable, and their errors are
+//returned in order, e.g. for editors working with files that are being edited.
+//Only errors that prevent reading in, such as an ErrBinaryInput, are returned as
+//err.
+func (s *Sourcer) ParseDocumentPartial(in io.Reader) (d *Document, lineErrors []*ErrSourcing, err error) {
+	lineErrors = []*ErrSourcing{}
+	d, err = s.parseDocument(in, func(lineError *ErrSourcing) error {
+		lineErrors = append(lineErrors, lineError)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return d, lineErrors, nil
+}
+
+//parseDocument parses all lines from in into a Document.
+//Lines that cannot be parsed are passed to onError. If onError returns nil, then
+//the line is kept as a line that does not define a variable. Otherwise, parsing
+//stops and that error is returned.
+func (s *Sourcer) parseDocument(in io.Reader, onError func(lineError *ErrSourcing) error) (*Document, error) {
+	s, in, dialectErr := s.dialectFor(in)
+	if dialectErr != nil {
+		if err := onError(dialectErr); err != nil {
+			return nil, err
+		}
+	}
+	d := &Document{sourcer: s, lines: []*documentLine{}}
+	endings := &lineEndingReader{reader: in}
+	err := scanLines(endings, func(lineNumber int, line string) error {
+		parsed, err := s.nameVar(line)
+		if err != nil && err != ErrEmptyLine && err != ErrPassThrough {
+			if err := onError(&ErrSourcing{lineNumber, err}); err != nil {
+				return err
+			}
+			parsed = parsedLine{}
+		}
+		d.lines = append(d.lines, &documentLine{
+			raw:        line,
+			isVariable: err == nil,
+			parsedLine: parsed,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	d.LineEnding, d.endsWithNewline = endings.first, endings.last == '\n'
+	return d, nil
+}
+
+//lineEndingReader reads from reader and records the first line ending and the
+//last byte read.
+type lineEndingReader struct {
+	reader io.Reader
+
+	//first is the first line ending, or LF if none has been read.
+	first string
+
+	//cr is true if the last byte read is '\r', which is needed when the first line
+	//ending spans two reads.
+	cr bool
+
+	last byte
+}
+
+//Read is the io.Reader implementation for lineEndingReader.
+func (r *lineEndingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	if n > 0 {
+		if r.first == "" {
+			if i := bytes.IndexByte(p[:n], '\n'); i >= 0 {
+				r.first = LF
+				if (i > 0 && p[i-1] == '\r') || (i == 0 && r.cr) {
+					r.first = CRLF
+				}
+			}
+		}
+		r.last = p[n-1]
+		r.cr = r.last == '\r'
+	}
+	if err == io.EOF && r.first == "" {
+		r.first = LF
+	}
+	return n, err
+}
+
+//Names returns all names defined in d, without duplicates, in the order of their
+//first definition.
+func (d *Document) Names() []string {
+	result := []string{}
+	seen := map[string]bool{}
+	for _, line := range d.lines {
+		if line.isVariable && !seen[line.name] {
+			seen[line.name] = true
+			result = append(result, line.name)
+		}
+	}
+	return result
+}
+
+//Get returns the value of the last definition of name in d.
+//ok is false if name is not defined in d.
+func (d *Document) Get(name string) (value string, ok bool) {
+	if i := d.index(name); i >= 0 {
+		return d.lines[i].value, true
+	}
+	return "", false
+}
+
+//Set changes the value of the last definition of name in d to value.
+//If name is not defined in d, then a new definition is appended to d.
+//The indentation and the whitespace around the equal sign of the definition are
+//kept, and an inline comment that is aligned by padding keeps its column where
+//the new value allows it. A new definition is indented and aligned like the
+//closest preceding definition.
+//If value must be quoted and the Sourcer d was parsed with does not have
+//QuotedComments, then the inline comment is removed, since it could not be
+//parsed after the quoted value.
+func (d *Document) Set(name, value string) {
+	i := d.index(name)
+	if i < 0 {
+		d.lines = append(d.lines, &documentLine{isVariable: true})
+		i = len(d.lines) - 1
+		d.lines[i].name = name
+	}
+	d.lines[i].value = value
+	d.format(d.lines[i])
+}
+
+//Comment returns the comment on the lines immediately preceding the last
+//definition of name in d, with the Comment prefix and a single following space
+//removed from each line.
+//Multiple comment lines are joined with "\n".
+//If name is not defined in d or has no such comment, then the empty string is
+//returned.
+func (d *Document) Comment(name string) string {
+	i := d.index(name)
+	if i < 0 {
+		return ""
+	}
+	lines := []string{}
+	for _, line := range d.lines[d.commentStart(i):i] {
+		text := strings.TrimPrefix(strings.TrimLeft(line.raw, SpaceTab), d.sourcer.Comment)
+		lines = append(lines, strings.TrimPrefix(text, " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+//SetComment replaces the comment on the lines immediately preceding the last
+//definition of name in d with comment. Each line of comment is written on its
+//own line after the Comment prefix and a space.
+//An empty comment removes the existing comment.
+//If name is not defined in d, then an ErrMissingVariables is returned.
+func (d *Document) SetComment(name, comment string) error {
+	i := d.index(name)
+	if i < 0 {
+		return ErrMissingVariables{name}
+	}
+	lines := []*documentLine{}
+	if comment != "" {
+		for _, text := range strings.Split(comment, "\n") {
+			lines = append(lines, &documentLine{raw: d.commentText(text)})
+		}
+	}
+	start := d.commentStart(i)
+	d.lines = append(d.lines[:start], append(lines, d.lines[i:]...)...)
+	return nil
+}
+
+//InlineComment returns the comment following the value of the last definition
+//of name in d, with the Comment prefix and surrounding whitespace removed.
+//If name is not defined in d or has no such comment, then the empty string is
+//returned.
+func (d *Document) InlineComment(name string) string {
+	i := d.index(name)
+	if i < 0 {
+		return ""
+	}
+	return strings.Trim(d.lines[i].comment, SpaceTab)
+}
+
+//SetInlineComment replaces the comment following the value of the last
+//definition of name in d with comment.
+//An empty comment removes the existing comment.
+//If name is not defined in d, then an ErrMissingVariables is returned, and if its
+//value is quoted and the Sourcer d was parsed with does not have QuotedComments,
+//then an ErrQuotedComment is returned.
+func (d *Document) SetInlineComment(name, comment string) error {
+	i := d.index(name)
+	if i < 0 {
+		return ErrMissingVariables{name}
+	}
+	line := d.lines[i]
+	if comment != "" && line.quoted && !d.sourcer.QuotedComments {
+		return ErrQuotedComment(name)
+	}
+	line.comment, line.hasComment = " "+comment, comment != ""
+	d.format(line)
+	return nil
+}
+
+//Exported returns whether the last definition of name in d is preceded by the
+//Sourcer's Export keyword.
+func (d *Document) Exported(name string) bool {
+	i := d.index(name)
+	return i >= 0 && d.lines[i].exported
+}
+
+//SetExported adds or removes the Sourcer's Export keyword before the last
+//definition of name in d. Definitions appended by Set are not exported.
+//If the Sourcer's Export is empty, then the keyword cannot be written and the
+//line is unchanged.
+//If name is not defined in d, then an ErrMissingVariables is returned.
+func (d *Document) SetExported(name string, exported bool) error {
+	i := d.index(name)
+	if i < 0 {
+		return ErrMissingVariables{name}
+	}
+	line := d.lines[i]
+	if line.exported != exported && d.sourcer.Export != "" {
+		line.exported = exported
+		d.format(line)
+	}
+	return nil
+}
+
+//WriteTo writes all lines of d to w as described by String.
+//It implements io.WriterTo.
+func (d *Document) WriteTo(w io.Writer) (int64, error) {
+	n, err := io.WriteString(w, d.String())
+	return int64(n), err
+}
+
+//String returns all lines of d, each followed by d.LineEnding except for the last
+//line as determined by d.FinalNewline.
+func (d *Document) String() string {
+	eol := d.lineEnding()
+	lines := d.writtenLines()
+	buf := &strings.Builder{}
+	for i, raw := range lines {
+		buf.WriteString(raw)
+		if i < len(lines)-1 || d.FinalNewline == FinalNewlineAlways ||
+			(d.FinalNewline == FinalNewlinePreserve && d.endsWithNewline) {
+			buf.WriteString(eol)
+		}
+	}
+	return buf.String()
+}
+
+//lineEnding returns d.LineEnding, or LF if it is empty.
+func (d *Document) lineEnding() string {
+	if d.LineEnding == "" {
+		return LF
+	}
+	return d.LineEnding
+}
+
+//writtenLines returns the raw text of the lines of d that are written, which are
+//all of them unless d.CollapseBlankLines is true.
+func (d *Document) writtenLines() []string {
+	result := make([]string, 0, len(d.lines))
+	blank := false
+	for _, line := range d.lines {
+		isBlank := strings.Trim(line.raw, SpaceTab) == ""
+		if d.CollapseBlankLines && isBlank {
+			blank = len(result) > 0
+			continue
+		}
+		if blank {
+			result = append(result, "")
+			blank = false
+		}
+		result = append(result, line.raw)
+	}
+	return result
+}
+
+//index returns the index of the line with the last definition of name in d, or
+//-1 if name is not defined.
+func (d *Document) index(name string) int {
+	for i := len(d.lines) - 1; i >= 0; i-- {
+		if d.lines[i].isVariable && d.lines[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+//commentStart returns the index of the first line of the contiguous comment lines
+//immediately preceding the line at index i.
+func (d *Document) commentStart(i int) int {
+	comment := d.sourcer.Comment
+	for i > 0 {
+		line := d.lines[i-1]
+		text := strings.TrimLeft(line.raw, SpaceTab)
+		if line.isVariable || comment == "" || !strings.HasPrefix(text, comment) {
+			break
+		}
+		i--
+	}
+	return i
+}
+
+//commentText returns text as a comment line.
+func (d *Document) commentText(text string) string {
+	if text == "" {
+		return d.sourcer.Comment
+	}
+	return d.sourcer.Comment + " " + text
+}
+
+//format regenerates the raw text of the variable definition line from its parsed
+//fields, keeping the layout of the line as described by lineLayout.
+func (d *Document) format(line *documentLine) {
+	s := d.sourcer
+	line.quoted = needsQuote(line.value, s.Comment, s.Quote)
+	value := line.value
+	if line.quoted {
+		value = strconv.Quote(line.value)
+	}
+	layout := d.layout(line)
+	raw := layout.indent
+	if line.exported && s.Export != "" {
+		raw += layout.export
+	}
+	raw += line.name + layout.assign + value
+	if line.quoted && !s.QuotedComments {
+		line.comment, line.hasComment = "", false
+	}
+	if line.hasComment && s.Comment != "" {
+		gap := layout.gap
+		if layout.commentColumn > len(raw) {
+			gap = strings.Repeat(" ", layout.commentColumn-len(raw))
+		} else if layout.commentColumn > 0 {
+			gap = " "
+		}
+		raw += gap + s.Comment + line.comment
+	}
+	d.setRaw(line, raw)
+}
+
+//lineLayout is the whitespace around the parts of a variable definition line.
+type lineLayout struct {
+	//indent precedes the line, export is the Export keyword and the whitespace
+	//following it, and assign is the text between the name and the value.
+	indent, export, assign string
+
+	//gap precedes an inline comment. If commentColumn is greater than zero, then
+	//the comment is padded to start at that byte offset instead, so that comments
+	//that are aligned with those of surrounding lines stay aligned.
+	gap           string
+	commentColumn int
+}
+
+//layout returns the layout of line as it was last parsed.
+//Lines appended by Set have no layout of their own, so the equal sign is aligned
+//with that of the closest preceding definition if that definition aligns its
+//equal sign by padding its name.
+func (d *Document) layout(line *documentLine) lineLayout {
+	s := d.sourcer
+	layout := lineLayout{export: s.Export + " ", assign: "=", gap: " "}
+	raw := line.raw
+	if raw == "" {
+		return d.appendedLayout(line)
+	}
+	if !line.hasSpan() {
+		return layout
+	}
+	nameEnd := line.nameOffset + strings.IndexAny(raw[line.nameOffset:], SpaceTab+"=")
+	if nameEnd < line.nameOffset || nameEnd > line.valueOffset || !strings.Contains(raw[nameEnd:line.valueOffset], "=") {
+		return layout
+	}
+
+	layout.indent = raw[:skipSpaceTab(raw, 0)]
+	if keyword := raw[len(layout.indent):line.nameOffset]; keyword != "" {
+		layout.export = keyword
+	}
+	layout.assign = raw[nameEnd:line.valueOffset]
+
+	valueEnd := line.valueOffset + len(line.rawValue)
+	if commentStart := skipSpaceTab(raw, valueEnd); commentStart > valueEnd && commentStart < len(raw) {
+		layout.gap = raw[valueEnd:commentStart]
+		if len(layout.gap) > 1 {
+			layout.commentColumn = commentStart
+		}
+	}
+	return layout
+}
+
+//appendedLayout returns the layout of line, which was appended by Set.
+func (d *Document) appendedLayout(line *documentLine) lineLayout {
+	layout := lineLayout{export: d.sourcer.Export + " ", assign: "=", gap: " "}
+	var previous *documentLine
+	for _, other := range d.lines {
+		if other == line {
+			break
+		}
+		if other.isVariable && other.raw != "" && other.hasSpan() {
+			previous = other
+		}
+	}
+	if previous == nil {
+		return layout
+	}
+	aligned := d.layout(previous)
+	equal := strings.Index(aligned.assign, "=")
+	if equal <= 0 {
+		return layout
+	}
+	//the column of the equal sign in previous, which is kept for line if its
+	//name fits before it.
+	column := previous.nameOffset + len(previous.name) + equal
+	width := len(aligned.indent) + len(line.name)
+	if line.exported {
+		width += len(aligned.export)
+	}
+	padding := 1
+	if column > width {
+		padding = column - width
+	}
+	layout.indent, layout.export = aligned.indent, aligned.export
+	layout.assign = strings.Repeat(" ", padding) + aligned.assign[equal:]
+	return layout
+}
+
+//setRaw sets the raw text of the variable definition line and reparses it so
+//that its parsed fields match the text.
+func (d *Document) setRaw(line *documentLine, raw string) {
+	line.raw = raw
+	if parsed, err := d.sourcer.nameVar(raw); err == nil {
+		line.parsedLine = parsed
+	}
+}
+
+//Location is a position within a Document.
+type Location struct {
+	//Line is the line number (1-based) in the Document.
+	Line int `json:"line"`
+
+	//Column is the byte offset (1-based) within the line, or 0 if it is unknown,
+	//e.g. for lines parsed by a LineParser that is not a LineSpanParser.
+	Column int `json:"column"`
+}
+
+//ErrQuotedComment is an error that occurs when an inline comment is set after a
+//quoted value, which the Sourcer of the Document does not allow without
+//QuotedComments. Its value is the name of the variable.
+type ErrQuotedComment string
+
+//Error is the error implementation for ErrQuotedComment.
+func (e ErrQuotedComment) Error() string {
+	return fmt.Sprintf("dotenv: %v is quoted and cannot have an inline comment without QuotedComments", string(e))
+}
+
+//ErrNameDefined is an error that occurs when a name is already defined.
+type ErrNameDefined string
+
+//Error is the error implementation for ErrNameDefined.
+func (e ErrNameDefined) Error() string {
+	return fmt.Sprintf("dotenv: name %q is already defined", string(e))
+}
+
+//Rename changes the name of every definition of oldName in d to newName.
+//If the Sourcer d was parsed with has Interpolate set, then references to
+//oldName in values are also changed to reference newName.
+//
+//A reference is not changed if it cannot be rewritten safely, and its location
+//is returned in skipped instead. This is the case for references that appear
+//before the first definition of oldName, since those refer to the process's
+//environment, and for references whose syntax cannot contain newName.
+//
+//If oldName is not defined in d, then an ErrMissingVariables is returned.
+//If newName is invalid, then an ErrInvalidName is returned.
+//If newName is already defined in d, then an ErrNameDefined is returned.
+func (d *Document) Rename(oldName, newName string) (skipped []Location, err error) {
+	if d.index(oldName) < 0 {
+		return nil, ErrMissingVariables{oldName}
+	}
+	return d.rename(oldName, newName, false)
+}
+
+//rename does the work of Rename without requiring oldName to be defined in d.
+//If external is true, then references to oldName before its first definition
+//refer to a definition in another file that is renamed as well, so they are
+//changed too.
+func (d *Document) rename(oldName, newName string, external bool) (skipped []Location, err error) {
+	s := d.sourcer
+	if s.isNameInvalid(newName) {
+		return nil, ErrInvalidName(newName)
+	}
+	if d.index(newName) >= 0 {
+		return nil, ErrNameDefined(newName)
+	}
+
+	skipped = []Location{}
+	defined := external
+	for i, line := range d.lines {
+		if !line.isVariable {
+			continue
+		}
+		raw := line.raw
+		if s.Interpolate {
+			var lineSkipped []int
+			raw, lineSkipped = d.renameReferences(line, oldName, newName, defined)
+			for _, offset := range lineSkipped {
+				skipped = append(skipped, Location{i + 1, line.valueColumn(offset)})
+			}
+		}
+		if line.name == oldName {
+			defined = true
+			if !line.hasSpan() {
+				//the line cannot be changed in place, so it is written anew.
+				line.name = newName
+				d.format(line)
+				continue
+			}
+			raw = raw[:line.nameOffset] + newName + raw[line.nameOffset+len(oldName):]
+		}
+		d.setRaw(line, raw)
+	}
+	return skipped, nil
+}
+
+//References returns the location of every reference to name in the values of d,
+//in order. The Column of each Location is that of the reference's dollar or
+//percent sign.
+//If the Sourcer d was parsed with does not have Interpolate set, then values
+//contain no references and an empty slice is returned.
+func (d *Document) References(name string) []Location {
+	result := []Location{}
+	if !d.sourcer.Interpolate {
+		return result
+	}
+	for i, line := range d.lines {
+		if !line.isVariable {
+			continue
+		}
+		for _, ref := range references(line.rawValue, d.sourcer.InterpolatePercent) {
+			if ref.name == name {
+				result = append(result, Location{i + 1, line.valueColumn(ref.start)})
+			}
+		}
+	}
+	return result
+}
+
+//renameReferences returns the raw text of line with references to oldName
+//changed to newName, and the byte offsets within line.rawValue of references
+//that were not changed. References in lines whose offsets are unknown are never
+//changed.
+//defined is whether or not oldName is defined before line.
+func (d *Document) renameReferences(line *documentLine, oldName, newName string, defined bool) (string, []int) {
+	s := d.sourcer
+	raw := line.raw
+	skipped := []int{}
+	refs := references(line.rawValue, s.InterpolatePercent)
+	for i := len(refs) - 1; i >= 0; i-- {
+		ref := refs[i]
+		if ref.name != oldName {
+			continue
+		}
+		if !line.hasSpan() {
+			skipped = append([]int{ref.start}, skipped...)
+			continue
+		}
+		start, end := line.valueOffset+ref.start, line.valueOffset+ref.end
+		replacement, ok := d.referenceText(raw[start:end], newName, line.quoted)
+		if !ok || !defined {
+			skipped = append([]int{ref.start}, skipped...)
+			continue
+		}
+		raw = raw[:start] + replacement + raw[end:]
+	}
+	return raw, skipped
+}
+
+//referenceText returns the text of the reference text changed to reference name,
+//or false if name cannot be referenced with the same syntax.
+func (d *Document) referenceText(text, name string, quoted bool) (string, bool) {
+	if quoted && strings.ContainsAny(name, d.sourcer.Quote+`\`) {
+		return "", false
+	}
+	switch {
+	case strings.HasPrefix(text, "%"):
+		if strings.ContainsAny(name, SpaceTab+"%") {
+			return "", false
+		}
+		return "%" + name + "%", true
+	case strings.HasPrefix(text, "${") || !isUnbracedName(name):
+		if strings.ContainsAny(name, "${}") {
+			return "", false
+		}
+		return "${" + name + "}", true
+	}
+	return "$" + name, true
+}
+
+//isUnbracedName determines whether or not name can be referenced as $name.
+func isUnbracedName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i], i == 0) {
+			return false
+		}
+	}
+	return len(name) > 0
+}
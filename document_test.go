@@ -0,0 +1,487 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const documentSource = `# Application settings.
+
+# http listen port
+# (must be free)
+PORT=8080 # default
+export HOST="local host" #inline
+#DISABLED=1
+NAME=value
+PORT=9090`
+
+func parseTestDocument(t *testing.T) *Document {
+	d, err := NewSourcerWith(WithQuotedComments()).ParseDocument(strings.NewReader(documentSource))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestSourcer_ParseDocument(t *testing.T) {
+	d := parseTestDocument(t)
+	if d.String() != documentSource+"\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+	if !reflect.DeepEqual(d.Names(), []string{"PORT", "HOST", "NAME"}) {
+		t.Errorf("d.Names() = %v", d.Names())
+	}
+}
+
+func TestSourcer_ParseDocument_error(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader("a=1\na= b"))
+	if d != nil || !reflect.DeepEqual(err, &ErrSourcing{2, ErrInvalidWhitespaceValuePrefix(" b")}) {
+		t.Errorf("d, err = %v, %v", d, err)
+	}
+}
+
+func TestDocument_GetSet(t *testing.T) {
+	d := parseTestDocument(t)
+	if v, ok := d.Get("PORT"); v != "9090" || !ok {
+		t.Errorf("d.Get(PORT) = %v, %v", v, ok)
+	}
+	if v, ok := d.Get("HOST"); v != "local host" || !ok {
+		t.Errorf("d.Get(HOST) = %v, %v", v, ok)
+	}
+	if _, ok := d.Get("DISABLED"); ok {
+		t.Fail()
+	}
+
+	d.Set("HOST", "remote#1")
+	d.Set("NAME", "")
+	d.Set("NEW", "new value")
+	want := `# Application settings.
+
+# http listen port
+# (must be free)
+PORT=8080 # default
+export HOST="remote#1" #inline
+#DISABLED=1
+NAME=
+PORT=9090
+NEW=new value
+`
+	if d.String() != want {
+		t.Errorf("d.String() = %v WANT %v", d.String(), want)
+	}
+}
+
+func TestDocument_Comment(t *testing.T) {
+	d := parseTestDocument(t)
+	cases := map[string]string{
+		"PORT":    "",
+		"HOST":    "",
+		"NAME":    "DISABLED=1",
+		"MISSING": "",
+	}
+	for name, want := range cases {
+		if comment := d.Comment(name); comment != want {
+			t.Errorf("d.Comment(%v) = %q WANT %q", name, comment, want)
+		}
+	}
+
+	d.Set("PORT", "8080")
+	if err := d.SetComment("PORT", "http listen port\n\nsecond line"); err != nil {
+		t.Error(err)
+	}
+	if comment := d.Comment("PORT"); comment != "http listen port\n\nsecond line" {
+		t.Errorf("d.Comment(PORT) = %q", comment)
+	}
+	if err := d.SetComment("NAME", ""); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetComment("MISSING", "a"); !reflect.DeepEqual(err, ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+
+	want := `# Application settings.
+
+# http listen port
+# (must be free)
+PORT=8080 # default
+export HOST="local host" #inline
+NAME=value
+# http listen port
+#
+# second line
+PORT=8080
+`
+	if d.String() != want {
+		t.Errorf("d.String() = %v WANT %v", d.String(), want)
+	}
+}
+
+func TestDocument_InlineComment(t *testing.T) {
+	d, err := NewSourcerWith(WithQuotedComments()).ParseDocument(strings.NewReader("PORT=8080 # http port\nHOST=\"a # b\"  #  the host \nNAME=value"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"PORT":    "http port",
+		"HOST":    "the host",
+		"NAME":    "",
+		"MISSING": "",
+	}
+	for name, want := range cases {
+		if comment := d.InlineComment(name); comment != want {
+			t.Errorf("d.InlineComment(%v) = %q WANT %q", name, comment, want)
+		}
+	}
+
+	if err := d.SetInlineComment("NAME", "the name"); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetInlineComment("PORT", ""); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetInlineComment("MISSING", "a"); !reflect.DeepEqual(err, ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+	want := "PORT=8080\nHOST=\"a # b\"  #  the host \nNAME=value # the name\n"
+	if d.String() != want {
+		t.Errorf("d.String() = %q WANT %q", d.String(), want)
+	}
+
+	nameVars, err := NewSourcerWith(WithQuotedComments()).NameVars(strings.NewReader(d.String()))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"PORT", "8080"}, {"HOST", "a # b"}, {"NAME", "value"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestDocument_InlineComment_quoted(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader("HOST=\"a # b\"\nPORT=8080 # http port\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetInlineComment("HOST", "the host"); err != ErrQuotedComment("HOST") {
+		t.Errorf("err = %v", err)
+	}
+	if err := d.SetInlineComment("HOST", ""); err != nil {
+		t.Errorf("err = %v", err)
+	}
+
+	//the comment cannot follow a value that must be quoted.
+	d.Set("PORT", "80 # 443")
+	want := "HOST=\"a # b\"\nPORT=\"80 # 443\"\n"
+	if d.String() != want {
+		t.Errorf("d.String() = %q WANT %q", d.String(), want)
+	}
+	if _, err := NewDefault().NameVars(strings.NewReader(d.String())); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestDocument_Set_alignment(t *testing.T) {
+	s := NewDefault()
+	s.SpaceAroundEqual = true
+	source := `export  USER = admin # the user
+  HOST     = localhost   # the host
+  PORT     = 8080        # the port
+`
+	d, err := s.ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.Set("USER", "a b")
+	d.Set("HOST", "example.com")
+	d.Set("PORT", "1")
+	d.Set("TIMEOUT", "30")
+	d.Set("A_VERY_LONG_NAME", "1")
+	want := `export  USER = a b # the user
+  HOST     = example.com # the host
+  PORT     = 1           # the port
+  TIMEOUT  = 30
+  A_VERY_LONG_NAME = 1
+`
+	if d.String() != want {
+		t.Errorf("d.String() = %q WANT %q", d.String(), want)
+	}
+
+	nameVars, err := s.NameVars(strings.NewReader(d.String()))
+	if err != nil || len(nameVars) != 5 || nameVars[2][1] != "1" {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+}
+
+func TestDocument_String_lineEndings(t *testing.T) {
+	cases := []struct {
+		source       string
+		finalNewline FinalNewline
+		collapse     bool
+		want         string
+	}{
+		{"A=1\r\nB=2\r\n", FinalNewlineAlways, false, "A=1\r\nB=2\r\n"},
+		{"A=1\r\nB=2", FinalNewlineAlways, false, "A=1\r\nB=2\r\n"},
+		{"A=1\r\nB=2", FinalNewlinePreserve, false, "A=1\r\nB=2"},
+		{"A=1\r\nB=2\r\n", FinalNewlinePreserve, false, "A=1\r\nB=2\r\n"},
+		{"A=1\nB=2\n", FinalNewlineNever, false, "A=1\nB=2"},
+		{"A=1\nB=2\r\n", FinalNewlineAlways, false, "A=1\nB=2\n"},
+		{"\n\nA=1\n\n \n\t\n# c\nB=2\n\n", FinalNewlineAlways, true, "A=1\n\n# c\nB=2\n"},
+		{"\n\nA=1\n\n \n", FinalNewlineAlways, false, "\n\nA=1\n\n \n"},
+		{"", FinalNewlineAlways, false, ""},
+	}
+	for _, c := range cases {
+		//a reader that returns a byte at a time splits "\r\n" across reads.
+		d, err := NewDefault().ParseDocument(iotest.OneByteReader(strings.NewReader(c.source)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		d.FinalNewline, d.CollapseBlankLines = c.finalNewline, c.collapse
+		if d.String() != c.want {
+			t.Errorf("%q: d.String() = %q WANT %q", c.source, d.String(), c.want)
+		}
+	}
+
+	d, err := NewDefault().ParseDocument(strings.NewReader("A=1\r\n"))
+	if err != nil || d.LineEnding != CRLF {
+		t.Fatalf("d, err = %v, %v", d, err)
+	}
+	d.Set("B", "2")
+	d.LineEnding = LF
+	if d.String() != "A=1\nB=2\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+}
+
+func TestDocument_Exported(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader("export A=1\nB=2 # b\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !d.Exported("A") || d.Exported("B") || d.Exported("MISSING") {
+		t.Error("Exported")
+	}
+	if err := d.SetExported("A", false); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetExported("B", true); err != nil {
+		t.Error(err)
+	}
+	if err := d.SetExported("MISSING", true); !reflect.DeepEqual(err, ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+	d.Set("C", "3")
+	if want := "A=1\nexport B=2 # b\nC=3\n"; d.String() != want {
+		t.Errorf("d.String() = %q WANT %q", d.String(), want)
+	}
+	if !d.Exported("B") || d.Exported("C") {
+		t.Error("Exported after SetExported")
+	}
+
+	s := NewDefault()
+	s.Export = ""
+	if d, err = s.ParseDocument(strings.NewReader("A=1\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.SetExported("A", true); err != nil || d.String() != "A=1\n" {
+		t.Errorf("d.String(), err = %q, %v", d.String(), err)
+	}
+}
+
+func TestDocument_WriteTo(t *testing.T) {
+	d := parseTestDocument(t)
+	out := &strings.Builder{}
+	n, err := d.WriteTo(out)
+	if err != nil || n != int64(len(documentSource)+1) || out.String() != documentSource+"\n" {
+		t.Errorf("d.WriteTo() = %v, %v", n, err)
+	}
+}
+
+func TestErrNameDefined_Error(t *testing.T) {
+	if ErrNameDefined("a").Error() != `dotenv: name "a" is already defined` {
+		t.Fail()
+	}
+}
+
+func TestDocument_Rename(t *testing.T) {
+	source := `A=$OLD
+export  OLD=old # the old one
+B=${OLD}/$OLD_X/$OLDX
+C="$OLD and %OLD%"
+OLD=again
+`
+	s := NewDefault()
+	s.Interpolate = true
+	s.InterpolatePercent = true
+	d, err := s.ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped, err := d.Rename("OLD", "NEW")
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(skipped, []Location{{1, 3}}) {
+		t.Errorf("skipped = %v", skipped)
+	}
+	want := `A=$OLD
+export  NEW=old # the old one
+B=${NEW}/$OLD_X/$OLDX
+C="$NEW and %NEW%"
+NEW=again
+`
+	if d.String() != want {
+		t.Errorf("d.String() = %v WANT %v", d.String(), want)
+	}
+	if v, ok := d.Get("NEW"); v != "again" || !ok {
+		t.Errorf("d.Get(NEW) = %v, %v", v, ok)
+	}
+}
+
+func TestDocument_Rename_unbracedName(t *testing.T) {
+	s := NewDefault()
+	s.Interpolate = true
+	s.InterpolatePercent = true
+	d, err := s.ParseDocument(strings.NewReader("OLD=1\nA=$OLD.x\nB=\"$OLD\"\nC=%OLD%\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped, err := d.Rename("OLD", `a.b"c`)
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(skipped, []Location{{3, 4}}) {
+		t.Errorf("skipped = %v", skipped)
+	}
+	want := "a.b\"c=1\nA=${a.b\"c}.x\nB=\"$OLD\"\nC=%a.b\"c%\n"
+	if d.String() != want {
+		t.Errorf("d.String() = %q WANT %q", d.String(), want)
+	}
+}
+
+func TestDocument_Rename_noInterpolate(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader("OLD=1\nA=$OLD\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped, err := d.Rename("OLD", "NEW")
+	if err != nil || len(skipped) != 0 {
+		t.Errorf("skipped, err = %v, %v", skipped, err)
+	}
+	if d.String() != "NEW=1\nA=$OLD\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+}
+
+func TestDocument_Rename_unknownSpan(t *testing.T) {
+	s := NewDefault()
+	s.Interpolate = true
+	s.LineParser = LineParserFunc(func(line string) (name, v string, err error) {
+		name, v, err = s.ParseLine(line)
+		return "PREFIX_" + strings.ToUpper(name), v, err
+	})
+	d, err := s.ParseDocument(strings.NewReader("x=1\ny=$PREFIX_X\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skipped, err := d.Rename("PREFIX_X", "Y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(skipped, []Location{{2, 0}}) {
+		t.Errorf("skipped = %v", skipped)
+	}
+	if d.String() != "Y=1\ny=$PREFIX_X\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+	if refs := d.References("PREFIX_X"); !reflect.DeepEqual(refs, []Location{{2, 0}}) {
+		t.Errorf("refs = %v", refs)
+	}
+	d.Set("PREFIX_Y", "2")
+	if d.String() != "Y=1\nPREFIX_Y=2\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+}
+
+func TestDocument_Rename_lineSpanParser(t *testing.T) {
+	s := NewDefault()
+	s.Interpolate = true
+	s.LineParser = colonSpanParser{}
+	d, err := s.ParseDocument(strings.NewReader("x: 1\ny:  $x\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs := d.References("x"); !reflect.DeepEqual(refs, []Location{{2, 5}}) {
+		t.Errorf("refs = %v", refs)
+	}
+	skipped, err := d.Rename("x", "Y")
+	if err != nil || !reflect.DeepEqual(skipped, []Location{}) {
+		t.Errorf("skipped, err = %v, %v", skipped, err)
+	}
+	if d.String() != "Y: 1\ny:  $Y\n" {
+		t.Errorf("d.String() = %q", d.String())
+	}
+}
+
+//colonSpanParser is a LineSpanParser of lines of the form "name: value".
+type colonSpanParser struct{}
+
+func (p colonSpanParser) ParseLine(line string) (string, string, error) {
+	name, v, _, err := p.ParseLineSpan(line)
+	return name, v, err
+}
+
+func (colonSpanParser) ParseLineSpan(line string) (string, string, LineSpan, error) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return "", "", LineSpan{}, ErrEmptyLine
+	}
+	value := skipSpaceTab(line, i+1)
+	return line[:i], line[value:], LineSpan{0, value, len(line)}, nil
+}
+
+func TestDocument_Rename_errors(t *testing.T) {
+	d := parseTestDocument(t)
+	if _, err := d.Rename("MISSING", "NEW"); !reflect.DeepEqual(err, ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := d.Rename("PORT", "a b"); err != ErrInvalidName("a b") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := d.Rename("PORT", "HOST"); err != ErrNameDefined("HOST") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestDocument_References(t *testing.T) {
+	source := `A=$NAME
+# $NAME
+NAME=1
+  export B = "${NAME} %NAME%" # $NAME
+C=$NAMES$$NAME
+`
+	s := NewSourcerWith(WithQuotedComments())
+	s.SpaceAroundEqual = true
+	d, err := s.ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refs := d.References("NAME"); len(refs) != 0 {
+		t.Errorf("refs = %v", refs)
+	}
+
+	s.Interpolate = true
+	want := []Location{{1, 3}, {4, 15}}
+	if refs := d.References("NAME"); !reflect.DeepEqual(refs, want) {
+		t.Errorf("refs = %v WANT %v", refs, want)
+	}
+
+	s.InterpolatePercent = true
+	want = []Location{{1, 3}, {4, 15}, {4, 23}}
+	if refs := d.References("NAME"); !reflect.DeepEqual(refs, want) {
+		t.Errorf("refs = %v WANT %v", refs, want)
+	}
+	if refs := d.References("MISSING"); len(refs) != 0 {
+		t.Errorf("refs = %v", refs)
+	}
+}
@@ -0,0 +1,108 @@
+//Package doppler resolves secrets stored in Doppler, https://www.doppler.com,
+//and imports the secrets of a Doppler config into a dotenv.Env.
+package doppler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gogolfing/dotenv"
+)
+
+//Scheme is the resolver scheme of Doppler references, e.g. "doppler:DB_PASSWORD".
+const Scheme = "doppler"
+
+//DefaultBaseURL is the base URL of the Doppler API.
+const DefaultBaseURL = "https://api.doppler.com"
+
+//Client fetches the secrets of a single Doppler config.
+//It is a dotenv.BatchResolver whose references are secret names, and every call
+//fetches all secrets of the config with one request.
+type Client struct {
+	//Token is the Doppler access token, typically a service token.
+	Token string
+
+	//Project and Config identify the config whose secrets are fetched. They may
+	//be empty if Token is a service token, which is scoped to a single config.
+	Project string
+	Config  string
+
+	//BaseURL is the base URL of the API. An empty BaseURL means DefaultBaseURL.
+	BaseURL string
+
+	//HTTPClient sends requests. A nil HTTPClient means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+//Secrets returns all secrets of the config by name.
+//Failed requests and server errors return a *dotenv.ErrTransient, and all other
+//error responses a *dotenv.ErrPermanent, so that c can be wrapped with
+//dotenv.WithRetry.
+func (c *Client) Secrets(ctx context.Context) (map[string]string, error) {
+	baseURL, httpClient := c.BaseURL, c.HTTPClient
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	query := url.Values{"format": {"json"}}
+	if c.Project != "" {
+		query.Set("project", c.Project)
+	}
+	if c.Config != "" {
+		query.Set("config", c.Config)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/v3/configs/config/secrets/download?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &dotenv.ErrTransient{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("doppler: %v: %s", resp.Status, body)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &dotenv.ErrTransient{Err: err}
+		}
+		return nil, &dotenv.ErrPermanent{Err: err}
+	}
+	secrets := map[string]string{}
+	if err := json.NewDecoder(resp.Body).Decode(&secrets); err != nil {
+		return nil, fmt.Errorf("doppler: decoding secrets: %v", err)
+	}
+	return secrets, nil
+}
+
+//Resolve implements dotenv.Resolver by returning the secret named ref.
+//If the config has no such secret, then a *dotenv.ErrPermanent is returned.
+func (c *Client) Resolve(ctx context.Context, ref string) (string, error) {
+	return c.resolver().Resolve(ctx, ref)
+}
+
+//ResolveBatch implements dotenv.BatchResolver by returning the secrets named
+//refs that exist in the config.
+func (c *Client) ResolveBatch(ctx context.Context, refs []string) (map[string]string, error) {
+	return c.resolver().ResolveBatch(ctx, refs)
+}
+
+//Env fetches all secrets of the config and returns them as a dotenv.Env parsed
+//by s, as with dotenv.FetchResolver.Env.
+func (c *Client) Env(ctx context.Context, s *dotenv.Sourcer) (*dotenv.Env, error) {
+	return c.resolver().Env(ctx, s)
+}
+
+//resolver returns the dotenv.FetchResolver that fetches with c.Secrets.
+func (c *Client) resolver() *dotenv.FetchResolver {
+	return &dotenv.FetchResolver{Scheme: Scheme, Fetch: c.Secrets}
+}
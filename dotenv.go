@@ -1,14 +1,29 @@
 //Package dotenv provides a Sourcer type that allows client code to source
 //environment variable inputs and set the values in the process via os.Setenv().
+//
+//Parsing is defined for all inputs. Every input either parses or causes one of
+//the errors documented by the method that parses it, e.g. the line errors of
+//Sourcer.NameVar wrapped in an *ErrSourcing, in time linear in the length of the
+//input. Parsing never panics unless a function the Sourcer is configured with,
+//such as Unquote or TransformValue, panics.
+//
+//The package builds for all platforms, including js/wasm and wasip1, where
+//methods that set variables return an ErrUnsupportedPlatform and all other
+//methods work as they do elsewhere.
+//
+//The core parser also compiles with TinyGo. With the tinygo build tag, which
+//TinyGo sets, NewDefault uses Unquote instead of strconv.Unquote, and the
+//features that decode JSON with reflection or run processes, i.e. the docker,
+//ECS, and Kubernetes decoders, DiskCache, GenerateConfig, and ResolveCommand, are
+//left out.
 package dotenv
 
 import (
-	"bufio"
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strconv"
 	"strings"
 )
 
@@ -86,6 +101,46 @@ func (e ErrInvalidName) Error() string {
 	return fmt.Sprintf("name %q is invalid", string(e))
 }
 
+//ErrUnsetPassThrough is a line error that occurs when a name passed through by
+//Sourcer.PassThrough is not set in the process's environment and
+//Sourcer.PassThroughStrict is true.
+type ErrUnsetPassThrough string
+
+//Error is the error implementation for ErrUnsetPassThrough.
+func (e ErrUnsetPassThrough) Error() string {
+	return fmt.Sprintf("pass through variable %q is not set", string(e))
+}
+
+//ErrMissingVariables is an error that occurs when variables explicitly requested
+//by name are not defined in an input.
+//It contains the missing names in the order they were requested.
+type ErrMissingVariables []string
+
+//Error is the error implementation for ErrMissingVariables.
+func (e ErrMissingVariables) Error() string {
+	return fmt.Sprintf("dotenv: variables not defined %q", []string(e))
+}
+
+//ErrUndefinedReference is a line error that occurs when a value references a
+//name that is not defined earlier in the input and Sourcer.InterpolateHermetic
+//is true.
+type ErrUndefinedReference string
+
+//Error is the error implementation for ErrUndefinedReference.
+func (e ErrUndefinedReference) Error() string {
+	return fmt.Sprintf("reference to undefined variable %q", string(e))
+}
+
+//ErrLineTooLong is an error that occurs when a line of an input is longer than
+//the limit of a Sourcer, which is bufio.MaxScanTokenSize. Its value is the limit.
+//It is returned wrapped in an *ErrSourcing for the line.
+type ErrLineTooLong int
+
+//Error is the error implementation for ErrLineTooLong.
+func (e ErrLineTooLong) Error() string {
+	return fmt.Sprintf("line is longer than %d bytes", int(e))
+}
+
 //ErrEmptyLine is a sentinel error value that is returned from Sourcer.NameVar()
 //that tells a Sourcer that a line is effectively empty (contains only whitespace
 //or whitespace and a comment).
@@ -93,10 +148,30 @@ func (e ErrInvalidName) Error() string {
 //in this package. It is simply used internally for parsing purposes.
 var ErrEmptyLine = errors.New("empty line")
 
+//ErrPassThrough is a sentinel error value that is returned from Sourcer.NameVar()
+//along with a name when Sourcer.PassThrough is true and a line contains only that
+//name (and possibly the export keyword and a comment).
+//It tells a Sourcer to use the value of name from the process's environment.
+//Note that, like ErrEmptyLine, this is not a semantic error and will never be
+//returned from any other methods in this package.
+var ErrPassThrough = errors.New("pass through")
+
+//SkipRemaining is a sentinel error value that is returned from the function
+//passed to Sourcer.Visit to stop reading the input without an error, e.g. once
+//the variables that are needed have been found.
+var SkipRemaining = errors.New("skip remaining")
+
 //Sourcer is a container for parsing parameters relevant to sourcing environment
 //variable inputs.
 //A Sourcer is able to take in an io.Reader (or file path) and set the environment
 //variables defined in the input on the process via os.Setenv().
+//
+//The methods of a Sourcer never change its fields, so a Sourcer may be used by
+//multiple goroutines concurrently as long as none of them changes its fields.
+//Use Clone() to change a copy for a single call, and Freeze() to share a
+//configuration that cannot be changed.
+//Note that methods that set variables change the process's environment, which is
+//shared by all goroutines.
 type Sourcer struct {
 	//Comment denotes the beginning of a comment on a line.
 	//An empty Comment value means that all commenting is disallowed.
@@ -114,20 +189,225 @@ type Sourcer struct {
 	//An empty Export value means that no keyword prefix is allowed.
 	Export string
 
+	//EmptyExport causes lines that contain only Export and possibly a comment,
+	//e.g. "export" or "export #comment", to be treated as empty lines instead of
+	//causing an ErrNonVariableLine. POSIX shells accept such lines when sourcing.
+	EmptyExport bool
+
+	//SpaceAroundEqual allows whitespace between a name and the equal sign and
+	//between the equal sign and the value, e.g. "NAME   = value". The whitespace
+	//is not part of the name or value.
+	//This allows for definitions that are aligned on their equal signs.
+	SpaceAroundEqual bool
+
 	//Unquote is a function that is called to unquote a variable's value definition
 	//if the value starts and ends with Quote.
-	//It must not be nil if any variables have the surrounding Quotes.
+	//A nil Unquote means that values are not unquoted, as if Quote were empty.
 	Unquote func(s string) (t string, err error)
+
+	//QuotedComments allows a quoted value to be followed by whitespace and a
+	//comment, e.g. `A="b" #c`, and the comment is not part of the value.
+	//By default a quoted value must end its line, and such lines cause an
+	//*ErrValueUnclosedQuote.
+	QuotedComments bool
+
+	//StripPrefix is removed from the beginning of every variable name that starts
+	//with it before the variable is set or returned.
+	//This allows a single input to define namespaced variables for multiple
+	//applications, e.g. "MYAPP_DATABASE_URL" is applied as "DATABASE_URL".
+	//An empty StripPrefix value means that names are left unchanged.
+	StripPrefix string
+
+	//PrefixOnly causes variables whose names do not start with StripPrefix to be
+	//ignored entirely.
+	//PrefixOnly has no effect if StripPrefix is empty.
+	PrefixOnly bool
+
+	//Resolvers resolve values that refer to data stored elsewhere. A value of
+	//the form SCHEME:REF, where SCHEME is a key in Resolvers, is replaced by the
+	//result of resolving REF with that Resolver, e.g. "file:/run/secrets/db".
+	//Resolution happens after interpolation and before TransformValue is called.
+	//A Resolver error is treated as a line error wrapped in an *ErrResolve.
+	//Values whose prefix is not a key in Resolvers are left unchanged.
+	//See Sourcer.Env for resolving values lazily.
+	Resolvers map[string]Resolver
+
+	//TransformValue is called with every variable's name and value after the
+	//value has been unquoted, and its result is used as the variable's value.
+	//A non-nil error is treated as a line error.
+	//Use Transforms() to compose multiple Transforms.
+	//A nil TransformValue means that values are used as parsed.
+	TransformValue Transform
+
+	//Policy is called with every variable's name and value after TransformValue,
+	//and a non-nil error is treated as a line error. This allows rejecting values
+	//such as plaintext credentials. Use NameVarsBestEffort() to find every
+	//violation in an input, and Policies() to compose multiple Policies.
+	//A nil Policy means that all values are allowed.
+	Policy Policy
+
+	//Interpolate enables the expansion of $NAME and ${NAME} references in values.
+	//References are resolved against variables defined earlier in the same input
+	//and then with Lookup, which defaults to the process's environment.
+	//Undefined references expand to the empty string. "$$" expands to a literal
+	//"$".
+	//Interpolation happens after a value is unquoted and before TransformValue is
+	//called.
+	Interpolate bool
+
+	//Lookup resolves interpolated references to names that are not defined
+	//earlier in the same input. References that Lookup does not resolve expand
+	//to the empty string.
+	//Use LookupChain() to resolve names against multiple sources, e.g. a map,
+	//then the process's environment, then a default.
+	//A nil Lookup means that os.LookupEnv is used.
+	Lookup LookupFunc
+
+	//InterpolateHermetic restricts interpolation to variables defined earlier in
+	//the same input, so that values never depend on the invoking environment.
+	//A reference to any other name causes an ErrUndefinedReference line error,
+	//and Lookup is not used.
+	//InterpolateHermetic has no effect if Interpolate is false.
+	InterpolateHermetic bool
+
+	//InterpolatePercent enables the expansion of Windows batch style %NAME%
+	//references in addition to those enabled by Interpolate. "%%" expands to a
+	//literal "%".
+	//InterpolatePercent has no effect if Interpolate is false.
+	InterpolatePercent bool
+
+	//OSSuffix enables platform specific variables whose names end with a dot and
+	//a known GOOS value, e.g. "PATH.windows".
+	//If the suffix is the target operating system, then the variable is used with
+	//the suffix removed. Otherwise the variable is ignored.
+	//Names ending in any other suffix are left unchanged. Since later definitions
+	//take precedence, platform specific variables should be defined after their
+	//generic counterparts.
+	OSSuffix bool
+
+	//GOOS is the target operating system for OSSuffix.
+	//An empty GOOS value means that runtime.GOOS is used.
+	GOOS string
+
+	//PassThrough allows lines that contain only a name, e.g. "NAME" or
+	//"export NAME", to pass the value of that name through from the process's
+	//environment. If the name is not set in the environment, then the line is
+	//ignored. This matches the semantics of docker-compose environment files.
+	//Note that "NAME=" still defines NAME with the empty value.
+	//The name is looked up as it appears in the input, before StripPrefix or
+	//OSSuffix are applied, and the value is never interpolated.
+	//If PassThrough is false, then such lines cause an ErrNonVariableLine.
+	PassThrough bool
+
+	//PassThroughStrict causes an ErrUnsetPassThrough line error, instead of the
+	//line being ignored, when a name passed through by PassThrough is not set in
+	//the process's environment.
+	//PassThroughStrict has no effect if PassThrough is false.
+	PassThroughStrict bool
+
+	//Include denotes a directive that includes another file in place of the
+	//directive's line, e.g. "include" allows lines such as "include common.env".
+	//The path follows the directive after whitespace, may be quoted with Quote,
+	//and is relative to the directory of the including file.
+	//Includes are only followed by the methods that read files by path, i.e.
+	//SourceFile, SourceFileContext, SourceFiles, and VariablesFile, and their
+	//nesting is limited by MaxIncludeDepth and MaxIncludeFiles. All other methods
+	//treat directives as lines that do not define a variable.
+	//See IncludeGraph for the files included by a file.
+	//An empty Include value means that includes are disallowed.
+	Include string
+
+	//MaxIncludeDepth is the maximum number of nested includes below the file
+	//that is read by path. A zero MaxIncludeDepth means DefaultMaxIncludeDepth.
+	MaxIncludeDepth int
+
+	//MaxIncludeFiles is the maximum number of includes that are followed while
+	//reading a single file by path, counting a file every time it is included.
+	//A zero MaxIncludeFiles means DefaultMaxIncludeFiles.
+	MaxIncludeFiles int
+
+	//Metrics receives measurements of lines parsed, line errors, variables set,
+	//and Resolver calls.
+	//A nil Metrics means that measurements are discarded.
+	Metrics Metrics
+
+	//Tracer starts spans around SourceFile and Resolver calls.
+	//A nil Tracer means that no spans are started.
+	Tracer Tracer
+
+	//LineParser parses every line instead of the syntax defined by the fields
+	//Comment, Quote, Export, EmptyExport, SpaceAroundEqual, Unquote, and
+	//PassThrough, so that inputs with other syntaxes, such as Java properties
+	//or batch files, can be sourced. All other processing, such as
+	//interpolation, resolving, and TransformValue, still applies to the parsed
+	//names and values.
+	//Documents can be parsed with a LineParser, but changed lines are written
+	//in the syntax defined by the fields. The positions of names and values in
+	//lines are only known if it is a LineSpanParser, and are otherwise reported
+	//with a Column of 0.
+	//A nil LineParser means that lines are parsed with the syntax defined by the
+	//fields. See Sourcer.ParseLine.
+	LineParser LineParser
+
+	//VersionDirective enables a directive on the first line of an input that
+	//selects the dialect its lines are parsed with, of the form
+	//"#dotenv-version: NAME" or "#syntax=NAME". A dialect replaces the syntax
+	//fields of the Sourcer, i.e. Comment, Quote, Export, EmptyExport,
+	//SpaceAroundEqual, Unquote, Interpolate, InterpolatePercent, PassThrough,
+	//PassThroughStrict, and LineParser, while all other fields still apply.
+	//The dialects are:
+	//
+	//	1: the syntax of NewDefault().
+	//	2: dialect 1 with EmptyExport, SpaceAroundEqual, and Interpolate.
+	//
+	//A directive with an unknown dialect causes an ErrUnknownDialect line error.
+	//Inputs without a directive are parsed with the Sourcer's own syntax.
+	VersionDirective bool
+
+	//LineFilter is called with every line, and its line number (1-based), before
+	//the line is parsed. If it returns false, then the line is skipped as if it
+	//were empty.
+	//A nil LineFilter means that all lines are parsed.
+	LineFilter func(line string, n int) bool
+
+	//NoOverride causes variables that are already set in the process's
+	//environment when a method starts setting variables to be left unchanged by
+	//it. Variables set by the method itself are still overwritten by later
+	//definitions, so the last definition of a name is the one that is set unless
+	//the name was set before. Plan reports such names as PlanUnchanged.
+	//With NoOverride, the methods that set variables use memory proportional to
+	//the number of distinct names they set.
+	NoOverride bool
+
+	//Warn is called with problems that do not stop sourcing. These are
+	//definitions of variables annotated as deprecated with a comment of the form
+	//"#deprecated: use NEW_NAME" on their line, with the Rule RuleDeprecated,
+	//and reads of such variables through Env.Get. Warn may be called
+	//concurrently by an Env.
+	//A nil Warn means that warnings are discarded. See LintDeprecated.
+	Warn func(problem *Problem)
+
+	//Aliases maps old names of variables to their new names, so that renaming a
+	//variable does not break inputs and code that still use the old name.
+	//Definitions of an old name define its new name instead, references to an
+	//old name refer to the new name when interpolating, and Env.Get returns the
+	//value of the new name for an old name. The names are those after
+	//StripPrefix and OSSuffix are applied, and aliases are not followed
+	//transitively. Definitions and Env.Get reads of old names are passed to Warn
+	//with the Rule RuleAlias.
+	//A nil Aliases means that names are not mapped.
+	Aliases map[string]string
 }
 
 //NewSourcer returns a Sourcer with Comment, Quote, Export, and Unquote set to
 //DefaultComment, DefaultQuote, DefaultExport, and strconv.Unquote respectively.
+//In TinyGo builds, Unquote is set to the Unquote function of this package instead.
 func NewDefault() *Sourcer {
 	return &Sourcer{
 		Comment: DefaultComment,
 		Quote:   DefaultQuote,
 		Export:  DefaultExport,
-		Unquote: strconv.Unquote,
+		Unquote: defaultUnquote,
 	}
 }
 
@@ -136,12 +416,42 @@ func NewDefault() *Sourcer {
 //If an error occurs while parsing or setting values, then an *ErrSourcing is returned.
 //The opened file is then closed and that possible error returned.
 //SourceFile uses s.Source() to do the work on the file.
-func (s *Sourcer) SourceFile(path string) error {
+//If s.Include is not empty, then included files are sourced in place of their
+//directives, and errors in included files are returned as an *ErrInclude. All
+//includes are found before any variable is set.
+//opts change a copy of s for this call only, as described by Source.
+func (s *Sourcer) SourceFile(path string, opts ...Option) error {
+	return s.SourceFileContext(context.Background(), path, opts...)
+}
+
+//SourceFileContext is the same as SourceFile except that ctx is passed to
+//Resolvers and used as the parent of the span started with s.Tracer.
+func (s *Sourcer) SourceFileContext(ctx context.Context, path string, opts ...Option) error {
+	s = s.with(opts)
+	return s.sourceFile(ctx, path, s.newSetter())
+}
+
+//sourceFile does the work of SourceFileContext, setting variables with st.
+func (s *Sourcer) sourceFile(ctx context.Context, path string, st *setter) (err error) {
+	ctx, span := s.tracer().StartSpan(ctx, "dotenv.SourceFile", Attribute{AttributeFile, path})
+	count := 0
+	defer func() {
+		span.SetAttributes(Attribute{AttributeVariableCount, count})
+		span.End(err)
+	}()
+
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	if s.Include != "" {
+		count, err = s.sourceIncludes(ctx, path, st)
+		return err
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return err
 	}
-	if err := s.Source(file); err != nil {
+	if count, err = s.sourceContext(ctx, file, st); err != nil {
 		return err
 	}
 	return file.Close()
@@ -151,13 +461,151 @@ func (s *Sourcer) SourceFile(path string) error {
 //As soon as an error occurs while parsing or setting values, then that
 //*ErrSourcing is returned and reading stops.
 //Therefore, Source is not guaranteed to read all of in.
+//Source uses memory proportional to the longest line of in, as described by
+//Visit.
 //Upon completion with a nil return value, all parsed name, value associations
 //will have been called in os.Setenv().
-func (s *Sourcer) Source(in io.Reader) error {
-	return s.sourceVisitor(in, os.Setenv)
+//
+//opts change a copy of s for this call only, so a Sourcer that is shared, e.g.
+//by multiple goroutines, can be used with different options per call without
+//being changed, e.g. s.Source(in, WithNoOverride(), WithStripPrefix("APP_", true)).
+func (s *Sourcer) Source(in io.Reader, opts ...Option) error {
+	s = s.with(opts)
+	_, err := s.sourceContext(context.Background(), in, s.newSetter())
+	return err
 }
 
-//not guaranteed to read all of in.
+//Visit attempts to parse all variable definitions from in and calls visit with
+//each of them in order, without keeping any of them. The Variable passed to visit
+//is not used by Visit afterwards.
+//As soon as an error occurs while parsing, then that *ErrSourcing is returned
+//and reading stops. If visit returns an error, then that error is returned and
+//reading stops, except for SkipRemaining, which stops reading and causes Visit
+//to return nil. The rest of in is then left unread.
+//
+//Visit, like Source, uses memory proportional to the longest line of in rather
+//than to its size, so inputs of any size can be streamed through it. The only
+//exception is s.Interpolate, which requires the values of all names defined so
+//far, i.e. memory proportional to the number of distinct names in in.
+func (s *Sourcer) Visit(in io.Reader, visit func(variable *Variable) error) error {
+	s, in, dialectErr := s.dialectFor(in)
+	if dialectErr != nil {
+		return dialectErr
+	}
+	defined := map[string]string{}
+	err := scanLines(in, func(lineNumber int, line string) error {
+		visited := false
+		err := s.visitLine(context.Background(), lineNumber, line, defined, func(variable *Variable) error {
+			visited = true
+			return visit(variable)
+		})
+		if err != nil && !visited {
+			return &ErrSourcing{lineNumber, err}
+		}
+		return err
+	})
+	if err == SkipRemaining {
+		return nil
+	}
+	return err
+}
+
+//LookupIn returns the value of the first definition of name in in, reading only
+//as much of in as is needed to find it. ok is false if name is not defined in in.
+//As with Visit, an *ErrSourcing is returned if a line before the definition
+//cannot be parsed. Lines after it are not parsed, so errors in them are not
+//reported, and a later definition of name, which Source would set instead, is
+//not found. Use LookupLastIn for the value that Source would set.
+func (s *Sourcer) LookupIn(in io.Reader, name string) (value string, ok bool, err error) {
+	err = s.Visit(in, func(variable *Variable) error {
+		if variable.Name != name {
+			return nil
+		}
+		value, ok = variable.Value, true
+		return SkipRemaining
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return value, ok, nil
+}
+
+//LookupLastIn is the same as LookupIn except that all of in is read and the value
+//of the last definition of name is returned, which is the value Source would set.
+func (s *Sourcer) LookupLastIn(in io.Reader, name string) (value string, ok bool, err error) {
+	err = s.Visit(in, func(variable *Variable) error {
+		if variable.Name == name {
+			value, ok = variable.Value, true
+		}
+		return nil
+	})
+	if err != nil {
+		return "", false, err
+	}
+	return value, ok, nil
+}
+
+//sourceContext does the work of Source with ctx, setting variables with st, and
+//returns the number of variables set.
+func (s *Sourcer) sourceContext(ctx context.Context, in io.Reader, st *setter) (count int, err error) {
+	if err := checkSetenv(); err != nil {
+		return 0, err
+	}
+	err = s.sourceLineVisitorContext(ctx, in, func(_ int, name, v string) error {
+		ok, err := st.setenv(name, v)
+		if ok {
+			count++
+		}
+		return err
+	})
+	return count, err
+}
+
+//SourceOnly attempts to parse all variable definitions from in and set only those
+//whose names are in names.
+//All of in is parsed before any variable is set. If an error occurs while parsing,
+//then that *ErrSourcing is returned and nothing is set.
+//If any of names is not defined in in, then an ErrMissingVariables is returned
+//and nothing is set.
+//As with Source, the last definition of a name in in is the one that is set.
+func (s *Sourcer) SourceOnly(in io.Reader, names ...string) error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	wanted := make(map[string]bool, len(names))
+	for _, name := range names {
+		wanted[name] = true
+	}
+
+	found := map[string]string{}
+	err := s.sourceVisitor(in, func(name, v string) error {
+		if wanted[name] {
+			found[name] = v
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	missing := ErrMissingVariables{}
+	for _, name := range names {
+		if _, ok := found[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return missing
+	}
+
+	st := s.newSetter()
+	for _, name := range names {
+		if _, err := st.setenv(name, found[name]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
 //NameVars attempts parse and return all variable definitions from in.
 //As soon as an error occurs while parsing or setting values, then that
@@ -165,7 +613,11 @@ func (s *Sourcer) Source(in io.Reader) error {
 //Therefore, NameVars is not guaranteed to read all of in.
 //The return value nameVars will contain all name, value associations found from
 //in with name at array index 0 and value at index 1.
-func (s *Sourcer) NameVars(in io.Reader) (nameVars [][2]string, err error) {
+//Since nameVars holds every definition, NameVars uses memory proportional to the
+//size of in. Use Visit for inputs of unbounded size.
+//opts change a copy of s for this call only, as described by Source.
+func (s *Sourcer) NameVars(in io.Reader, opts ...Option) (nameVars [][2]string, err error) {
+	s = s.with(opts)
 	result := [][2]string{}
 	err = s.sourceVisitor(in, func(name, v string) error {
 		result = append(result, [2]string{name, v})
@@ -177,28 +629,185 @@ func (s *Sourcer) NameVars(in io.Reader) (nameVars [][2]string, err error) {
 	return result, nil
 }
 
+//NameVarsBestEffort attempts to parse all variable definitions from in, skipping
+//over lines that cannot be parsed instead of stopping at the first one.
+//The return value nameVars will contain all name, value associations that were
+//successfully parsed, in the same form as NameVars.
+//lineErrors will contain an *ErrSourcing for every line that could not be parsed.
+//err is only non-nil if reading from in fails, in which case nameVars and
+//lineErrors contain the results of the lines read before the failure.
+func (s *Sourcer) NameVarsBestEffort(in io.Reader) (nameVars [][2]string, lineErrors []*ErrSourcing, err error) {
+	nameVars = [][2]string{}
+	lineErrors = []*ErrSourcing{}
+	s, in, dialectErr := s.dialectFor(in)
+	if dialectErr != nil {
+		lineErrors = append(lineErrors, dialectErr)
+	}
+	defined := map[string]string{}
+	err = scanLines(in, func(lineNumber int, line string) error {
+		lineErr := s.visitLine(context.Background(), lineNumber, line, defined, func(variable *Variable) error {
+			nameVars = append(nameVars, [2]string{variable.Name, variable.Value})
+			return nil
+		})
+		if lineErr != nil {
+			lineErrors = append(lineErrors, &ErrSourcing{lineNumber, lineErr})
+		}
+		return nil
+	})
+	return nameVars, lineErrors, err
+}
+
 //sourceVisitor actually does the work of reading from in using a bufio.Scanner
 //to read, parse, and visit all lines from in.
 func (s *Sourcer) sourceVisitor(in io.Reader, visit func(name, v string) error) error {
-	lineNumber := 0
-	scanner := bufio.NewScanner(in)
+	return s.sourceLineVisitor(in, func(_ int, name, v string) error {
+		return visit(name, v)
+	})
+}
 
-	for scanner.Scan() {
-		line := scanner.Text()
-		lineNumber++
-		name, v, err := s.NameVar(line)
+//sourceLineVisitor is the same as sourceVisitor except that visit is also called
+//with the line number that name and v were defined on.
+func (s *Sourcer) sourceLineVisitor(in io.Reader, visit func(line int, name, v string) error) error {
+	return s.sourceLineVisitorContext(context.Background(), in, visit)
+}
 
-		if err == ErrEmptyLine {
-			continue
-		}
+//sourceLineVisitorContext is the same as sourceLineVisitor except that ctx is
+//passed to Resolvers.
+func (s *Sourcer) sourceLineVisitorContext(ctx context.Context, in io.Reader, visit func(line int, name, v string) error) error {
+	s, in, dialectErr := s.dialectFor(in)
+	if dialectErr != nil {
+		return dialectErr
+	}
+	defined := map[string]string{}
+	return scanLines(in, func(lineNumber int, line string) error {
+		err := s.visitLine(ctx, lineNumber, line, defined, func(variable *Variable) error {
+			return visit(lineNumber, variable.Name, variable.Value)
+		})
 		if err != nil {
 			return &ErrSourcing{lineNumber, err}
 		}
-		if err := visit(name, v); err != nil {
-			return &ErrSourcing{lineNumber, err}
+		return nil
+	})
+}
+
+//scanLines calls fn with every line, and its line number, read from in.
+//Line endings may be "\n" or "\r\n" and are not included in line. A UTF-8 byte
+//order mark at the beginning of in is removed.
+//If in appears to be an encrypted file, then an ErrEncrypted is returned and fn
+//is not called. If it appears to be binary, then an *ErrBinaryInput is returned
+//for the first binary line instead of calling fn with it.
+//If fn returns an error, then scanning stops and that error is returned.
+func scanLines(in io.Reader, fn func(lineNumber int, line string) error) error {
+	ls := lineScanners.Get().(*lineScanner)
+	defer lineScanners.Put(ls)
+	return ls.scan(in, fn)
+}
+
+//visitLine parses line and calls visit with the resulting name and value, unless
+//line is effectively empty, filtered out by s.LineFilter, or its variable is
+//ignored.
+//defined contains the variables visited so far and is updated if s.Interpolate
+//is true. ctx is passed to Resolvers.
+//The returned error is a line error that has not been wrapped in an ErrSourcing.
+func (s *Sourcer) visitLine(ctx context.Context, lineNumber int, line string, defined map[string]string, visit func(variable *Variable) error) error {
+	err := s.visitVariable(ctx, lineNumber, line, defined, visit)
+	if err != nil {
+		s.metrics().LineError()
+	}
+	return err
+}
+
+//visitVariable does the work of visitLine other than recording line errors.
+func (s *Sourcer) visitVariable(ctx context.Context, lineNumber int, line string, defined map[string]string, visit func(variable *Variable) error) error {
+	variable, passThrough, err := s.parseVariable(lineNumber, line)
+	if err != nil || variable == nil {
+		return err
+	}
+	v := variable.Value
+	if s.Interpolate && !passThrough {
+		if v, err = s.interpolate(v, defined); err != nil {
+			return err
+		}
+	}
+	if variable.Value, err = s.finishValue(ctx, variable.Name, v, nil); err != nil {
+		return err
+	}
+	if err := visit(variable); err != nil {
+		return err
+	}
+	if s.Interpolate {
+		defined[variable.Name] = variable.Value
+	}
+	return nil
+}
+
+//parseVariable parses line and returns the variable it defines, with its name
+//fixed and its value as written, or pass through from the process's environment.
+//passThrough is true if the value was passed through.
+//variable is nil if line is effectively empty, filtered out by s.LineFilter, or
+//its variable is ignored.
+//The returned error is a line error that has not been wrapped in an ErrSourcing.
+func (s *Sourcer) parseVariable(lineNumber int, line string) (variable *Variable, passThrough bool, err error) {
+	s.metrics().LineParsed()
+	if s.LineFilter != nil && !s.LineFilter(line, lineNumber) {
+		return nil, false, nil
+	}
+
+	parsed, err := s.nameVar(line)
+	name, v, quoted, exported := parsed.name, parsed.value, parsed.quoted, parsed.exported
+	passThrough = err == ErrPassThrough
+
+	if err == ErrEmptyLine {
+		return nil, false, nil
+	}
+	if passThrough {
+		var set bool
+		if v, set = os.LookupEnv(name); !set {
+			if s.PassThroughStrict {
+				return nil, false, ErrUnsetPassThrough(name)
+			}
+			return nil, false, nil
 		}
+	} else if err != nil {
+		return nil, false, err
 	}
-	return scanner.Err()
+
+	fixed, ok, err := s.fixName(name)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	if newName := s.alias(fixed); newName != fixed {
+		s.warn(aliasProblem(Location{lineNumber, parsed.nameOffset + 1}, fixed, newName, "defined"))
+		fixed = newName
+	}
+	variable = &Variable{Name: fixed, Value: v, Line: lineNumber, Quoted: quoted, Exported: exported}
+	if message, column := s.deprecation(line, parsed); message != "" {
+		variable.Deprecated = message
+		s.warn(deprecatedProblem(Location{lineNumber, column}, fixed, "defined", message))
+	}
+	return variable, passThrough, nil
+}
+
+//finishValue returns the final value of the variable name whose interpolated
+//value is v, by resolving it with s.Resolvers, calling s.TransformValue, and
+//checking it with s.Policy.
+//cache contains results of previous resolutions and may be nil.
+func (s *Sourcer) finishValue(ctx context.Context, name, v string, cache *resolveCache) (string, error) {
+	v, err := s.resolve(ctx, name, v, cache)
+	if err != nil {
+		return "", err
+	}
+	if s.TransformValue != nil {
+		if v, err = s.TransformValue(name, v); err != nil {
+			return "", err
+		}
+	}
+	if s.Policy != nil {
+		if err := s.Policy(name, v); err != nil {
+			return "", err
+		}
+	}
+	return v, nil
 }
 
 //NameVar attempts to parse a single line and return the name, value association
@@ -208,47 +817,289 @@ func (s *Sourcer) sourceVisitor(in io.Reader, visit func(name, v string) error)
 //simply parses and does not know about the purpose of the return values.
 //The error ErrEmptyLine will be returned with empty name and v if line contains
 //only whitespace or whitespace and a comment.
+//The error ErrPassThrough will be returned with name and an empty v if
+//s.PassThrough is true and line contains only name.
+//Otherwise, the error is one of ErrNonVariableLine, ErrInvalidName,
+//ErrInvalidWhitespaceValuePrefix, *ErrValueUnclosedQuote, or an error returned by
+//s.Unquote.
+//If s.LineParser is not nil, then it parses line instead.
 func (s *Sourcer) NameVar(line string) (name, v string, err error) {
-	origLine := line
+	parsed, err := s.nameVar(line)
+	return parsed.name, parsed.value, err
+}
+
+//ParseLine parses line with the syntax defined by the fields of s, i.e. without
+//s.LineParser, in the same way as NameVar.
+//It implements LineParser, so that a LineParser can delegate lines it does not
+//recognize to a Sourcer.
+func (s *Sourcer) ParseLine(line string) (name, v string, err error) {
+	parsed, err := s.parseLine(line)
+	return parsed.name, parsed.value, err
+}
+
+//ParseLineSpan is ParseLine that also returns where the name and value are in
+//line. It implements LineSpanParser.
+func (s *Sourcer) ParseLineSpan(line string) (name, v string, span LineSpan, err error) {
+	parsed, err := s.parseLine(line)
+	span = LineSpan{parsed.nameOffset, parsed.valueOffset, parsed.valueOffset + len(parsed.rawValue)}
+	return parsed.name, parsed.value, span, err
+}
+
+//LineParser parses single lines of an input into the variables they define.
+//See Sourcer.LineParser.
+type LineParser interface {
+	//ParseLine returns the name and value of the variable defined on line.
+	//It returns ErrEmptyLine if line does not define a variable but is valid,
+	//e.g. a comment, and ErrPassThrough with a name if the value of name should
+	//be passed through from the process's environment. Any other error is a
+	//line error.
+	ParseLine(line string) (name, v string, err error)
+}
+
+//LineSpanParser is a LineParser that also reports where the name and value are
+//in the lines it parses, so that Documents can change the lines in place and
+//report their locations.
+type LineSpanParser interface {
+	LineParser
+
+	//ParseLineSpan returns the same as ParseLine, and the position of name and
+	//the value in line if err is nil.
+	ParseLineSpan(line string) (name, v string, span LineSpan, err error)
+}
+
+//LineSpan is the position of the name and the value of a variable in a line, as
+//byte offsets.
+type LineSpan struct {
+	//Name is the offset of the name, which must appear in the line as it was
+	//returned.
+	Name int
+
+	//Value and ValueEnd are the offsets of the start and the end of the value as
+	//it appears in the line, e.g. including any quotes. They must be after the
+	//name.
+	Value, ValueEnd int
+}
+
+//LineParserFunc is an adapter that allows the use of an ordinary function as a
+//LineParser.
+type LineParserFunc func(line string) (name, v string, err error)
+
+//ParseLine calls f(line).
+func (f LineParserFunc) ParseLine(line string) (name, v string, err error) {
+	return f(line)
+}
+
+//parsedLine is the result of parsing a single line with Sourcer.nameVar.
+type parsedLine struct {
+	//name and value are the name and value of the variable defined on the line.
+	name, value string
+
+	//quoted is true if the value was surrounded by Quote.
+	quoted bool
+
+	//exported is true if the line started with Export.
+	exported bool
+
+	//comment is the text after Comment following the value, if hasComment is true.
+	comment    string
+	hasComment bool
+
+	//nameOffset and valueOffset are the byte offsets of the name and value in the
+	//line. They are -1 if they are unknown, see hasSpan.
+	nameOffset, valueOffset int
+
+	//rawValue is the value as it appears in the line, including any Quotes.
+	rawValue string
+}
+
+//nameVar is the implementation of NameVar. It additionally returns details about
+//how the variable was defined in line.
+func (s *Sourcer) nameVar(line string) (parsed parsedLine, err error) {
+	if s.LineParser == nil {
+		return s.parseLine(line)
+	}
+	parsed = parsedLine{nameOffset: -1, valueOffset: -1}
+	spanParser, ok := s.LineParser.(LineSpanParser)
+	if !ok {
+		parsed.name, parsed.value, err = s.LineParser.ParseLine(line)
+		parsed.rawValue = parsed.value
+		return parsed, err
+	}
+	name, v, span, err := spanParser.ParseLineSpan(line)
+	parsed.name, parsed.value, parsed.rawValue = name, v, v
+	nameEnd := span.Name + len(name)
+	if err == nil && span.Name >= 0 && nameEnd <= span.Value && span.Value <= span.ValueEnd &&
+		span.ValueEnd <= len(line) && line[span.Name:nameEnd] == name {
+		parsed.nameOffset, parsed.valueOffset = span.Name, span.Value
+		parsed.rawValue = line[span.Value:span.ValueEnd]
+	}
+	return parsed, err
+}
+
+//hasSpan determines whether or not the offsets of l are known. They are unknown
+//for lines parsed by a LineParser that is not a LineSpanParser, or that returned
+//an invalid LineSpan, in which case rawValue is the value.
+func (l *parsedLine) hasSpan() bool {
+	return l.nameOffset >= 0
+}
+
+//valueColumn returns the column (1-based) of the byte offset within rawValue in
+//the line, or 0 if the offsets of l are unknown.
+func (l *parsedLine) valueColumn(offset int) int {
+	if !l.hasSpan() {
+		return 0
+	}
+	return l.valueOffset + offset + 1
+}
 
-	//get rid of any whitespace at the start of the line. doesn't really matter.
-	line = strings.TrimLeft(line, SpaceTab)
+//parseLine parses line with the syntax defined by the fields of s.
+//It scans line once, recording the positions of whitespace and comments in the
+//name as it looks for the equal sign, and then scans the value once.
+func (s *Sourcer) parseLine(line string) (parsed parsedLine, err error) {
+	//skip any whitespace at the start of the line. doesn't really matter.
+	start := skipSpaceTab(line, 0)
 
 	//check for s.Export at beginning of line.
-	if strings.HasPrefix(line, s.Export) && s.Export != "" {
-		line = strings.TrimPrefix(line, s.Export)
-		line = strings.TrimLeft(line, SpaceTab)
-		if len(line) == 0 || strings.HasPrefix(line, s.Comment) {
-			return "", "", ErrNonVariableLine(origLine)
+	if s.Export != "" && strings.HasPrefix(line[start:], s.Export) {
+		parsed.exported = true
+		start = skipSpaceTab(line, start+len(s.Export))
+		rest := line[start:]
+		if s.EmptyExport && (len(rest) == 0 || s.hasCommentAt(rest, 0)) {
+			return parsed, ErrEmptyLine
 		}
+		if len(rest) == 0 || strings.HasPrefix(rest, s.Comment) {
+			return parsed, ErrNonVariableLine(line)
+		}
+	}
+	rest := line[start:]
+
+	//a line that is empty or starts with a comment is an empty line.
+	if len(rest) == 0 || s.hasCommentAt(rest, 0) {
+		return parsed, ErrEmptyLine
 	}
 
-	//check for Equal in the line.
-	equalIndex := strings.Index(line, "=")
-	if equalIndex < 0 {
-		line = strings.TrimLeft(line, SpaceTab)
-		if len(line) == 0 || (strings.HasPrefix(line, s.Comment) && s.Comment != "") {
-			return "", "", ErrEmptyLine
+	//scan the name up to Equal, recording the first whitespace, the first
+	//comment, and the end of the name without trailing whitespace.
+	equal, space, comment, end := -1, -1, -1, 0
+	for i := 0; i < len(rest); i++ {
+		c := rest[i]
+		if c == '=' {
+			equal = i
+			break
+		}
+		if comment < 0 && s.hasCommentAt(rest, i) {
+			comment = i
+		}
+		if isSpaceTab(c) {
+			if space < 0 {
+				space = i
+			}
+			continue
 		}
-		return "", "", ErrNonVariableLine(origLine)
+		end = i + 1
 	}
 
-	//get name and varible parts of the line. trim the name.
-	name, v = strings.TrimLeft(line[:equalIndex], SpaceTab), line[equalIndex+1:]
+	if equal < 0 {
+		//the line contains only a name, possibly followed by a comment.
+		if comment >= 0 {
+			end = len(strings.TrimRight(rest[:comment], SpaceTab))
+			comment = -1
+		}
+		if s.PassThrough && s.isNameRangeValid(end, space, comment) {
+			return parsedLine{name: rest[:end]}, ErrPassThrough
+		}
+		return parsed, ErrNonVariableLine(line)
+	}
 
-	//if a comment appears at the beginning name (before Equal) then it is a comment line.
-	if strings.HasPrefix(strings.TrimLeft(line, SpaceTab), s.Comment) && s.Comment != "" {
-		return "", "", ErrEmptyLine
+	//allow for whitespace around Equal if aligned definitions are allowed.
+	if !s.SpaceAroundEqual {
+		end = equal
+	}
+	name, valueStart := rest[:end], equal+1
+	if s.SpaceAroundEqual {
+		valueStart = skipSpaceTab(rest, valueStart)
 	}
 
 	//evaluate name for errors.
-	if s.isNameInvalid(name) {
-		return "", "", ErrInvalidName(name)
+	if !s.isNameRangeValid(end, space, comment) {
+		return parsed, ErrInvalidName(name)
 	}
 
 	//fix and return variable part with possible error.
-	v, err = s.fixVariable(v)
-	return name, v, err
+	parsed.name = name
+	parsed.nameOffset = start
+	parsed.valueOffset = start + valueStart
+	if err := s.fixVariable(rest[valueStart:], &parsed); err != nil {
+		return parsedLine{name: name}, err
+	}
+	return parsed, nil
+}
+
+//isNameRangeValid determines whether or not the name ending at end is valid,
+//given the index of the first whitespace and the first comment in the line the
+//name starts, which are negative if there are none.
+func (s *Sourcer) isNameRangeValid(end, space, comment int) bool {
+	return end > 0 &&
+		(space < 0 || space >= end) &&
+		(comment < 0 || comment+len(s.Comment) > end)
+}
+
+//hasCommentAt determines whether or not s.Comment is non-empty and starts at
+//index i of text.
+func (s *Sourcer) hasCommentAt(text string, i int) bool {
+	return s.Comment != "" && text[i] == s.Comment[0] && strings.HasPrefix(text[i:], s.Comment)
+}
+
+//isSpaceTab determines whether or not c is in SpaceTab.
+func isSpaceTab(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+//skipSpaceTab returns the index of the first byte of text at or after i that is
+//not in SpaceTab.
+func skipSpaceTab(text string, i int) int {
+	for i < len(text) && isSpaceTab(text[i]) {
+		i++
+	}
+	return i
+}
+
+//sourcedName returns the name that a variable parsed as name is sourced as, i.e.
+//with s.StripPrefix, s.OSSuffix, and s.Aliases applied.
+//ok is false if s ignores name.
+func (s *Sourcer) sourcedName(name string) (string, bool) {
+	fixed, ok, err := s.fixName(name)
+	if err != nil || !ok {
+		return "", false
+	}
+	return s.alias(fixed), true
+}
+
+//fixName returns the name to visit for a variable parsed as name.
+//ok is false if the variable should be ignored because of s.PrefixOnly or
+//s.OSSuffix.
+func (s *Sourcer) fixName(name string) (result string, ok bool, err error) {
+	result, ok, err = s.stripPrefix(name)
+	if !ok || err != nil || !s.OSSuffix {
+		return
+	}
+	return s.selectOS(result)
+}
+
+//stripPrefix removes s.StripPrefix from name.
+//ok is false if name does not have the prefix and s.PrefixOnly is true.
+func (s *Sourcer) stripPrefix(name string) (result string, ok bool, err error) {
+	if s.StripPrefix == "" {
+		return name, true, nil
+	}
+	if !strings.HasPrefix(name, s.StripPrefix) {
+		return name, !s.PrefixOnly, nil
+	}
+	result = strings.TrimPrefix(name, s.StripPrefix)
+	if len(result) == 0 {
+		return "", false, ErrInvalidName(name)
+	}
+	return result, true, nil
 }
 
 //isNameInvalid determines whether or not name is valid in s.
@@ -258,38 +1109,103 @@ func (s *Sourcer) isNameInvalid(name string) bool {
 		(strings.Contains(name, s.Comment) && s.Comment != "")
 }
 
-//fixVariable returns the actual variable value to set parsed from v.
+//fixVariable sets the actual variable value, and possible comment, parsed from
+//v in parsed.
 //v should be the remainder of a line after the first equal sign.
 //It may contain a comment.
-func (s *Sourcer) fixVariable(v string) (string, error) {
-	origV := v
-
+func (s *Sourcer) fixVariable(v string, parsed *parsedLine) error {
 	//if v is empty, then just return the empty string and no error.
 	if len(v) == 0 {
-		return v, nil
+		return nil
 	}
 
 	//if v starts with s.Quote, then assume it either ends with one and unquote
 	//or v should be returned literally.
-	if strings.HasPrefix(v, s.Quote) && s.Quote != "" {
-		//if starts and ends with quote but not equal to quote.
-		if strings.HasSuffix(v, s.Quote) && v != s.Quote {
-			return s.Unquote(v)
+	if s.Quote != "" && s.Unquote != nil && strings.HasPrefix(v, s.Quote) {
+		return s.fixQuoted(v, parsed)
+	}
+
+	//scan for a comment, recording the end of the value without trailing
+	//whitespace.
+	end := 0
+	for i := 0; i < len(v); i++ {
+		if s.hasCommentAt(v, i) {
+			parsed.comment, parsed.hasComment = v[i+len(s.Comment):], true
+			break
+		}
+		if !isSpaceTab(v[i]) {
+			end = i + 1
 		}
-		return "", &ErrValueUnclosedQuote{origV, s.Quote}
 	}
 
-	//if there is a comment, then get rid of it.
-	commentIndex := strings.Index(v, s.Comment)
-	if commentIndex >= 0 && s.Comment != "" {
-		v = v[:commentIndex]
+	if end > 0 && isSpaceTab(v[0]) {
+		return ErrInvalidWhitespaceValuePrefix(v)
 	}
-	//trim any right whitespace.
-	v = strings.TrimRight(v, SpaceTab)
 
-	if v != strings.TrimLeft(v, SpaceTab) {
-		return "", ErrInvalidWhitespaceValuePrefix(origV)
+	parsed.value, parsed.rawValue = v[:end], v[:end]
+	return nil
+}
+
+//maxUnquoteAttempts is the maximum number of times fixQuoted calls Unquote for a
+//single value, which bounds the time spent on values with many comments.
+const maxUnquoteAttempts = 64
+
+//fixQuoted sets the unquoted value, and possible comment, parsed from v in parsed.
+//v must start with s.Quote. The quoted value may be followed by whitespace and a
+//comment if s.QuotedComments is true.
+//At most maxUnquoteAttempts ends of the quoted value are tried, after which the
+//value is treated as unclosed.
+func (s *Sourcer) fixQuoted(v string, parsed *parsedLine) error {
+	//the quoted value can end at the end of v or at the beginning of any comment,
+	//which are tried in that order.
+	var unquoteErr error
+	attempts := 0
+	for end := len(v); end >= 0 && attempts < maxUnquoteAttempts; end = s.nextComment(v, end) {
+		quoted := v[:end]
+		if s.QuotedComments {
+			quoted = strings.TrimRight(quoted, SpaceTab)
+		}
+		//if starts and ends with quote but not equal to quote.
+		if !strings.HasSuffix(quoted, s.Quote) || quoted == s.Quote {
+			continue
+		}
+		attempts++
+		value, err := s.Unquote(quoted)
+		if err != nil {
+			if end == len(v) {
+				unquoteErr = err
+			}
+			continue
+		}
+		parsed.value, parsed.quoted, parsed.rawValue = value, true, quoted
+		if end < len(v) {
+			parsed.comment, parsed.hasComment = v[end+len(s.Comment):], true
+		}
+		return nil
 	}
 
-	return v, nil
+	if unquoteErr != nil {
+		return unquoteErr
+	}
+	return &ErrValueUnclosedQuote{v, s.Quote}
+}
+
+//nextComment returns the index of the first comment in v after the opening Quote
+//that follows end, where end is the previous result or len(v) to find the first
+//comment. It returns -1 if there are no more comments.
+//It always returns -1 unless s.QuotedComments is true.
+func (s *Sourcer) nextComment(v string, end int) int {
+	if !s.QuotedComments {
+		return -1
+	}
+	i := len(s.Quote)
+	if end < len(v) {
+		i = end + 1
+	}
+	for ; s.Comment != "" && i < len(v); i++ {
+		if s.hasCommentAt(v, i) {
+			return i
+		}
+	}
+	return -1
 }
@@ -2,37 +2,32 @@ package dotenv
 
 import (
 	"fmt"
-	"os"
 	"strings"
 )
 
-func Example() {
-	source := `
-name1=value1
-export name2=value2
-name3="Hello\nWorld"
-
-name4=value4 # this is a comment
-
-# this is a comment as well
+func ExampleLineParser() {
+	source := `REM settings for the build
+SET GOOS=windows
+SET GOARCH=amd64
 `
 
 	sourcer := NewDefault()
-
-	err := sourcer.Source(strings.NewReader(source))
-
+	sourcer.LineParser = LineParserFunc(func(line string) (name, v string, err error) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "REM ") {
+			return "", "", ErrEmptyLine
+		}
+		if !strings.HasPrefix(line, "SET ") {
+			return "", "", ErrNonVariableLine(line)
+		}
+		return sourcer.ParseLine(strings.TrimPrefix(line, "SET "))
+	})
+
+	nameVars, err := sourcer.NameVars(strings.NewReader(source))
 	if err != nil {
 		fmt.Println(err)
 	}
-
-	fmt.Println(os.Getenv("name1"))
-	fmt.Println(os.Getenv("name2"))
-	fmt.Println(os.Getenv("name3"))
-	fmt.Println(os.Getenv("name4"))
+	fmt.Println(nameVars)
 	//Output:
-	//value1
-	//value2
-	//Hello
-	//World
-	//value4
+	//[[GOOS windows] [GOARCH amd64]]
 }
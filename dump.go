@@ -0,0 +1,118 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+//DumpMask is the text written by DumpEffective in place of masked values.
+const DumpMask = "********"
+
+//DumpOptions are the options for DumpEffective.
+type DumpOptions struct {
+	//Sourcer is used to parse Files. If it is nil, then NewDefault() is used.
+	Sourcer *Sourcer
+
+	//Files are the paths of the files that are sourced, in order, so that later
+	//files override earlier ones.
+	Files []string
+
+	//Environ includes the variables of the process's environment that are not
+	//defined in Files, with the origin "environment".
+	Environ bool
+
+	//Reveal disables masking, so that all values are written as they are.
+	Reveal bool
+
+	//Mask determines whether or not the value of name is masked. If it is nil,
+	//then IsSecretLike is used.
+	Mask func(name, value string) bool
+}
+
+//secretNamePattern matches names that conventionally hold secrets.
+var secretNamePattern = regexp.MustCompile(`(?i)(SECRET|PASSWORD|PASSWD|PASSPHRASE|TOKEN|PRIVATE|CREDENTIAL|API_?KEY|ACCESS_?KEY|AUTH)`)
+
+//IsSecretLike determines whether or not a variable looks like it holds a secret,
+//either because its name suggests so, e.g. DB_PASSWORD or API_TOKEN, because
+//DetectSecrets detects a credential in value, or because value contains a token
+//reported by an EntropyDetector returned from NewEntropyDetector().
+//Empty values are never secret like.
+func IsSecretLike(name, value string) bool {
+	if value == "" {
+		return false
+	}
+	if secretNamePattern.MatchString(name) || DetectSecrets(name, value) != nil {
+		return true
+	}
+	_, _, ok := NewEntropyDetector().detect(value)
+	return ok
+}
+
+//DumpEffective writes the effective variables from sourcing options.Files to w,
+//one per line in sorted order, in the form NAME=VALUE # ORIGIN, where ORIGIN is
+//the file and line of the definition that takes effect, e.g. ".env:3".
+//Values are fully resolved, quoted with strconv.Quote() if needed, and replaced
+//by DumpMask if they are masked.
+//options may be nil, in which case nothing is written.
+//If a file cannot be read or parsed, then that error is returned.
+func DumpEffective(w io.Writer, options *DumpOptions) error {
+	if options == nil {
+		options = &DumpOptions{}
+	}
+	s, mask := options.Sourcer, options.Mask
+	if s == nil {
+		s = NewDefault()
+	}
+	if mask == nil {
+		mask = IsSecretLike
+	}
+
+	effective := map[string]*Variable{}
+	for _, path := range options.Files {
+		variables, err := s.VariablesFile(path)
+		if err != nil {
+			return err
+		}
+		for _, variable := range variables {
+			effective[variable.Name] = variable
+		}
+	}
+	origins := map[string]string{}
+	for name, variable := range effective {
+		origins[name] = fmt.Sprintf("%v:%v", variable.File, variable.Line)
+	}
+	if options.Environ {
+		for _, pair := range os.Environ() {
+			name, value := pair, ""
+			if i := strings.Index(pair, "="); i > 0 {
+				name, value = pair[:i], pair[i+1:]
+			}
+			if _, ok := effective[name]; !ok {
+				effective[name] = &Variable{Name: name, Value: value}
+				origins[name] = "environment"
+			}
+		}
+	}
+
+	names := make([]string, 0, len(effective))
+	for name := range effective {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	buf := &strings.Builder{}
+	for _, name := range names {
+		value := effective[name].Value
+		if !options.Reveal && mask(name, value) {
+			value = DumpMask
+		} else {
+			value = MaybeQuote(value)
+		}
+		fmt.Fprintf(buf, "%v=%v # %v\n", name, value, origins[name])
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
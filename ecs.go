@@ -0,0 +1,102 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+)
+
+//ECSOptions are the options for EncodeECS.
+type ECSOptions struct {
+	//Secret determines whether or not the variable name with value is written to
+	//the secrets array, and returns the valueFrom to write for it, e.g. the ARN
+	//of a Secrets Manager secret or the name of an SSM parameter.
+	//A nil Secret means that ECSSecretARN is used.
+	Secret func(name, value string) (valueFrom string, ok bool)
+}
+
+//ECSSecretARN returns value as a valueFrom if it is the ARN of a Secrets Manager
+//secret or an SSM parameter, i.e. it starts with "arn:aws:secretsmanager:" or
+//"arn:aws:ssm:". Partitions other than aws, such as aws-cn, are also recognized.
+func ECSSecretARN(name, value string) (string, bool) {
+	parts := strings.SplitN(value, ":", 4)
+	if len(parts) < 4 || parts[0] != "arn" || !strings.HasPrefix(parts[1], "aws") {
+		return "", false
+	}
+	return value, parts[2] == "secretsmanager" || parts[2] == "ssm"
+}
+
+//ecsContainer is the part of an ECS container definition that contains its
+//environment.
+type ecsContainer struct {
+	Environment []*ecsVariable `json:"environment"`
+	Secrets     []*ecsSecret   `json:"secrets"`
+}
+
+//ecsVariable is an entry of a container definition's environment array.
+type ecsVariable struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+//ecsSecret is an entry of a container definition's secrets array.
+type ecsSecret struct {
+	Name      string `json:"name"`
+	ValueFrom string `json:"valueFrom"`
+}
+
+//EncodeECS writes nameVars to w as the environment and secrets arrays of an
+//ECS task definition's container definition, i.e. a JSON object of the form
+//{"environment": [{"name": "A", "value": "a"}], "secrets": [{"name": "B",
+//"valueFrom": "arn:..."}]}.
+//Names appear in the order of their first definition in nameVars with the value
+//of their last definition. Variables for which options.Secret returns true are
+//written to secrets, and all others to environment.
+//options may be nil.
+func EncodeECS(w io.Writer, nameVars [][2]string, options *ECSOptions) error {
+	if options == nil {
+		options = &ECSOptions{}
+	}
+	secret := options.Secret
+	if secret == nil {
+		secret = ECSSecretARN
+	}
+	container := &ecsContainer{Environment: []*ecsVariable{}, Secrets: []*ecsSecret{}}
+	for _, nameVar := range lastDefinitions(nameVars) {
+		if valueFrom, ok := secret(nameVar[0], nameVar[1]); ok {
+			container.Secrets = append(container.Secrets, &ecsSecret{nameVar[0], valueFrom})
+		} else {
+			container.Environment = append(container.Environment, &ecsVariable{nameVar[0], nameVar[1]})
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(container)
+}
+
+//DecodeECS decodes an ECS container definition, or any JSON object with
+//environment and secrets arrays such as the output of EncodeECS, from in into an
+//Env.
+//Variables of environment are defined first, in order, followed by those of
+//secrets, whose value is their valueFrom. Secrets are not fetched, so the result
+//can be compared against, or encoded back from, unresolved .env files.
+//The line of each variable is the 1-based index of its entry across both
+//arrays. Values are used exactly as they appear.
+//If in cannot be decoded, then that error is returned.
+func DecodeECS(in io.Reader) (*Env, error) {
+	container := &ecsContainer{}
+	if err := json.NewDecoder(in).Decode(container); err != nil {
+		return nil, err
+	}
+	env := newValueEnv()
+	for _, variable := range container.Environment {
+		env.addValue(variable.Name, variable.Value, len(env.definitions)+1)
+	}
+	for _, secret := range container.Secrets {
+		env.addValue(secret.Name, secret.ValueFrom, len(env.definitions)+1)
+	}
+	return env, nil
+}
@@ -0,0 +1,91 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestECSSecretARN(t *testing.T) {
+	cases := map[string]bool{
+		"arn:aws:secretsmanager:us-east-1:123:secret:db-AbC": true,
+		"arn:aws:ssm:us-east-1:123:parameter/app/key":        true,
+		"arn:aws-cn:ssm:cn-north-1:123:parameter/key":        true,
+		"arn:aws:s3:::bucket/key":                            false,
+		"arn:aws:ssm":                                        false,
+		"secretsmanager:db":                                  false,
+		"":                                                   false,
+	}
+	for value, want := range cases {
+		valueFrom, ok := ECSSecretARN("A", value)
+		if ok != want || (ok && valueFrom != value) {
+			t.Errorf("ECSSecretARN(%q) = %q, %v", value, valueFrom, ok)
+		}
+	}
+}
+
+func TestEncodeECS(t *testing.T) {
+	out := &strings.Builder{}
+	nameVars := [][2]string{
+		{"A", "<a>"},
+		{"DB_PASSWORD", "arn:aws:secretsmanager:us-east-1:123:secret:db"},
+		{"A", "a2"},
+	}
+	if err := EncodeECS(out, nameVars, nil); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "environment": [
+    {
+      "name": "A",
+      "value": "a2"
+    }
+  ],
+  "secrets": [
+    {
+      "name": "DB_PASSWORD",
+      "valueFrom": "arn:aws:secretsmanager:us-east-1:123:secret:db"
+    }
+  ]
+}
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+
+	out.Reset()
+	options := &ECSOptions{Secret: func(name, value string) (string, bool) {
+		return strings.TrimPrefix(value, "ssm:"), strings.HasPrefix(value, "ssm:")
+	}}
+	if err := EncodeECS(out, [][2]string{{"KEY", "ssm:/app/key"}}, options); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), `"environment": [],`) || !strings.Contains(out.String(), `"valueFrom": "/app/key"`) {
+		t.Errorf("out = %v", out.String())
+	}
+}
+
+func TestDecodeECS(t *testing.T) {
+	in := `{
+  "name": "app",
+  "environment": [{"name": "A", "value": "a"}, {"name": "B", "value": ""}],
+  "secrets": [{"name": "C", "valueFrom": "arn:aws:ssm:us-east-1:123:parameter/c"}]
+}`
+	env, err := DecodeECS(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"A": "a", "B": "", "C": "arn:aws:ssm:us-east-1:123:parameter/c"}
+	if values := envValues(t, env); !reflect.DeepEqual(values, want) {
+		t.Errorf("values = %v WANT %v", values, want)
+	}
+	if !reflect.DeepEqual(env.Names(), []string{"A", "B", "C"}) {
+		t.Errorf("names = %v", env.Names())
+	}
+
+	if _, err := DecodeECS(strings.NewReader(`{"environment": {}}`)); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,404 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//ErrUnencodableValue is an error that occurs when the value of a variable cannot
+//be represented in an output format.
+type ErrUnencodableValue struct {
+	//Name is the name of the variable.
+	Name string
+
+	//Format is the name of the output format.
+	Format string
+}
+
+//Error is the error implementation for ErrUnencodableValue.
+func (e *ErrUnencodableValue) Error() string {
+	return fmt.Sprintf("dotenv: value of %q cannot be encoded as %v", e.Name, e.Format)
+}
+
+//EncodeShell writes each name, value association in nameVars to w as a POSIX
+//shell export statement, e.g. export NAME='some value'.
+//Values are quoted with ShellQuote().
+//If a name is not a valid shell variable name, then an ErrInvalidName is returned
+//and nothing more is written.
+func EncodeShell(w io.Writer, nameVars [][2]string) error {
+	for _, nameVar := range nameVars {
+		if !isShellName(nameVar[0]) {
+			return ErrInvalidName(nameVar[0])
+		}
+		if _, err := fmt.Fprintf(w, "export %v=%v\n", nameVar[0], ShellQuote(nameVar[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//isShellName determines whether or not name is a valid POSIX shell variable name.
+func isShellName(name string) bool {
+	if len(name) == 0 {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z'):
+		case '0' <= r && r <= '9' && i > 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+//EncodeJSONLines writes each of variables to w as a JSON object on its own line,
+//e.g. {"name":"A","value":"a","file":".env","line":1,"quoted":false}.
+//The file member is omitted if a Variable's File is empty.
+func EncodeJSONLines(w io.Writer, variables []*Variable) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	for _, variable := range variables {
+		if err := encoder.Encode(variable); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//EncodeMakefile writes each name, value association in nameVars to w as a
+//GNU Make exported, simply expanded variable, e.g. export NAME := value.
+//The output is suitable for use with Make's include directive.
+//Dollar signs and comment characters in values are escaped, and leading
+//whitespace and trailing backslashes are protected with an empty variable
+//reference $().
+//If a name is not a valid shell variable name, then an ErrInvalidName is returned.
+//If a value contains a newline, then an *ErrUnencodableValue is returned.
+func EncodeMakefile(w io.Writer, nameVars [][2]string) error {
+	for _, nameVar := range nameVars {
+		if !isShellName(nameVar[0]) {
+			return ErrInvalidName(nameVar[0])
+		}
+		if strings.ContainsAny(nameVar[1], "\r\n") {
+			return &ErrUnencodableValue{nameVar[0], "Makefile"}
+		}
+		if _, err := fmt.Fprintf(w, "export %v := %v\n", nameVar[0], makeEscape(nameVar[1])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//makeEscape returns value escaped for the right hand side of a Make assignment.
+func makeEscape(value string) string {
+	result := make([]byte, 0, len(value))
+	if strings.TrimLeft(value, SpaceTab) != value {
+		result = append(result, "$()"...)
+	}
+	backslashes := 0
+	for i := 0; i < len(value); i++ {
+		switch value[i] {
+		case '$':
+			result = append(result, "$$"...)
+		case '#':
+			//backslashes before a comment character must themselves be escaped.
+			result = append(result, strings.Repeat(`\`, backslashes)...)
+			result = append(result, `\#`...)
+		default:
+			result = append(result, value[i])
+		}
+		if value[i] == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
+		}
+	}
+	if backslashes > 0 {
+		result = append(result, "$()"...)
+	}
+	return string(result)
+}
+
+//EnvrcOptions are the optional directives written by EncodeEnvrc.
+type EnvrcOptions struct {
+	//Dotenv contains paths of files loaded with direnv's dotenv directive.
+	//An empty path writes the directive without an argument, which loads .env.
+	Dotenv []string
+
+	//PathAdd contains directories prepended to PATH with direnv's PATH_add
+	//directive.
+	PathAdd []string
+}
+
+//EncodeEnvrc writes a direnv compatible .envrc file to w.
+//The dotenv and PATH_add directives in options are written first, followed by
+//nameVars in the same form as EncodeShell. options may be nil.
+func EncodeEnvrc(w io.Writer, nameVars [][2]string, options *EnvrcOptions) error {
+	if options == nil {
+		options = &EnvrcOptions{}
+	}
+	for _, path := range options.Dotenv {
+		directive := "dotenv"
+		if path != "" {
+			directive += " " + ShellQuote(path)
+		}
+		if _, err := fmt.Fprintln(w, directive); err != nil {
+			return err
+		}
+	}
+	for _, path := range options.PathAdd {
+		if _, err := fmt.Fprintf(w, "PATH_add %v\n", ShellQuote(path)); err != nil {
+			return err
+		}
+	}
+	return EncodeShell(w, nameVars)
+}
+
+//EncodeDevcontainer writes nameVars to w as a devcontainer.json fragment of the
+//form {"containerEnv": {"NAME": "value"}}.
+//Names appear in the order of their first definition in nameVars with the value
+//of their last definition.
+func EncodeDevcontainer(w io.Writer, nameVars [][2]string) error {
+	nameVars = lastDefinitions(nameVars)
+	buf := &strings.Builder{}
+	buf.WriteString("{\n  \"containerEnv\": {")
+	for i, nameVar := range nameVars {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		name, err := marshalJSONString(nameVar[0])
+		if err != nil {
+			return err
+		}
+		value, err := marshalJSONString(nameVar[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "\n    %v: %v", name, value)
+	}
+	if len(nameVars) > 0 {
+		buf.WriteString("\n  ")
+	}
+	buf.WriteString("}\n}\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//EncodeNixShellHook writes nameVars to w as a nix-shell shellHook attribute,
+//i.e. an indented string containing a shell export statement for each variable
+//in the same form as EncodeShell.
+//If a name is not a valid shell variable name, then an ErrInvalidName is returned.
+func EncodeNixShellHook(w io.Writer, nameVars [][2]string) error {
+	exports := &strings.Builder{}
+	if err := EncodeShell(exports, nameVars); err != nil {
+		return err
+	}
+	buf := &strings.Builder{}
+	buf.WriteString("shellHook = ''\n")
+	for _, line := range strings.SplitAfter(exports.String(), "\n") {
+		if line != "" {
+			buf.WriteString("  " + nixIndentedEscape(line))
+		}
+	}
+	buf.WriteString("'';\n")
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//nixIndentedEscape returns s escaped for use within a Nix indented string.
+//Pairs of single quotes are escaped as ''' and interpolations as ''${. A single
+//quote immediately before an interpolation is escaped as ''\' so that it is not
+//lexed as part of the following escape.
+func nixIndentedEscape(s string) string {
+	result := make([]byte, 0, len(s))
+	rawQuote := false
+	for i := 0; i < len(s); i++ {
+		switch {
+		case strings.HasPrefix(s[i:], "''"):
+			result = append(result, "'''"...)
+			i++
+			rawQuote = false
+			continue
+		case strings.HasPrefix(s[i:], "${"):
+			if rawQuote {
+				result = append(result[:len(result)-1], `''\'`...)
+			}
+			result = append(result, "''${"...)
+			i++
+		default:
+			result = append(result, s[i])
+		}
+		rawQuote = s[i] == '\''
+	}
+	return string(result)
+}
+
+//marshalJSONString returns s as a JSON string without HTML escaping.
+func marshalJSONString(s string) (string, error) {
+	buf := &strings.Builder{}
+	encoder := json.NewEncoder(buf)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(s); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+//lastDefinitions returns nameVars with each name appearing once, in the order of
+//its first definition, with the value of its last definition.
+func lastDefinitions(nameVars [][2]string) [][2]string {
+	indexes := map[string]int{}
+	result := [][2]string{}
+	for _, nameVar := range nameVars {
+		if i, ok := indexes[nameVar[0]]; ok {
+			result[i][1] = nameVar[1]
+			continue
+		}
+		indexes[nameVar[0]] = len(result)
+		result = append(result, nameVar)
+	}
+	return result
+}
+
+//cloudRunDelimiters are the candidate delimiters for CloudRunSetEnvVars, in
+//order of preference.
+const cloudRunDelimiters = ",@|;:#~!%+"
+
+//CloudRunSetEnvVars returns nameVars as the argument of the --set-env-vars flag
+//of gcloud run deploy, e.g. "A=a,B=b".
+//Names appear in the order of their first definition in nameVars with the value
+//of their last definition.
+//If any name or value contains a comma, then gcloud's alternate delimiter syntax
+//is used with the first of "@|;:#~!%+" that does not appear in nameVars, e.g.
+//"^@^A=a,b@B=b". Newlines in values are kept as they are, so the result must be
+//passed as a single argument.
+//If no delimiter can be used, then an *ErrUnencodableValue is returned for the
+//first variable.
+func CloudRunSetEnvVars(nameVars [][2]string) (string, error) {
+	nameVars = lastDefinitions(nameVars)
+	pairs := make([]string, 0, len(nameVars))
+	for _, nameVar := range nameVars {
+		if nameVar[0] == "" || strings.Contains(nameVar[0], "=") {
+			return "", ErrInvalidName(nameVar[0])
+		}
+		pairs = append(pairs, nameVar[0]+"="+nameVar[1])
+	}
+	all := strings.Join(pairs, "")
+	for i, delimiter := range cloudRunDelimiters {
+		if strings.ContainsRune(all, delimiter) {
+			continue
+		}
+		if i == 0 {
+			return strings.Join(pairs, ","), nil
+		}
+		return "^" + string(delimiter) + "^" + strings.Join(pairs, string(delimiter)), nil
+	}
+	return "", &ErrUnencodableValue{nameVars[0][0], "--set-env-vars"}
+}
+
+//EncodeCloudRunYAML writes nameVars to w as the env block of a container in a
+//Cloud Run service.yaml, i.e. a YAML sequence of name and value mappings under
+//an env key. Values are always written as double quoted strings, so that commas,
+//newlines, and other special characters are escaped.
+//Names appear in the order of their first definition in nameVars with the value
+//of their last definition.
+//If a name is not a valid shell variable name, then an ErrInvalidName is returned
+//and nothing is written.
+func EncodeCloudRunYAML(w io.Writer, nameVars [][2]string) error {
+	buf := &strings.Builder{}
+	buf.WriteString("env:\n")
+	for _, nameVar := range lastDefinitions(nameVars) {
+		if !isShellName(nameVar[0]) {
+			return ErrInvalidName(nameVar[0])
+		}
+		value, err := marshalJSONString(nameVar[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "- name: %v\n  value: %v\n", nameVar[0], value)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//EncodeAnsibleVars writes nameVars to w as an Ansible vars file, i.e. a YAML
+//document mapping each name to its value, e.g. "---\nA: \"a\"\n".
+//Values are always written as double quoted strings, so that Ansible does not
+//interpret them as numbers, booleans, or other types.
+//Names appear in the order of their first definition in nameVars with the value
+//of their last definition.
+//If a name is not a valid shell variable name, then an ErrInvalidName is returned
+//and nothing is written.
+func EncodeAnsibleVars(w io.Writer, nameVars [][2]string) error {
+	buf := &strings.Builder{}
+	buf.WriteString("---\n")
+	if err := writeYAMLMapping(buf, nameVars, ""); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//EncodeHelmValues writes nameVars to w as a Helm values fragment that nests the
+//variables under keyPath, a dot separated path of keys, e.g. keyPath
+//"app.env" writes "app:\n  env:\n    A: \"a\"\n".
+//An empty keyPath writes the variables at the top level.
+//Values are written as in EncodeAnsibleVars.
+//If a name is not a valid shell variable name, or keyPath contains an empty key,
+//then an ErrInvalidName is returned and nothing is written.
+func EncodeHelmValues(w io.Writer, nameVars [][2]string, keyPath string) error {
+	buf := &strings.Builder{}
+	indent := ""
+	if keyPath != "" {
+		for _, key := range strings.Split(keyPath, ".") {
+			if key == "" {
+				return ErrInvalidName(keyPath)
+			}
+			quoted, err := yamlKey(key)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(buf, "%v%v:\n", indent, quoted)
+			indent += "  "
+		}
+	}
+	if len(nameVars) == 0 && keyPath != "" {
+		//an empty mapping instead of a null value.
+		text := strings.TrimSuffix(buf.String(), "\n") + " {}\n"
+		buf.Reset()
+		buf.WriteString(text)
+	}
+	if err := writeYAMLMapping(buf, nameVars, indent); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//writeYAMLMapping writes nameVars to buf as a YAML block mapping with each line
+//prefixed by indent.
+func writeYAMLMapping(buf *strings.Builder, nameVars [][2]string, indent string) error {
+	for _, nameVar := range lastDefinitions(nameVars) {
+		if !isShellName(nameVar[0]) {
+			return ErrInvalidName(nameVar[0])
+		}
+		value, err := marshalJSONString(nameVar[1])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(buf, "%v%v: %v\n", indent, nameVar[0], value)
+	}
+	return nil
+}
+
+//yamlKey returns key as a plain YAML scalar if it is a shell name, or as a double
+//quoted string otherwise.
+func yamlKey(key string) (string, error) {
+	if isShellName(key) {
+		return key, nil
+	}
+	return marshalJSONString(key)
+}
@@ -0,0 +1,280 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEncodeShell_success(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeShell(out, [][2]string{{"A", "a"}, {"B_2", "it's"}})
+	if err != nil {
+		t.Error(err)
+	}
+	if out.String() != "export A=a\nexport B_2='it'\\''s'\n" {
+		t.Errorf("out = %q", out.String())
+	}
+}
+
+func TestEncodeShell_invalidName(t *testing.T) {
+	out := &strings.Builder{}
+	for _, name := range []string{"", "2A", "A.B", "A-B"} {
+		err := EncodeShell(out, [][2]string{{name, "a"}})
+		if err != ErrInvalidName(name) {
+			t.Errorf("EncodeShell(%q) = %v", name, err)
+		}
+	}
+	if out.Len() != 0 {
+		t.Fail()
+	}
+}
+
+func TestEncodeJSONLines(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeJSONLines(out, []*Variable{
+		{Name: "A", Value: "<a>", File: ".env", Line: 1},
+		{Name: "B", Value: "b c", Line: 3, Quoted: true},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `{"name":"A","value":"<a>","file":".env","line":1,"quoted":false}
+{"name":"B","value":"b c","line":3,"quoted":true}
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+}
+
+func TestErrUnencodableValue_Error(t *testing.T) {
+	err := &ErrUnencodableValue{"A", "Makefile"}
+	if err.Error() != `dotenv: value of "A" cannot be encoded as Makefile` {
+		t.Fail()
+	}
+}
+
+func TestEncodeMakefile_success(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeMakefile(out, [][2]string{
+		{"A", "a $b #c"},
+		{"B", "  lead"},
+		{"C", `back\`},
+		{"D", `x\#y`},
+		{"E", "trail  "},
+		{"F", ""},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `export A := a $$b \#c
+export B := $()  lead
+export C := back\$()
+export D := x\\\#y
+export E := trail  
+export F := 
+`
+	if out.String() != want {
+		t.Errorf("out = %q WANT %q", out.String(), want)
+	}
+}
+
+func TestEncodeMakefile_errors(t *testing.T) {
+	out := &strings.Builder{}
+	if err := EncodeMakefile(out, [][2]string{{"A:B", "a"}}); err != ErrInvalidName("A:B") {
+		t.Errorf("err = %v", err)
+	}
+	err := EncodeMakefile(out, [][2]string{{"A", "a\nb"}})
+	if !reflect.DeepEqual(err, &ErrUnencodableValue{"A", "Makefile"}) {
+		t.Errorf("err = %v", err)
+	}
+	if out.Len() != 0 {
+		t.Fail()
+	}
+}
+
+func TestEncodeEnvrc(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeEnvrc(out, [][2]string{{"A", "a b"}}, &EnvrcOptions{
+		Dotenv:  []string{"", ".env.local"},
+		PathAdd: []string{"bin", "my tools"},
+	})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `dotenv
+dotenv .env.local
+PATH_add bin
+PATH_add 'my tools'
+export A='a b'
+`
+	if out.String() != want {
+		t.Errorf("out = %q WANT %q", out.String(), want)
+	}
+}
+
+func TestEncodeEnvrc_nilOptions(t *testing.T) {
+	out := &strings.Builder{}
+	if err := EncodeEnvrc(out, [][2]string{{"A", "a"}}, nil); err != nil {
+		t.Error(err)
+	}
+	if out.String() != "export A=a\n" {
+		t.Errorf("out = %q", out.String())
+	}
+	if err := EncodeEnvrc(out, [][2]string{{"A B", "a"}}, nil); err != ErrInvalidName("A B") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestEncodeDevcontainer(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeDevcontainer(out, [][2]string{{"A", "a"}, {"B", "<b>\n"}, {"A", "a2"}})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `{
+  "containerEnv": {
+    "A": "a2",
+    "B": "<b>\n"
+  }
+}
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+
+	out.Reset()
+	if err := EncodeDevcontainer(out, nil); err != nil {
+		t.Error(err)
+	}
+	if out.String() != "{\n  \"containerEnv\": {}\n}\n" {
+		t.Errorf("out = %v", out.String())
+	}
+}
+
+func TestEncodeNixShellHook(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeNixShellHook(out, [][2]string{{"A", "a"}, {"B", "${HOME}'s"}, {"C", ""}})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `shellHook = ''
+  export A=a
+  export B=''\'''${HOME}'\'''s'
+  export C='''
+'';
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+
+	if err := EncodeNixShellHook(out, [][2]string{{"A-B", "a"}}); err != ErrInvalidName("A-B") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestLastDefinitions(t *testing.T) {
+	nameVars := [][2]string{{"a", "1"}, {"b", "2"}, {"a", "3"}}
+	result := lastDefinitions(nameVars)
+	if !reflect.DeepEqual(result, [][2]string{{"a", "3"}, {"b", "2"}}) {
+		t.Errorf("result = %v", result)
+	}
+	if nameVars[0][1] != "1" {
+		t.Error("lastDefinitions() must not modify its argument")
+	}
+}
+
+func TestCloudRunSetEnvVars(t *testing.T) {
+	cases := []struct {
+		nameVars [][2]string
+		want     string
+	}{
+		{nil, ""},
+		{[][2]string{{"A", "a"}, {"B", "b c"}, {"A", "a2"}}, "A=a2,B=b c"},
+		{[][2]string{{"A", "a,b"}, {"B", "x\ny"}}, "^@^A=a,b@B=x\ny"},
+		{[][2]string{{"A", "a,b@c|d"}}, "^;^A=a,b@c|d"},
+	}
+	for _, c := range cases {
+		result, err := CloudRunSetEnvVars(c.nameVars)
+		if result != c.want || err != nil {
+			t.Errorf("CloudRunSetEnvVars(%v) = %q, %v WANT %q", c.nameVars, result, err, c.want)
+		}
+	}
+
+	if _, err := CloudRunSetEnvVars([][2]string{{"A=B", "a"}}); err != ErrInvalidName("A=B") {
+		t.Errorf("err = %v", err)
+	}
+	_, err := CloudRunSetEnvVars([][2]string{{"A", cloudRunDelimiters}})
+	if !reflect.DeepEqual(err, &ErrUnencodableValue{"A", "--set-env-vars"}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestEncodeCloudRunYAML(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeCloudRunYAML(out, [][2]string{{"A", "a,b"}, {"B", "line1\nline2 \"q\""}, {"C", ""}})
+	if err != nil {
+		t.Error(err)
+	}
+	want := `env:
+- name: A
+  value: "a,b"
+- name: B
+  value: "line1\nline2 \"q\""
+- name: C
+  value: ""
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+
+	out.Reset()
+	if err := EncodeCloudRunYAML(out, [][2]string{{"A-B", "a"}}); err != ErrInvalidName("A-B") || out.Len() != 0 {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestEncodeAnsibleVars(t *testing.T) {
+	out := &strings.Builder{}
+	err := EncodeAnsibleVars(out, [][2]string{{"A", "yes"}, {"B", "a: b\n"}, {"A", "8080"}})
+	if err != nil {
+		t.Error(err)
+	}
+	if out.String() != "---\nA: \"8080\"\nB: \"a: b\\n\"\n" {
+		t.Errorf("out = %q", out.String())
+	}
+
+	out.Reset()
+	if err := EncodeAnsibleVars(out, [][2]string{{"A B", "a"}}); err != ErrInvalidName("A B") || out.Len() != 0 {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestEncodeHelmValues(t *testing.T) {
+	cases := []struct {
+		keyPath  string
+		nameVars [][2]string
+		want     string
+	}{
+		{"", [][2]string{{"A", "a"}}, "A: \"a\"\n"},
+		{"app.env", [][2]string{{"A", "a"}, {"B", "true"}}, "app:\n  env:\n    A: \"a\"\n    B: \"true\"\n"},
+		{"my-chart.env", nil, "\"my-chart\":\n  env: {}\n"},
+	}
+	for _, c := range cases {
+		out := &strings.Builder{}
+		if err := EncodeHelmValues(out, c.nameVars, c.keyPath); err != nil {
+			t.Error(err)
+		}
+		if out.String() != c.want {
+			t.Errorf("EncodeHelmValues(%q) = %q WANT %q", c.keyPath, out.String(), c.want)
+		}
+	}
+
+	out := &strings.Builder{}
+	if err := EncodeHelmValues(out, nil, "app..env"); err != ErrInvalidName("app..env") {
+		t.Errorf("err = %v", err)
+	}
+	if err := EncodeHelmValues(out, [][2]string{{"A.B", "a"}}, "env"); err != ErrInvalidName("A.B") || out.Len() != 0 {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package dotenv
+
+import (
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//DefaultGroupSeparator is the GroupSeparator set to Encoder.GroupSeparator in
+//NewEncoder().
+const DefaultGroupSeparator = "_"
+
+//Encoder writes variable definitions in the format parsed by a Sourcer returned
+//from NewDefault().
+//By default, values are only quoted when required, as determined by MaybeQuote().
+type Encoder struct {
+	//Sort causes variables to be written in ascending order of their names.
+	//Variables with the same name keep their relative order.
+	//If Sort is false, then variables are written in the order given.
+	Sort bool
+
+	//Group causes variables to be grouped by the prefix of their names before
+	//the first GroupSeparator, e.g. DATABASE_URL and DATABASE_USER are grouped by
+	//DATABASE. Groups are separated by an empty line and each is preceded by a
+	//comment containing its prefix. Groups appear in the order of their first
+	//variable. Variables whose names do not contain GroupSeparator are written
+	//first without a comment.
+	Group bool
+
+	//GroupSeparator separates a name's group prefix from the rest of the name.
+	//An empty GroupSeparator value means that Group has no effect.
+	GroupSeparator string
+
+	//Align causes the equal signs of consecutive variables within a group to be
+	//aligned by padding names with spaces.
+	//The output must be parsed by a Sourcer with SpaceAroundEqual set to true.
+	Align bool
+
+	//Deterministic guarantees that identical sets of variables are always written
+	//as identical bytes, regardless of the order or repetition of nameVars.
+	//Each name is written once with the value of its last definition, which is
+	//the value it would have after sourcing, and variables are written as if
+	//Sort were true.
+	//Deterministic output always uses "\n" line endings.
+	Deterministic bool
+
+	//ForceQuote causes every value, including empty values, to be quoted with
+	//strconv.Quote().
+	ForceQuote bool
+
+	//ForceExport causes every definition to be preceded by DefaultExport and a
+	//space, e.g. export NAME=value, as in files that are also sourced by POSIX
+	//shells.
+	//If ForceExport is false, then Encode writes no definition with the export
+	//keyword, and EncodeVariables writes it for the variables whose Exported is
+	//true.
+	ForceExport bool
+
+	//LineEnding ends each line that is written, and is either LF or CRLF.
+	//An empty LineEnding means LF. LineEnding has no effect if Deterministic is
+	//true.
+	LineEnding string
+}
+
+//encoderVar is a variable written by an Encoder.
+type encoderVar struct {
+	name, value string
+	exported    bool
+}
+
+//NewEncoder returns an Encoder with GroupSeparator set to DefaultGroupSeparator
+//and all other options disabled.
+func NewEncoder() *Encoder {
+	return &Encoder{GroupSeparator: DefaultGroupSeparator}
+}
+
+//Encode writes each name, value association in nameVars to w as a variable
+//definition line, e.g. NAME=value.
+func (e *Encoder) Encode(w io.Writer, nameVars [][2]string) error {
+	vars := make([]encoderVar, len(nameVars))
+	for i, nameVar := range nameVars {
+		vars[i] = encoderVar{name: nameVar[0], value: nameVar[1]}
+	}
+	_, err := io.WriteString(w, e.format(vars))
+	return err
+}
+
+//EncodeVariables is the same as Encode for the names and values of variables,
+//except that the definitions of variables whose Exported is true are preceded by
+//DefaultExport, so that files parsed into Variables keep their export keywords
+//when written back. All other metadata is ignored.
+func (e *Encoder) EncodeVariables(w io.Writer, variables []*Variable) error {
+	vars := make([]encoderVar, len(variables))
+	for i, variable := range variables {
+		vars[i] = encoderVar{variable.Name, variable.Value, variable.Exported}
+	}
+	_, err := io.WriteString(w, e.format(vars))
+	return err
+}
+
+//format returns the encoded form of vars.
+func (e *Encoder) format(vars []encoderVar) string {
+	vars = append([]encoderVar{}, vars...)
+	if e.Deterministic {
+		vars = lastEncoderVars(vars)
+	}
+	if e.Sort || e.Deterministic {
+		sort.SliceStable(vars, func(i, j int) bool {
+			return vars[i].name < vars[j].name
+		})
+	}
+
+	buf := &strings.Builder{}
+	for i, group := range e.groups(vars) {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		if group.prefix != "" {
+			buf.WriteString(DefaultComment + " " + group.prefix + "\n")
+		}
+		e.formatGroup(buf, group.vars)
+	}
+	if e.LineEnding != "" && e.LineEnding != LF && !e.Deterministic {
+		//values containing newlines are always quoted, so every "\n" ends a line.
+		return strings.Replace(buf.String(), LF, e.LineEnding, -1)
+	}
+	return buf.String()
+}
+
+//lastEncoderVars returns vars with each name appearing once, as in
+//lastDefinitions. A name is exported if its last definition is.
+func lastEncoderVars(vars []encoderVar) []encoderVar {
+	indexes := map[string]int{}
+	result := []encoderVar{}
+	for _, v := range vars {
+		if i, ok := indexes[v.name]; ok {
+			result[i].value, result[i].exported = v.value, v.exported
+			continue
+		}
+		indexes[v.name] = len(result)
+		result = append(result, v)
+	}
+	return result
+}
+
+//formatGroup writes the variable definitions in vars to buf.
+func (e *Encoder) formatGroup(buf *strings.Builder, vars []encoderVar) {
+	names := make([]string, len(vars))
+	width := 0
+	for i, v := range vars {
+		names[i] = v.name
+		if v.exported || e.ForceExport {
+			names[i] = DefaultExport + " " + v.name
+		}
+		if e.Align && len(names[i]) > width {
+			width = len(names[i])
+		}
+	}
+	for i, v := range vars {
+		buf.WriteString(names[i])
+		if e.Align {
+			buf.WriteString(strings.Repeat(" ", width-len(names[i])) + " = ")
+		} else {
+			buf.WriteString("=")
+		}
+		buf.WriteString(e.quote(v.value) + "\n")
+	}
+}
+
+//quote returns value as it should appear after the equal sign of a definition.
+func (e *Encoder) quote(value string) string {
+	if e.ForceQuote {
+		return strconv.Quote(value)
+	}
+	return MaybeQuote(value)
+}
+
+//encoderGroup is a group of variables that share a name prefix.
+type encoderGroup struct {
+	prefix string
+	vars   []encoderVar
+}
+
+//groups returns vars grouped by prefix if e.Group is true, and a single group
+//containing all of vars otherwise.
+func (e *Encoder) groups(vars []encoderVar) []*encoderGroup {
+	if !e.Group || e.GroupSeparator == "" {
+		return []*encoderGroup{{vars: vars}}
+	}
+
+	ungrouped := &encoderGroup{}
+	result := []*encoderGroup{ungrouped}
+	byPrefix := map[string]*encoderGroup{}
+	for _, v := range vars {
+		separator := strings.Index(v.name, e.GroupSeparator)
+		if separator <= 0 {
+			ungrouped.vars = append(ungrouped.vars, v)
+			continue
+		}
+		prefix := v.name[:separator]
+		group, ok := byPrefix[prefix]
+		if !ok {
+			group = &encoderGroup{prefix: prefix}
+			byPrefix[prefix] = group
+			result = append(result, group)
+		}
+		group.vars = append(group.vars, v)
+	}
+
+	if len(ungrouped.vars) == 0 {
+		return result[1:]
+	}
+	return result
+}
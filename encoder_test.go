@@ -0,0 +1,254 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var encoderNameVars = [][2]string{
+	{"PORT", "8080"},
+	{"DATABASE_URL", "postgres://db"},
+	{"APP_NAME", "my app"},
+	{"DATABASE_USER", "admin #1"},
+	{"HOST", "localhost"},
+	{"APP_ENV", ""},
+}
+
+func TestNewEncoder(t *testing.T) {
+	e := NewEncoder()
+	if e.GroupSeparator != DefaultGroupSeparator || e.Sort || e.Group || e.Align {
+		t.Fail()
+	}
+}
+
+func TestEncoder_Encode(t *testing.T) {
+	cases := []struct {
+		sort, group, align bool
+		want               string
+	}{
+		{false, false, false, `PORT=8080
+DATABASE_URL=postgres://db
+APP_NAME=my app
+DATABASE_USER="admin #1"
+HOST=localhost
+APP_ENV=
+`},
+		{true, false, false, `APP_ENV=
+APP_NAME=my app
+DATABASE_URL=postgres://db
+DATABASE_USER="admin #1"
+HOST=localhost
+PORT=8080
+`},
+		{false, true, false, `PORT=8080
+HOST=localhost
+
+# DATABASE
+DATABASE_URL=postgres://db
+DATABASE_USER="admin #1"
+
+# APP
+APP_NAME=my app
+APP_ENV=
+`},
+		{true, true, true, `HOST = localhost
+PORT = 8080
+
+# APP
+APP_ENV  = 
+APP_NAME = my app
+
+# DATABASE
+DATABASE_URL  = postgres://db
+DATABASE_USER = "admin #1"
+`},
+	}
+	for i, c := range cases {
+		e := NewEncoder()
+		e.Sort, e.Group, e.Align = c.sort, c.group, c.align
+		out := &strings.Builder{}
+		if err := e.Encode(out, encoderNameVars); err != nil {
+			t.Error(err)
+		}
+		if out.String() != c.want {
+			t.Errorf("%v out = %v WANT %v", i, out.String(), c.want)
+		}
+	}
+}
+
+func TestEncoder_Encode_roundTrip(t *testing.T) {
+	e := NewEncoder()
+	e.Sort, e.Group, e.Align = true, true, true
+	out := &strings.Builder{}
+	if err := e.Encode(out, encoderNameVars); err != nil {
+		t.Error(err)
+	}
+
+	s := NewDefault()
+	s.SpaceAroundEqual = true
+	stats, err := s.Stats(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats.Definitions != len(encoderNameVars) {
+		t.Fail()
+	}
+	nameVars, err := s.NameVars(strings.NewReader(out.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, nameVar := range encoderNameVars {
+		found := false
+		for _, result := range nameVars {
+			found = found || reflect.DeepEqual(nameVar, result)
+		}
+		if !found {
+			t.Errorf("%v not found in %v", nameVar, nameVars)
+		}
+	}
+}
+
+func TestEncoder_Encode_groupNoSeparator(t *testing.T) {
+	e := NewEncoder()
+	e.Group = true
+	e.GroupSeparator = ""
+	out := &strings.Builder{}
+	if err := e.Encode(out, [][2]string{{"A_B", "1"}, {"C", "2"}}); err != nil {
+		t.Error(err)
+	}
+	if out.String() != "A_B=1\nC=2\n" {
+		t.Errorf("out = %v", out.String())
+	}
+}
+
+func TestEncoder_Encode_deterministic(t *testing.T) {
+	e := NewEncoder()
+	e.Deterministic = true
+	e.Group = true
+
+	outputs := []string{}
+	for _, nameVars := range [][][2]string{
+		{{"B_1", "b"}, {"A", "a"}, {"B_2", "c"}},
+		{{"B_2", "c"}, {"A", "x"}, {"B_1", "b"}, {"A", "a"}},
+		{{"A", "a"}, {"B_1", "b"}, {"B_2", "c"}, {"B_2", "c"}},
+	} {
+		out := &strings.Builder{}
+		if err := e.Encode(out, nameVars); err != nil {
+			t.Error(err)
+		}
+		outputs = append(outputs, out.String())
+	}
+
+	want := "A=a\n\n# B\nB_1=b\nB_2=c\n"
+	for i, out := range outputs {
+		if out != want {
+			t.Errorf("%v out = %q WANT %q", i, out, want)
+		}
+	}
+}
+
+func TestEncoder_Encode_quoting(t *testing.T) {
+	nameVars := [][2]string{
+		{"A", "plain value"},
+		{"B", " padded "},
+		{"C", "a#b"},
+		{"D", `"quoted"`},
+		{"E", "bell\a"},
+		{"F", ""},
+		{"G", "héllo"},
+	}
+	cases := map[bool]string{
+		false: `A=plain value
+B=" padded "
+C="a#b"
+D="\"quoted\""
+E="bell\a"
+F=
+G=héllo
+`,
+		true: `A="plain value"
+B=" padded "
+C="a#b"
+D="\"quoted\""
+E="bell\a"
+F=""
+G="héllo"
+`,
+	}
+	for forceQuote, want := range cases {
+		e := NewEncoder()
+		e.ForceQuote = forceQuote
+		out := &strings.Builder{}
+		if err := e.Encode(out, nameVars); err != nil {
+			t.Error(err)
+		}
+		if out.String() != want {
+			t.Errorf("ForceQuote %v out = %v WANT %v", forceQuote, out.String(), want)
+		}
+
+		result, err := NewDefault().NameVars(strings.NewReader(out.String()))
+		if err != nil || !reflect.DeepEqual(result, nameVars) {
+			t.Errorf("ForceQuote %v round trip = %q, %v", forceQuote, result, err)
+		}
+	}
+}
+
+func TestEncoder_EncodeVariables_export(t *testing.T) {
+	source := "export A=1\nBB=2\nexport A=3\n"
+	variables, err := NewDefault().Variables(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		forceExport, deterministic, align bool
+		want                              string
+	}{
+		{false, false, false, source},
+		{true, false, false, "export A=1\nexport BB=2\nexport A=3\n"},
+		{false, true, false, "export A=3\nBB=2\n"},
+		{false, false, true, "export A = 1\nBB       = 2\nexport A = 3\n"},
+	}
+	for _, c := range cases {
+		e := NewEncoder()
+		e.ForceExport, e.Deterministic, e.Align = c.forceExport, c.deterministic, c.align
+		out := &strings.Builder{}
+		if err := e.EncodeVariables(out, variables); err != nil {
+			t.Error(err)
+		}
+		if out.String() != c.want {
+			t.Errorf("%+v out = %q", c, out.String())
+		}
+	}
+
+	e := NewEncoder()
+	e.ForceExport = true
+	out := &strings.Builder{}
+	if err := e.Encode(out, [][2]string{{"A", "a b"}}); err != nil || out.String() != "export A=a b\n" {
+		t.Errorf("out, err = %q, %v", out.String(), err)
+	}
+}
+
+func TestEncoder_Encode_lineEnding(t *testing.T) {
+	nameVars := [][2]string{{"A", "a\nb"}, {"B_1", "b"}}
+	e := NewEncoder()
+	e.Group = true
+	e.LineEnding = CRLF
+	out := &strings.Builder{}
+	if err := e.Encode(out, nameVars); err != nil {
+		t.Error(err)
+	}
+	if want := "A=\"a\\nb\"\r\n\r\n# B\r\nB_1=b\r\n"; out.String() != want {
+		t.Errorf("out = %q WANT %q", out.String(), want)
+	}
+	result, err := NewDefault().NameVars(strings.NewReader(out.String()))
+	if err != nil || !reflect.DeepEqual(result, nameVars) {
+		t.Errorf("round trip = %q, %v", result, err)
+	}
+
+	e.Deterministic = true
+	out.Reset()
+	if err := e.Encode(out, nameVars); err != nil || strings.Contains(out.String(), "\r") {
+		t.Errorf("out, err = %q, %v", out.String(), err)
+	}
+}
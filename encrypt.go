@@ -0,0 +1,198 @@
+package dotenv
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+//EncryptScheme is the scheme of encrypted values, e.g.
+//"KEY=enc:AES256:base64...". Register a ValueCipher for it in a Sourcer's
+//Resolvers to decrypt them while sourcing.
+const EncryptScheme = "enc"
+
+//AES256 is the algorithm of values encrypted by a ValueCipher.
+const AES256 = "AES256"
+
+//ErrKeySize is an error that occurs when a key for a ValueCipher is not 32
+//bytes long. Its value is the length of the key.
+type ErrKeySize int
+
+//Error is the error implementation for ErrKeySize.
+func (e ErrKeySize) Error() string {
+	return fmt.Sprintf("dotenv: %v keys must be 32 bytes, not %d", AES256, int(e))
+}
+
+//ErrUnknownAlgorithm is an error that occurs when an encrypted value names an
+//algorithm other than AES256. Its value is the algorithm.
+type ErrUnknownAlgorithm string
+
+//Error is the error implementation for ErrUnknownAlgorithm.
+func (e ErrUnknownAlgorithm) Error() string {
+	return fmt.Sprintf("unknown encryption algorithm %q", string(e))
+}
+
+//ValueCipher encrypts and decrypts single values with AES-256-GCM, so that a file
+//can contain encrypted secrets next to plaintext values and remain diffable.
+//Encrypted values have the form "enc:AES256:BASE64", where BASE64 is the standard
+//base64 encoding of a random nonce followed by the sealed value.
+//The value is sealed with the name of its variable as additional authenticated
+//data, so that an encrypted value copied to another variable does not decrypt.
+//The name is the one the variable is sourced as, i.e. after StripPrefix,
+//OSSuffix, and Aliases of the Sourcer are applied.
+//ValueCipher is a NameResolver for EncryptScheme and is safe for concurrent use.
+type ValueCipher struct {
+	aead cipher.AEAD
+}
+
+//NewValueCipher returns a ValueCipher for the 32 byte key, or an ErrKeySize if
+//key has another length.
+func NewValueCipher(key []byte) (*ValueCipher, error) {
+	if len(key) != 32 {
+		return nil, ErrKeySize(len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &ValueCipher{aead}, nil
+}
+
+//Encrypt returns value of the variable name encrypted with a random nonce,
+//prefixed with "enc:AES256:".
+func (c *ValueCipher) Encrypt(name, value string) (string, error) {
+	nonce := make([]byte, c.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := c.aead.Seal(nonce, nonce, []byte(value), []byte(name))
+	return EncryptScheme + ":" + AES256 + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+//Decrypt returns the plaintext of value of the variable name, which must have
+//been returned from Encrypt with the same key and name.
+func (c *ValueCipher) Decrypt(name, value string) (string, error) {
+	if !IsEncrypted(value) {
+		return "", fmt.Errorf("dotenv: value is not prefixed with %v:", EncryptScheme)
+	}
+	return c.ResolveName(context.Background(), name, value[len(EncryptScheme)+1:])
+}
+
+//Resolve implements Resolver by always returning an error, since values cannot
+//be decrypted without the name of their variable. Sourcers call ResolveName
+//instead.
+func (c *ValueCipher) Resolve(ctx context.Context, ref string) (string, error) {
+	return "", fmt.Errorf("dotenv: %v values can only be decrypted with the name of their variable", EncryptScheme)
+}
+
+//ResolveName decrypts ref, which is a value of the variable name returned from
+//Encrypt without its "enc:" prefix. It implements NameResolver.
+func (c *ValueCipher) ResolveName(ctx context.Context, name, ref string) (string, error) {
+	algorithm, encoded := ref, ""
+	if i := strings.IndexByte(ref, ':'); i >= 0 {
+		algorithm, encoded = ref[:i], ref[i+1:]
+	}
+	if algorithm != AES256 {
+		return "", ErrUnknownAlgorithm(algorithm)
+	}
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < c.aead.NonceSize() {
+		return "", fmt.Errorf("encrypted value is too short")
+	}
+	nonce, sealed := sealed[:c.aead.NonceSize()], sealed[c.aead.NonceSize():]
+	plain, err := c.aead.Open(nil, nonce, sealed, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+//EncryptDocument encrypts the values of names in d in place, leaving all other
+//lines unchanged. Values that are already encrypted are not encrypted again.
+//If a name is not defined in d, then an ErrMissingVariables with all such names
+//is returned and d is not changed.
+func (c *ValueCipher) EncryptDocument(d *Document, names ...string) error {
+	missing := ErrMissingVariables{}
+	for _, name := range names {
+		if _, ok := d.Get(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return missing
+	}
+	for _, name := range names {
+		value, _ := d.Get(name)
+		if IsEncrypted(value) {
+			continue
+		}
+		encrypted, err := c.Encrypt(d.boundName(name), value)
+		if err != nil {
+			return err
+		}
+		d.Set(name, encrypted)
+	}
+	return nil
+}
+
+//Rotate re-encrypts every encrypted value in d, which must have been encrypted
+//with oldKey, with newKey in place, keeping the formatting of d. Every
+//definition is rotated, including those that are redefined later in d.
+//If a key is invalid or a value cannot be decrypted, then an error is returned
+//and d is not changed.
+func Rotate(d *Document, oldKey, newKey []byte) error {
+	oldCipher, err := NewValueCipher(oldKey)
+	if err != nil {
+		return err
+	}
+	newCipher, err := NewValueCipher(newKey)
+	if err != nil {
+		return err
+	}
+	rotated := map[*documentLine]string{}
+	for i, line := range d.lines {
+		if !line.isVariable || !IsEncrypted(line.value) {
+			continue
+		}
+		name := d.boundName(line.name)
+		plain, err := oldCipher.Decrypt(name, line.value)
+		if err != nil {
+			return fmt.Errorf("dotenv: line %d decrypting %q: %w", i+1, line.name, err)
+		}
+		if rotated[line], err = newCipher.Encrypt(name, plain); err != nil {
+			return err
+		}
+	}
+	for line, value := range rotated {
+		line.value = value
+		d.format(line)
+	}
+	return nil
+}
+
+//boundName returns the name that the value of the variable defined as name in d
+//is encrypted with, which is the name it is sourced as, or name itself if the
+//Sourcer of d ignores it.
+func (d *Document) boundName(name string) string {
+	if sourced, ok := d.sourcer.sourcedName(name); ok {
+		return sourced
+	}
+	return name
+}
+
+//IsEncrypted returns whether or not value is marked as encrypted, i.e. starts with
+//"enc:".
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, EncryptScheme+":")
+}
@@ -0,0 +1,129 @@
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewValueCipher_keySize(t *testing.T) {
+	if _, err := NewValueCipher(make([]byte, 16)); err != ErrKeySize(16) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestValueCipher(t *testing.T) {
+	c, err := NewValueCipher(bytes.Repeat([]byte{1}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := c.Encrypt("B", "s3cret")
+	if err != nil || !strings.HasPrefix(encrypted, "enc:AES256:") || !IsEncrypted(encrypted) {
+		t.Fatalf("encrypted, err = %v, %v", encrypted, err)
+	}
+	if again, _ := c.Encrypt("B", "s3cret"); again == encrypted {
+		t.Error("nonces must be random")
+	}
+	if v, err := c.Decrypt("B", encrypted); v != "s3cret" || err != nil {
+		t.Errorf("Decrypt() = %v, %v", v, err)
+	}
+
+	other, _ := NewValueCipher(bytes.Repeat([]byte{2}, 32))
+	if _, err := other.Decrypt("B", encrypted); err == nil {
+		t.Error("decrypting with another key must fail")
+	}
+	if _, err := c.Decrypt("A", encrypted); err == nil {
+		t.Error("decrypting with another name must fail")
+	}
+	if _, err := c.Decrypt("B", "enc:DES:abc"); err != ErrUnknownAlgorithm("DES") {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := c.Decrypt("B", "plain"); err == nil {
+		t.Error("decrypting an unmarked value must fail")
+	}
+
+	s := NewSourcerWith(WithResolver(EncryptScheme, c))
+	nameVars, err := s.NameVars(strings.NewReader("A=a\nB=" + encrypted + "\n"))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}, {"B", "s3cret"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+	if _, err := s.NameVars(strings.NewReader("B=" + encrypted + "\nA=" + encrypted + "\n")); err == nil {
+		t.Error("a value copied to another variable must not decrypt")
+	}
+	//the value is bound to the name it is sourced as.
+	s.StripPrefix = "APP_"
+	if nameVars, err := s.NameVars(strings.NewReader("APP_B=" + encrypted + "\n")); err != nil || nameVars[0][1] != "s3cret" {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+	if _, err := c.Resolve(context.Background(), encrypted[len("enc:"):]); err == nil {
+		t.Error("Resolve() must fail without a name")
+	}
+}
+
+func TestValueCipher_EncryptDocument(t *testing.T) {
+	c, _ := NewValueCipher(bytes.Repeat([]byte{1}, 32))
+	d, err := NewDefault().ParseDocument(strings.NewReader("#comment\nA=a\nB=b #inline\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.EncryptDocument(d, "B", "C", "D"); !reflect.DeepEqual(err, ErrMissingVariables{"C", "D"}) {
+		t.Errorf("err = %v", err)
+	}
+	if err := c.EncryptDocument(d, "B"); err != nil {
+		t.Fatal(err)
+	}
+	encrypted, _ := d.Get("B")
+	if v, err := c.Decrypt("B", encrypted); v != "b" || err != nil {
+		t.Errorf("Decrypt() = %v, %v", v, err)
+	}
+	if want := "#comment\nA=a\nB=" + encrypted + " #inline\n"; d.String() != want {
+		t.Errorf("d = %q", d.String())
+	}
+	if err := c.EncryptDocument(d, "B"); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := d.Get("B"); again != encrypted {
+		t.Error("encrypted values must not be encrypted again")
+	}
+
+	other, _ := NewValueCipher(bytes.Repeat([]byte{2}, 32))
+	s := NewSourcerWith(WithResolver(EncryptScheme, other))
+	if _, err := s.NameVars(strings.NewReader(d.String())); err == nil || !strings.Contains(err.Error(), `line 3 resolving "B"`) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestRotate(t *testing.T) {
+	oldKey, newKey := bytes.Repeat([]byte{1}, 32), bytes.Repeat([]byte{2}, 32)
+	oldCipher, _ := NewValueCipher(oldKey)
+	newCipher, _ := NewValueCipher(newKey)
+	a1, _ := oldCipher.Encrypt("A", "a1")
+	a2, _ := oldCipher.Encrypt("A", "a2")
+	input := "A=" + a1 + "  #first\nB=plain\n  A=" + a2 + "\n"
+	d, err := NewDefault().ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := Rotate(d, newKey, oldKey); err == nil || d.String() != input {
+		t.Errorf("rotating with the wrong key: err = %v, d = %q", err, d.String())
+	}
+	if err := Rotate(d, oldKey, newKey[:16]); err != ErrKeySize(16) {
+		t.Errorf("err = %v", err)
+	}
+	if err := Rotate(d, oldKey, newKey); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(d.String(), "\n")
+	if len(lines) != 4 || lines[1] != "B=plain" || !strings.HasSuffix(lines[0], "  #first") {
+		t.Fatalf("d = %q", d.String())
+	}
+	for i, want := range map[int]string{0: "a1", 2: "a2"} {
+		value := strings.Fields(strings.SplitN(lines[i], "=", 2)[1])[0]
+		if v, err := newCipher.Decrypt("A", value); v != want || err != nil {
+			t.Errorf("line %d: Decrypt() = %v, %v", i+1, v, err)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package dotenv
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+)
+
+//ErrEncrypted is an error that occurs when an input appears to be a file that is
+//still encrypted by a tool that transparently encrypts files in a git
+//repository, such as git-crypt or transcrypt. Its value is the name of the tool.
+//It is returned instead of the errors from parsing the encrypted contents.
+type ErrEncrypted string
+
+//Error is the error implementation for ErrEncrypted.
+func (e ErrEncrypted) Error() string {
+	return fmt.Sprintf("dotenv: file appears encrypted by %v; unlock the repository", string(e))
+}
+
+//encryptedHeaders contains the headers that encrypted files start with, and the
+//tool that encrypted them.
+var encryptedHeaders = []struct {
+	header string
+	tool   string
+}{
+	{"\x00GITCRYPT\x00", "git-crypt"},
+	//transcrypt stores the output of openssl enc, which starts with "Salted__",
+	//either base64 encoded or as it is.
+	{"U2FsdGVkX1", "transcrypt"},
+	{"Salted__", "transcrypt"},
+}
+
+//checkEncrypted returns an ErrEncrypted if the input read by reader starts with
+//one of encryptedHeaders. No input is consumed.
+//Only the input of a single read is inspected, so that inputs such as pipes are
+//not read beyond their first line before it is visited.
+func checkEncrypted(reader *bufio.Reader) error {
+	reader.Peek(1)
+	head, _ := reader.Peek(reader.Buffered())
+	for _, encrypted := range encryptedHeaders {
+		if bytes.HasPrefix(head, []byte(encrypted.header)) {
+			return ErrEncrypted(encrypted.tool)
+		}
+	}
+	return nil
+}
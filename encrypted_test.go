@@ -0,0 +1,33 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestErrEncrypted_Error(t *testing.T) {
+	if ErrEncrypted("git-crypt").Error() != "dotenv: file appears encrypted by git-crypt; unlock the repository" {
+		t.Fail()
+	}
+}
+
+func TestSourcer_NameVars_encrypted(t *testing.T) {
+	cases := map[string]ErrEncrypted{
+		"\x00GITCRYPT\x00\x8f\x12\x00\xff\nA=a":                    "git-crypt",
+		"U2FsdGVkX1+vupppZksvRf5pq5g5XjFRlipRkwB0K1Y=\n":           "transcrypt",
+		"Salted__\x01\x02\x03":                                     "transcrypt",
+		"\x00GITCRYPT\x00" + strings.Repeat("\x01", 100000) + "\n": "git-crypt",
+	}
+	for source, want := range cases {
+		_, err := NewDefault().NameVars(strings.NewReader(source))
+		if err != want {
+			t.Errorf("err = %v WANT %v", err, want)
+		}
+	}
+
+	for _, source := range []string{"", "G", "A=U2FsdGVkX1", "GITCRYPT=1"} {
+		if _, _, err := NewDefault().NameVarsBestEffort(strings.NewReader(source)); err != nil {
+			t.Errorf("NameVarsBestEffort(%q) err = %v", source, err)
+		}
+	}
+}
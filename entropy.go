@@ -0,0 +1,128 @@
+package dotenv
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"strings"
+)
+
+//Default values for the fields of an EntropyDetector returned from
+//NewEntropyDetector().
+const (
+	DefaultEntropyThreshold    = 4.2
+	DefaultEntropyHexThreshold = 3.0
+	DefaultEntropyMinLength    = 20
+)
+
+//EntropyDetector finds values that look like plaintext secrets because they
+//contain long, random looking tokens. It is intended for files that are
+//committed, such as .env.example, where real secrets must not appear.
+//
+//Values are split into tokens of consecutive base64 and base64url characters,
+//and the Shannon entropy (in bits per character) of each token of at least
+//MinLength characters is compared against a threshold. Tokens consisting only of
+//hexadecimal digits use HexThreshold since their alphabet is smaller.
+type EntropyDetector struct {
+	//Threshold is the entropy above which a token is reported.
+	Threshold float64
+
+	//HexThreshold is the entropy above which a hexadecimal token is reported.
+	HexThreshold float64
+
+	//MinLength is the minimum length of tokens that are examined.
+	MinLength int
+
+	//AllowNames contains names, or path.Match() patterns of names, whose values
+	//are never reported, e.g. "*_PUBLIC_KEY".
+	AllowNames []string
+}
+
+//NewEntropyDetector returns an EntropyDetector with the default thresholds and
+//minimum length.
+func NewEntropyDetector() *EntropyDetector {
+	return &EntropyDetector{
+		Threshold:    DefaultEntropyThreshold,
+		HexThreshold: DefaultEntropyHexThreshold,
+		MinLength:    DefaultEntropyMinLength,
+	}
+}
+
+//Lint reports a Problem with the Rule "high-entropy" for every variable
+//definition in d whose value contains a token above e's thresholds.
+//Values encrypted by a ValueCipher are not reported.
+//It is a Linter.
+func (e *EntropyDetector) Lint(d *Document) []*Problem {
+	problems := []*Problem{}
+	for i, line := range d.lines {
+		if !line.isVariable || e.allowed(line.name) || IsEncrypted(line.value) {
+			continue
+		}
+		if token, entropy, ok := e.detect(line.value); ok {
+			problems = append(problems, &Problem{
+				Location: Location{i + 1, line.valueColumn(0)},
+				Name:     line.name,
+				Rule:     "high-entropy",
+				Message: fmt.Sprintf("value of %q may be a secret (%d character token with entropy %.2f)",
+					line.name, len(token), entropy),
+			})
+		}
+	}
+	return problems
+}
+
+//allowed determines whether or not name matches one of e.AllowNames.
+func (e *EntropyDetector) allowed(name string) bool {
+	for _, pattern := range e.AllowNames {
+		if ok, _ := path.Match(pattern, name); ok || pattern == name {
+			return true
+		}
+	}
+	return false
+}
+
+//detect returns the first token in value above e's thresholds and its entropy.
+func (e *EntropyDetector) detect(value string) (token string, entropy float64, ok bool) {
+	tokens := strings.FieldsFunc(value, func(r rune) bool {
+		return !isBase64Rune(r)
+	})
+	for _, token := range tokens {
+		if len(token) < e.MinLength {
+			continue
+		}
+		threshold := e.Threshold
+		if isHex(token) {
+			threshold = e.HexThreshold
+		}
+		if entropy := shannonEntropy(token); entropy > threshold {
+			return token, entropy, true
+		}
+	}
+	return "", 0, false
+}
+
+//isBase64Rune determines whether or not r is in the base64 or base64url
+//alphabets, including padding.
+func isBase64Rune(r rune) bool {
+	return ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') ||
+		strings.ContainsRune("+/=-_", r)
+}
+
+//isHex determines whether or not s contains only hexadecimal digits.
+func isHex(s string) bool {
+	return strings.Trim(s, "0123456789abcdefABCDEF") == ""
+}
+
+//shannonEntropy returns the Shannon entropy of the bytes in s, in bits per byte.
+func shannonEntropy(s string) float64 {
+	counts := map[byte]int{}
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	entropy := 0.0
+	for _, count := range counts {
+		p := float64(count) / float64(len(s))
+		entropy -= p * math.Log2(p)
+	}
+	return entropy
+}
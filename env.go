@@ -0,0 +1,378 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//Env is a set of parsed variables whose resolver backed values are resolved
+//lazily, on first use, instead of while parsing.
+//This allows a process to avoid fetching secrets it never reads.
+//An Env is safe for concurrent use. See Sourcer.Env.
+type Env struct {
+	sourcer *Sourcer
+	entries map[string]*envEntry
+	names   []string
+
+	//definitions contains every definition in order.
+	definitions []*envEntry
+
+	//cache contains the results of resolved references.
+	cache *resolveCache
+}
+
+//envEntry is a single variable definition in an Env.
+type envEntry struct {
+	name string
+	file string
+	line int
+
+	//deprecated is the Problem passed to Warn when the entry is read, or nil if
+	//it is not deprecated.
+	deprecated *Problem
+
+	//deps contains the definitions referenced by the value.
+	deps map[string]*envEntry
+
+	//expand returns the interpolated, but not yet resolved, value.
+	expand func(ctx context.Context) (string, error)
+
+	once  sync.Once
+	done  atomic.Bool
+	value string
+	err   error
+}
+
+//Env attempts to parse all variable definitions from in into an Env without
+//setting them.
+//Values that start with a scheme in s.Resolvers, and values that reference such
+//variables when s.Interpolate is true, are resolved the first time they are
+//requested from the Env. Their TransformValue and Policy are also deferred until
+//then, and any resulting error is returned, as an *ErrSourcing, from every call
+//that requests the value. All other values are processed immediately.
+//The result of resolving each distinct reference is reused within an Env.
+//If an error occurs while parsing or processing a value that is not deferred,
+//then that *ErrSourcing is returned.
+func (s *Sourcer) Env(in io.Reader) (*Env, error) {
+	env, layer := s.newEnv()
+	if err := layer.read(in, ""); err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+//EnvFiles is the same as Env except that it parses the files at paths as layers
+//of a single Env, in order, so that definitions in later files override those
+//in earlier files, and, if s.Interpolate is true, can reference them.
+//If an error occurs, then it is returned prefixed with the path of its file.
+func (s *Sourcer) EnvFiles(paths ...string) (*Env, error) {
+	env, layer := s.newEnv()
+	for _, path := range paths {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = layer.read(file, path)
+		file.Close()
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", path, err)
+		}
+	}
+	return env, nil
+}
+
+//envLayers adds the definitions of inputs to an Env.
+type envLayers struct {
+	sourcer *Sourcer
+	env     *Env
+
+	//defined contains the definitions that later values can reference.
+	defined map[string]*envEntry
+
+	//lazy contains the definitions whose values are resolved on first use.
+	lazy map[*envEntry]bool
+}
+
+//newEnv returns an empty Env for s and the envLayers that add to it.
+func (s *Sourcer) newEnv() (*Env, *envLayers) {
+	env := &Env{
+		sourcer:     s,
+		entries:     map[string]*envEntry{},
+		names:       []string{},
+		definitions: []*envEntry{},
+		cache:       &resolveCache{},
+	}
+	return env, &envLayers{s, env, map[string]*envEntry{}, map[*envEntry]bool{}}
+}
+
+//read adds all variable definitions from in, which is read from the file at path
+//or not from a file if path is empty, to l.env, as described by Env.
+func (l *envLayers) read(in io.Reader, path string) error {
+	s, in, dialectErr := l.sourcer.dialectFor(in)
+	if dialectErr != nil {
+		return dialectErr
+	}
+	env, defined, lazy := l.env, l.defined, l.lazy
+	return scanLines(in, func(lineNumber int, line string) error {
+		variable, passThrough, err := s.parseVariable(lineNumber, line)
+		if err != nil {
+			s.metrics().LineError()
+			return &ErrSourcing{lineNumber, err}
+		}
+		if variable == nil {
+			return nil
+		}
+
+		raw := variable.Value
+		_, _, _, isLazy := s.resolverScheme(raw)
+		entry := &envEntry{name: variable.Name, file: path, line: lineNumber, deps: map[string]*envEntry{}}
+		if variable.Deprecated != "" {
+			parsed, _ := s.nameVar(line)
+			_, column := s.deprecation(line, parsed)
+			entry.deprecated = deprecatedProblem(Location{lineNumber, column}, entry.name, "read", variable.Deprecated)
+		}
+		if s.Interpolate && !passThrough {
+			for _, ref := range references(raw, s.InterpolatePercent) {
+				if dep, ok := defined[s.alias(ref.name)]; ok {
+					entry.deps[dep.name] = dep
+					isLazy = isLazy || lazy[dep]
+				}
+			}
+		}
+		entry.expand = func(ctx context.Context) (string, error) {
+			if !s.Interpolate || passThrough {
+				return raw, nil
+			}
+			return s.expand(raw, func(name string) (string, bool, error) {
+				dep, ok := entry.deps[name]
+				if !ok {
+					return "", false, nil
+				}
+				value, err := env.get(ctx, dep)
+				return value, true, err
+			})
+		}
+		if isLazy {
+			lazy[entry] = true
+		} else if _, err := env.get(context.Background(), entry); err != nil {
+			s.metrics().LineError()
+			return err
+		}
+
+		env.add(entry)
+		if s.Interpolate {
+			defined[entry.name] = entry
+		}
+		return nil
+	})
+}
+
+//add adds entry to env as the last definition of its name.
+func (env *Env) add(entry *envEntry) {
+	if _, ok := env.entries[entry.name]; !ok {
+		env.names = append(env.names, entry.name)
+	}
+	env.entries[entry.name] = entry
+	env.definitions = append(env.definitions, entry)
+}
+
+//get returns the value of entry, evaluating it with ctx the first time it is
+//called.
+func (env *Env) get(ctx context.Context, entry *envEntry) (string, error) {
+	entry.once.Do(func() {
+		entry.value, entry.err = env.evaluate(ctx, entry)
+		//errors from referenced variables already describe their own line.
+		if _, ok := entry.err.(*ErrSourcing); entry.err != nil && !ok {
+			entry.err = &ErrSourcing{entry.line, entry.err}
+		}
+		entry.done.Store(true)
+	})
+	return entry.value, entry.err
+}
+
+//evaluate returns the final value of entry.
+func (env *Env) evaluate(ctx context.Context, entry *envEntry) (string, error) {
+	v, err := entry.expand(ctx)
+	if err != nil {
+		return "", err
+	}
+	return env.sourcer.finishValue(ctx, entry.name, v, env.cache)
+}
+
+//Names returns the names of all variables in env in the order of their first
+//definition.
+func (env *Env) Names() []string {
+	return append([]string{}, env.names...)
+}
+
+//Get returns the value of the last definition of name in env, resolving it if it
+//has not been resolved yet.
+//If name is not defined in env, then an ErrMissingVariables is returned.
+//If the last definition of name is deprecated, then it is passed to the
+//Sourcer's Warn. If name is an alias in the Sourcer's Aliases, then the value of
+//its new name is returned, and the read is passed to Warn with a zero Location.
+//If resolving the value, or a value it references, fails, then an *ErrSourcing
+//for the failed line is returned, and the same error is returned from all later
+//calls.
+func (env *Env) Get(name string) (string, error) {
+	return env.GetContext(context.Background(), name)
+}
+
+//GetContext is the same as Get except that ctx is passed to Resolvers if the
+//value has not been resolved yet.
+func (env *Env) GetContext(ctx context.Context, name string) (string, error) {
+	if newName := env.sourcer.alias(name); newName != name {
+		env.sourcer.warn(aliasProblem(Location{}, name, newName, "read"))
+		name = newName
+	}
+	entry, ok := env.entries[name]
+	if !ok {
+		return "", ErrMissingVariables{name}
+	}
+	if entry.deprecated != nil {
+		problem := *entry.deprecated
+		env.sourcer.warn(&problem)
+	}
+	return env.get(ctx, entry)
+}
+
+//Variable returns the last definition of name in env with its value, as returned
+//from Get, and its origin, i.e. the File it was read from by EnvFiles and its
+//Line. Only Name, Value, File, and Line are set.
+func (env *Env) Variable(name string) (*Variable, error) {
+	value, err := env.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	entry := env.entries[env.sourcer.alias(name)]
+	return &Variable{Name: entry.name, Value: value, File: entry.file, Line: entry.line}, nil
+}
+
+//Resolve resolves all values in env that have not been resolved yet and returns
+//the first error in the order of Names().
+//Values are resolved in rounds, where each round contains the values whose
+//references have been resolved, so that the references of each BatchResolver
+//in a round are resolved with a single call.
+func (env *Env) Resolve() error {
+	return env.ResolveContext(context.Background())
+}
+
+//ResolveContext is the same as Resolve except that ctx is passed to Resolvers.
+func (env *Env) ResolveContext(ctx context.Context) error {
+	for {
+		round := []*envEntry{}
+		values := []string{}
+		for _, entry := range env.definitions {
+			if entry.done.Load() || !entry.depsDone() {
+				continue
+			}
+			round = append(round, entry)
+			//an error is reported when entry is evaluated by get.
+			if v, err := entry.expand(ctx); err == nil {
+				values = append(values, v)
+			}
+		}
+		if len(round) == 0 {
+			break
+		}
+		env.sourcer.resolveBatches(ctx, values, env.cache)
+		for _, entry := range round {
+			env.get(ctx, entry)
+		}
+	}
+
+	for _, name := range env.names {
+		if _, err := env.GetContext(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//depsDone determines whether or not all definitions referenced by e have been
+//evaluated.
+func (e *envEntry) depsDone() bool {
+	for _, dep := range e.deps {
+		if !dep.done.Load() {
+			return false
+		}
+	}
+	return true
+}
+
+//Source resolves all values in env and then sets them in the process's
+//environment with os.Setenv().
+//If any value cannot be resolved, then that error is returned and nothing is
+//set.
+func (env *Env) Source() error {
+	return env.SourceContext(context.Background())
+}
+
+//SourceContext is the same as Source except that ctx is passed to Resolvers.
+func (env *Env) SourceContext(ctx context.Context) error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	if err := env.ResolveContext(ctx); err != nil {
+		return err
+	}
+	st := env.sourcer.newSetter()
+	for _, name := range env.names {
+		value, _ := env.Get(name)
+		if _, err := st.setenv(name, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Environ resolves all values in env and returns base, which is of the form
+//returned by os.Environ(), with them set, e.g. as the environment of a child
+//process. Values of base are replaced in place, and names not in base are
+//appended in order. If env's Sourcer has NoOverride set, then names in base are
+//left unchanged.
+//If any value cannot be resolved, then that error is returned.
+func (env *Env) Environ(base []string) ([]string, error) {
+	if err := env.ResolveContext(context.Background()); err != nil {
+		return nil, err
+	}
+	result := append([]string{}, base...)
+	indexes := make(map[string]int, len(base))
+	for i, entry := range base {
+		if j := strings.IndexByte(entry, '='); j >= 0 {
+			indexes[entry[:j]] = i
+		}
+	}
+	for _, name := range env.names {
+		value, _ := env.Get(name)
+		i, ok := indexes[name]
+		switch {
+		case !ok:
+			result = append(result, name+"="+value)
+		case !env.sourcer.NoOverride:
+			result[i] = name + "=" + value
+		}
+	}
+	return result, nil
+}
+
+//ReferenceEnv returns an Env that defines each of names, in order, with a
+//reference to itself with scheme, i.e. as if parsed from lines of the form
+//NAME=SCHEME:NAME. The values are resolved lazily with s.Resolvers[scheme] like
+//those of any other Env, so that all of them are fetched with a single call if it
+//is a BatchResolver.
+//This allows the secrets of a remote secrets manager to be imported without a
+//file that lists their references.
+//If a name cannot be parsed by s, then that *ErrSourcing is returned.
+func (s *Sourcer) ReferenceEnv(scheme string, names ...string) (*Env, error) {
+	buf := &strings.Builder{}
+	for _, name := range names {
+		fmt.Fprintf(buf, "%v=%v:%v\n", name, scheme, name)
+	}
+	return s.Env(strings.NewReader(buf.String()))
+}
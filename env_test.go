@@ -0,0 +1,286 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+//countingResolver returns ref as its value and counts its calls.
+type countingResolver struct {
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func (r *countingResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[ref]++
+	if ref == "bad" {
+		return "", errors.New("bad ref")
+	}
+	return "<" + ref + ">", nil
+}
+
+func TestSourcer_Env_lazy(t *testing.T) {
+	resolver := &countingResolver{calls: map[string]int{}}
+	s := NewDefault()
+	s.Interpolate = true
+	s.Resolvers = map[string]Resolver{"vault": resolver}
+	source := `PLAIN=plain
+SECRET=vault:db
+URL=user:${SECRET}@${PLAIN}
+UNUSED=vault:other
+BAD=vault:bad
+USES_BAD=${BAD}
+PLAIN=again
+`
+	env, err := s.Env(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resolver.calls) != 0 {
+		t.Errorf("resolver called while parsing: %v", resolver.calls)
+	}
+	if !reflect.DeepEqual(env.Names(), []string{"PLAIN", "SECRET", "URL", "UNUSED", "BAD", "USES_BAD"}) {
+		t.Errorf("env.Names() = %v", env.Names())
+	}
+
+	for i := 0; i < 2; i++ {
+		if value, err := env.Get("URL"); value != "user:<db>@plain" || err != nil {
+			t.Errorf("Get(URL) = %q, %v", value, err)
+		}
+	}
+	if value, _ := env.Get("PLAIN"); value != "again" {
+		t.Errorf("Get(PLAIN) = %q", value)
+	}
+	if !reflect.DeepEqual(resolver.calls, map[string]int{"db": 1}) {
+		t.Errorf("resolver.calls = %v", resolver.calls)
+	}
+
+	want := &ErrSourcing{5, &ErrResolve{"BAD", "vault", errors.New("bad ref")}}
+	for _, name := range []string{"BAD", "USES_BAD", "BAD"} {
+		if _, err := env.Get(name); !reflect.DeepEqual(err, want) {
+			t.Errorf("Get(%v) err = %v", name, err)
+		}
+	}
+	if resolver.calls["bad"] != 1 {
+		t.Error("errors must be cached")
+	}
+	if err := env.Resolve(); !reflect.DeepEqual(err, want) {
+		t.Errorf("Resolve() = %v", err)
+	}
+	if _, err := env.Get("MISSING"); !reflect.DeepEqual(err, ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_Env_eagerErrors(t *testing.T) {
+	s := NewDefault()
+	s.Policy = ForbidValues("changeme")
+	_, err := s.Env(strings.NewReader("A=a\nB=changeme"))
+	if !reflect.DeepEqual(err, &ErrSourcing{2, &ErrPolicyViolation{"B", "forbidden value"}}) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.Env(strings.NewReader("bad line")); err == nil {
+		t.Fail()
+	}
+}
+
+func TestEnv_Source(t *testing.T) {
+	skipUnlessSetenv(t)
+	defer os.Unsetenv("GOGOLFING_DOTENV_ENV_A")
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"vault": &countingResolver{calls: map[string]int{}}}
+
+	env, err := s.Env(strings.NewReader("GOGOLFING_DOTENV_ENV_A=vault:a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Source(); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("GOGOLFING_DOTENV_ENV_A") != "<a>" {
+		t.Fail()
+	}
+
+	env, err = s.Env(strings.NewReader("GOGOLFING_DOTENV_ENV_B=b\nGOGOLFING_DOTENV_ENV_C=vault:bad"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Source(); err == nil {
+		t.Fail()
+	}
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_ENV_B"); ok {
+		t.Error("Source() must not set anything on error")
+	}
+}
+
+//batchResolver is a BatchResolver that records its calls.
+type batchResolver struct {
+	countingResolver
+	batches [][]string
+	err     error
+}
+
+func (r *batchResolver) ResolveBatch(ctx context.Context, refs []string) (map[string]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sorted := append([]string{}, refs...)
+	sort.Strings(sorted)
+	r.batches = append(r.batches, sorted)
+	if r.err != nil {
+		return nil, r.err
+	}
+	result := map[string]string{}
+	for _, ref := range refs {
+		if ref != "single" {
+			result[ref] = "[" + ref + "]"
+		}
+	}
+	return result, nil
+}
+
+func TestEnv_Resolve_batch(t *testing.T) {
+	resolver := &batchResolver{countingResolver: countingResolver{calls: map[string]int{}}}
+	s := NewDefault()
+	s.Interpolate = true
+	s.Resolvers = map[string]Resolver{"ssm": resolver, "vault": &countingResolver{calls: map[string]int{}}}
+	source := `A=ssm:a
+B=ssm:b
+C=ssm:a
+D=vault:d
+PREFIX=ssm:prefix
+E=ssm:${PREFIX}/e
+F=ssm:single
+`
+	env, err := s.Env(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"a", "b", "prefix", "single"}, {"[prefix]/e"}}
+	if !reflect.DeepEqual(resolver.batches, want) {
+		t.Errorf("batches = %v WANT %v", resolver.batches, want)
+	}
+	if !reflect.DeepEqual(resolver.calls, map[string]int{"single": 1}) {
+		t.Errorf("calls = %v", resolver.calls)
+	}
+	values := []string{}
+	for _, name := range env.Names() {
+		value, _ := env.Get(name)
+		values = append(values, value)
+	}
+	if !reflect.DeepEqual(values, []string{"[a]", "[b]", "[a]", "<d>", "[prefix]", "[[prefix]/e]", "<single>"}) {
+		t.Errorf("values = %v", values)
+	}
+}
+
+func TestEnv_Resolve_batchError(t *testing.T) {
+	batchErr := errors.New("unavailable")
+	resolver := &batchResolver{countingResolver: countingResolver{calls: map[string]int{}}, err: batchErr}
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"ssm": resolver}
+	env, err := s.Env(strings.NewReader("A=ssm:a\nB=ssm:b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := env.Resolve(); !reflect.DeepEqual(err, &ErrSourcing{1, &ErrResolve{"A", "ssm", batchErr}}) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := env.Get("B"); !reflect.DeepEqual(err, &ErrSourcing{2, &ErrResolve{"B", "ssm", batchErr}}) {
+		t.Errorf("err = %v", err)
+	}
+	if len(resolver.calls) != 0 || len(resolver.batches) != 1 {
+		t.Errorf("calls, batches = %v, %v", resolver.calls, resolver.batches)
+	}
+}
+
+func TestEnv_Environ(t *testing.T) {
+	input := "A=a\nB=$A-b\nC=c\n"
+	base := []string{"B=base", "PATH=/bin", "invalid"}
+	tests := []struct {
+		noOverride bool
+		want       []string
+	}{
+		{false, []string{"B=a-b", "PATH=/bin", "invalid", "A=a", "C=c"}},
+		{true, []string{"B=base", "PATH=/bin", "invalid", "A=a", "C=c"}},
+	}
+	for _, test := range tests {
+		s := NewDefault()
+		s.Interpolate, s.NoOverride = true, test.noOverride
+		env, err := s.Env(strings.NewReader(input))
+		if err != nil {
+			t.Fatal(err)
+		}
+		environ, err := env.Environ(base)
+		if err != nil || !reflect.DeepEqual(environ, test.want) {
+			t.Errorf("Environ() = %v, %v, want %v", environ, err, test.want)
+		}
+	}
+	if !reflect.DeepEqual(base, []string{"B=base", "PATH=/bin", "invalid"}) {
+		t.Errorf("base = %v", base)
+	}
+}
+
+func TestSourcer_ReferenceEnv(t *testing.T) {
+	resolver := &batchResolver{countingResolver: countingResolver{calls: map[string]int{}}}
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"vault": resolver}
+	env, err := s.ReferenceEnv("vault", "B", "A")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(env.Names(), []string{"B", "A"}) {
+		t.Errorf("names = %v", env.Names())
+	}
+	if err := env.Resolve(); err != nil {
+		t.Fatal(err)
+	}
+	if value, _ := env.Get("A"); value != "[A]" || len(resolver.batches) != 1 {
+		t.Errorf("value, batches = %q, %v", value, resolver.batches)
+	}
+
+	if _, err := s.ReferenceEnv("vault", "A B"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSourcer_EnvFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	first, second := filepath.Join(dir, "first"), filepath.Join(dir, "second")
+	ioutil.WriteFile(first, []byte("A=a\nB=b\n"), 0600)
+	ioutil.WriteFile(second, []byte("B=${A}2\nC=c\n"), 0600)
+
+	s := NewDefault()
+	s.Interpolate = true
+	env, err := s.EnvFiles(first, second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := env.Names(); !reflect.DeepEqual(names, []string{"A", "B", "C"}) {
+		t.Errorf("Names() = %v", names)
+	}
+	if v, err := env.Get("B"); v != "a2" || err != nil {
+		t.Errorf("Get(B) = %v, %v", v, err)
+	}
+
+	ioutil.WriteFile(second, []byte("B=b\nbad line\n"), 0600)
+	var sourcingErr *ErrSourcing
+	if _, err := s.EnvFiles(first, second); !errors.As(err, &sourcingErr) || sourcingErr.Line != 2 || !strings.HasPrefix(err.Error(), second+": ") {
+		t.Errorf("err = %v", err)
+	}
+}
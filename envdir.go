@@ -0,0 +1,73 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//DirOptions are the options for EncodeDir.
+type DirOptions struct {
+	//Perm is the permission of the written files. A zero Perm means 0600.
+	Perm os.FileMode
+
+	//Newline appends a newline to every value, as many tools expect text files
+	//to end with one. Credentials() removes it again.
+	Newline bool
+
+	//Envdir writes files for the envdir program of daemontools and runit, which
+	//reads the first line of a file and turns NUL bytes into newlines. Newlines
+	//in values are therefore written as NUL bytes, and values that end in
+	//whitespace, which envdir removes, cause an *ErrUnencodableValue.
+	//Note that envdir removes variables whose file is empty instead of setting
+	//them to the empty value.
+	Envdir bool
+}
+
+//EncodeDir writes each name, value association in nameVars to dir as one file
+//per variable, whose name is the variable's name and whose content is its value.
+//This is the convention of /env on Plan 9 and of envdir, and the inverse of
+//Sourcer.Credentials(). Only the last definition of a name is written.
+//dir is created if it does not exist, and existing files for other names are left
+//as they are. options may be nil, in which case values are written as they are.
+//If a name cannot be a file name, e.g. because it contains a path separator or
+//starts with a dot, then an ErrInvalidName is returned before anything is
+//written.
+func EncodeDir(dir string, nameVars [][2]string, options *DirOptions) error {
+	if options == nil {
+		options = &DirOptions{}
+	}
+	perm := options.Perm
+	if perm == 0 {
+		perm = 0600
+	}
+
+	nameVars = lastDefinitions(nameVars)
+	contents := make([]string, len(nameVars))
+	for i, nameVar := range nameVars {
+		name, value := nameVar[0], nameVar[1]
+		if name == "" || strings.HasPrefix(name, ".") || strings.ContainsAny(name, `/\`+"\x00") {
+			return ErrInvalidName(name)
+		}
+		if options.Envdir {
+			if strings.TrimRight(value, SpaceTab) != value {
+				return &ErrUnencodableValue{name, "envdir"}
+			}
+			value = strings.Replace(value, "\n", "\x00", -1)
+		}
+		if options.Newline {
+			value += "\n"
+		}
+		contents[i] = value
+	}
+
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	for i, nameVar := range nameVars {
+		if err := os.WriteFile(filepath.Join(dir, nameVar[0]), []byte(contents[i]), perm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	dir = filepath.Join(dir, "env")
+
+	nameVars := [][2]string{{"A", "a"}, {"B", "line 1\nline 2"}, {"A", "last"}, {"EMPTY", ""}}
+	if err := EncodeDir(dir, nameVars, &DirOptions{Newline: true}); err != nil {
+		t.Fatal(err)
+	}
+	variables, err := NewDefault().Credentials(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := [][2]string{}
+	for _, variable := range variables {
+		result = append(result, [2]string{variable.Name, variable.Value})
+	}
+	if !reflect.DeepEqual(result, [][2]string{{"A", "last"}, {"B", "line 1\nline 2"}, {"EMPTY", ""}}) {
+		t.Errorf("result = %v", result)
+	}
+	if info, err := os.Stat(filepath.Join(dir, "A")); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("info, err = %v, %v", info, err)
+	}
+
+	if err := EncodeDir(dir, [][2]string{{"A", "plan9"}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "A")); string(data) != "plan9" || err != nil {
+		t.Errorf("data, err = %q, %v", data, err)
+	}
+}
+
+func TestEncodeDir_envdir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	options := &DirOptions{Envdir: true, Newline: true, Perm: 0644}
+	if err := EncodeDir(dir, [][2]string{{"A", "a\nb"}}, options); err != nil {
+		t.Fatal(err)
+	}
+	if data, err := ioutil.ReadFile(filepath.Join(dir, "A")); string(data) != "a\x00b\n" || err != nil {
+		t.Errorf("data, err = %q, %v", data, err)
+	}
+	err = EncodeDir(dir, [][2]string{{"A", "a "}}, options)
+	if !reflect.DeepEqual(err, &ErrUnencodableValue{"A", "envdir"}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestEncodeDir_invalidName(t *testing.T) {
+	dir, err := ioutil.TempDir("", "dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, name := range []string{"", ".A", "../A", `A\B`} {
+		err := EncodeDir(dir, [][2]string{{"B", "b"}, {name, "a"}}, nil)
+		if err != ErrInvalidName(name) {
+			t.Errorf("%q: err = %v", name, err)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("entries = %v", entries)
+	}
+}
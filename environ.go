@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"context"
+	"io"
+	"strings"
+)
+
+//ParseEnviron parses in as a NUL separated list of NAME=VALUE entries, which is
+//the format of /proc/<pid>/environ on Linux, into an Env.
+//Values are used exactly as they appear. They are not unquoted, interpolated, or
+//resolved. The line of each variable is the 1-based index of its entry, and
+//entries without an equal sign or with an empty name are ignored.
+//If an error occurs while reading in, then that error is returned.
+func ParseEnviron(in io.Reader) (*Env, error) {
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return nil, err
+	}
+	return environEnv(strings.Split(string(data), "\x00")), nil
+}
+
+//environEnv returns an Env containing the NAME=VALUE entries in environ. Entries
+//without an equal sign or with an empty name are ignored.
+func environEnv(environ []string) *Env {
+	env := newValueEnv()
+	for i, pair := range environ {
+		if equal := strings.Index(pair, "="); equal > 0 {
+			env.addValue(pair[:equal], pair[equal+1:], i+1)
+		}
+	}
+	return env
+}
+
+//newValueEnv returns an empty Env for values that are used exactly as they are.
+func newValueEnv() *Env {
+	return &Env{
+		sourcer:     &Sourcer{},
+		entries:     map[string]*envEntry{},
+		names:       []string{},
+		definitions: []*envEntry{},
+		cache:       &resolveCache{},
+	}
+}
+
+//addValue adds a definition of name with value, which is used exactly as it is,
+//to env.
+func (env *Env) addValue(name, value string, line int) {
+	entry := &envEntry{name: name, line: line, deps: map[string]*envEntry{}}
+	entry.expand = func(ctx context.Context) (string, error) {
+		return value, nil
+	}
+	env.get(context.Background(), entry)
+	env.add(entry)
+}
@@ -0,0 +1,21 @@
+package dotenv
+
+import (
+	"os"
+	"strconv"
+)
+
+//ProcEnviron reads the environment of the process with pid from
+///proc/<pid>/environ and parses it with ParseEnviron().
+//Note that procfs reports the environment the process was started with. Changes
+//the process makes to its own environment afterwards are not visible.
+//Reading the environment of another user's process typically requires elevated
+//privileges, in which case the error from os.Open() is returned.
+func ProcEnviron(pid int) (*Env, error) {
+	file, err := os.Open("/proc/" + strconv.Itoa(pid) + "/environ")
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseEnviron(file)
+}
@@ -0,0 +1,21 @@
+package dotenv
+
+import (
+	"os"
+	"testing"
+)
+
+func TestProcEnviron(t *testing.T) {
+	env, err := ProcEnviron(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//the test binary's initial environment contains PATH.
+	if value, err := env.Get("PATH"); value != os.Getenv("PATH") || err != nil {
+		t.Errorf("PATH = %q, %v", value, err)
+	}
+
+	if _, err := ProcEnviron(-1); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseEnviron(t *testing.T) {
+	in := "A=a\x00B=\x00=bad\x00noequal\x00C=$A \"c\"\x00A=a=2\x00"
+	env, err := ParseEnviron(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := env.Names(); !reflect.DeepEqual(names, []string{"A", "B", "C"}) {
+		t.Errorf("names = %v", names)
+	}
+	cases := map[string]string{"A": "a=2", "B": "", "C": `$A "c"`}
+	for name, want := range cases {
+		if value, err := env.Get(name); value != want || err != nil {
+			t.Errorf("Get(%q) = %q, %v WANT %q", name, value, err, want)
+		}
+	}
+	if _, err := env.Get("noequal"); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,111 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+//Rules of the Problems reported by the Linter returned from LintExpiry.
+const (
+	RuleExpired       = "expired"
+	RuleExpiresSoon   = "expires-soon"
+	RuleInvalidExpiry = "invalid-expiry"
+)
+
+//expiresPrefix starts the text of a comment that sets the date by which a
+//variable, or a file, must be changed.
+const expiresPrefix = "expires:"
+
+//LintExpiry returns a Linter that reports the expiry annotations of a Document
+//that are past due at now, or due within soon of now, so that credentials that
+//must be rotated by a date can be tracked. An annotation is a comment of the
+//form "#expires: 2025-06-01", with a date or an RFC 3339 time, that is either
+//the inline comment of a definition, or a comment line. A comment line applies
+//to the definition its contiguous comment lines immediately precede, and
+//otherwise to the whole file, in which case its Problem has no Name.
+//A date expires at its start in UTC. The rules are:
+//
+//	expired: the annotation is at or after its date at now.
+//	expires-soon: the annotation expires within soon after now.
+//	invalid-expiry: the date of the annotation cannot be parsed.
+//
+//A zero soon means that only expired annotations are reported.
+func LintExpiry(now time.Time, soon time.Duration) Linter {
+	return func(d *Document) []*Problem {
+		problems := []*Problem{}
+		for i, line := range d.lines {
+			value, column, ok := d.expiry(i)
+			if !ok {
+				continue
+			}
+			problem := &Problem{Location: Location{i + 1, column}}
+			subject := "the file"
+			if line.isVariable {
+				problem.Name = line.name
+			} else if j := d.commentEnd(i); j < len(d.lines) && d.lines[j].isVariable {
+				problem.Name = d.lines[j].name
+			}
+			if problem.Name != "" {
+				subject = fmt.Sprintf("%q", problem.Name)
+			}
+
+			expires, err := parseExpiry(value)
+			switch {
+			case err != nil:
+				problem.Rule = RuleInvalidExpiry
+				problem.Message = fmt.Sprintf("the expiry %q of %v is not a date", value, subject)
+			case !now.Before(expires):
+				problem.Rule = RuleExpired
+				problem.Message = fmt.Sprintf("%v expired on %v", subject, value)
+			case soon > 0 && expires.Sub(now) <= soon:
+				problem.Rule = RuleExpiresSoon
+				problem.Message = fmt.Sprintf("%v expires on %v", subject, value)
+			default:
+				continue
+			}
+			problems = append(problems, problem)
+		}
+		return problems
+	}
+}
+
+//expiry returns the value and column (1-based) of the expiry annotation on the
+//line at index i of d, and whether or not the line has one.
+func (d *Document) expiry(i int) (value string, column int, ok bool) {
+	line, comment := d.lines[i], d.sourcer.Comment
+	if line.isVariable {
+		if value, ok = annotation(line.comment, expiresPrefix); !line.hasComment || !ok {
+			return "", 0, false
+		}
+		return value, len(line.raw) - len(line.comment) - len(comment) + 1, true
+	}
+	text := strings.TrimLeft(line.raw, SpaceTab)
+	if comment == "" || !strings.HasPrefix(text, comment) {
+		return "", 0, false
+	}
+	if value, ok = annotation(text[len(comment):], expiresPrefix); !ok {
+		return "", 0, false
+	}
+	return value, len(line.raw) - len(text) + 1, true
+}
+
+//commentEnd returns the index of the first line after the contiguous comment
+//lines starting at index i of d.
+func (d *Document) commentEnd(i int) int {
+	for ; i < len(d.lines); i++ {
+		text := strings.TrimLeft(d.lines[i].raw, SpaceTab)
+		if d.lines[i].isVariable || d.sourcer.Comment == "" || !strings.HasPrefix(text, d.sourcer.Comment) {
+			break
+		}
+	}
+	return i
+}
+
+//parseExpiry parses value as a date, which is in UTC, or an RFC 3339 time.
+func parseExpiry(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
@@ -0,0 +1,41 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLintExpiry(t *testing.T) {
+	input := `#expires: 2025-01-01
+
+#database
+  #expires: 2025-06-01
+DB_PASSWORD=secret
+API_KEY=key #expires: 2025-06-10T12:00:00Z
+TOKEN=token #expires: soon
+OTHER=other #expires: 2026-01-01
+`
+	d, err := NewDefault().ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	problems := LintExpiry(now, 0)(d)
+	want := []*Problem{
+		{Location{1, 1}, "", RuleExpired, "the file expired on 2025-01-01"},
+		{Location{4, 3}, "DB_PASSWORD", RuleExpired, `"DB_PASSWORD" expired on 2025-06-01`},
+		{Location{7, 13}, "TOKEN", RuleInvalidExpiry, `the expiry "soon" of "TOKEN" is not a date`},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v", problems)
+	}
+
+	problems = LintExpiry(now, 10*24*time.Hour)(d)
+	want = append(want[:2], &Problem{Location{6, 13}, "API_KEY", RuleExpiresSoon, `"API_KEY" expires on 2025-06-10T12:00:00Z`}, want[2])
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v", problems)
+	}
+}
@@ -0,0 +1,81 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+//FetchResolver is a BatchResolver for secrets managers whose API returns all
+//secrets of a project at once, such as Doppler and Infisical. Its references are
+//secret names, and every call fetches all secrets with one call to Fetch.
+type FetchResolver struct {
+	//Scheme is the resolver scheme of the references, e.g. "doppler". It is used
+	//by Env and in errors.
+	Scheme string
+
+	//Fetch returns all secrets by name.
+	Fetch func(ctx context.Context) (map[string]string, error)
+}
+
+//Resolve implements Resolver by returning the secret named ref.
+//If there is no such secret, then a *ErrPermanent is returned.
+func (f *FetchResolver) Resolve(ctx context.Context, ref string) (string, error) {
+	secrets, err := f.Fetch(ctx)
+	if err != nil {
+		return "", err
+	}
+	return f.lookup(secrets, ref)
+}
+
+//ResolveBatch implements BatchResolver by returning the secrets named refs that
+//exist.
+func (f *FetchResolver) ResolveBatch(ctx context.Context, refs []string) (map[string]string, error) {
+	secrets, err := f.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := map[string]string{}
+	for _, ref := range refs {
+		if value, ok := secrets[ref]; ok {
+			result[ref] = value
+		}
+	}
+	return result, nil
+}
+
+//Env fetches all secrets and returns them as an Env parsed by s, in order of
+//name, as if each were defined with a reference of the form SCHEME:NAME, as with
+//s.ReferenceEnv. The values are then processed by s like those of any other Env,
+//e.g. with its TransformValue and Policy, without fetching them again.
+//s itself is not modified.
+func (f *FetchResolver) Env(ctx context.Context, s *Sourcer) (*Env, error) {
+	secrets, err := f.Fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(secrets))
+	for name := range secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	withSecrets := *s
+	withSecrets.Resolvers = map[string]Resolver{}
+	for scheme, resolver := range s.Resolvers {
+		withSecrets.Resolvers[scheme] = resolver
+	}
+	withSecrets.Resolvers[f.Scheme] = ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return f.lookup(secrets, ref)
+	})
+	return withSecrets.ReferenceEnv(f.Scheme, names...)
+}
+
+//lookup returns the secret named ref, or a *ErrPermanent if it does not exist.
+func (f *FetchResolver) lookup(secrets map[string]string, ref string) (string, error) {
+	value, ok := secrets[ref]
+	if !ok {
+		return "", &ErrPermanent{Err: fmt.Errorf("%v: secret %q does not exist", f.Scheme, ref)}
+	}
+	return value, nil
+}
@@ -0,0 +1,71 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func newTestFetchResolver(secrets map[string]string, err error) (*FetchResolver, *int) {
+	fetches := 0
+	return &FetchResolver{
+		Scheme: "vault",
+		Fetch: func(ctx context.Context) (map[string]string, error) {
+			fetches++
+			return secrets, err
+		},
+	}, &fetches
+}
+
+func TestFetchResolver_Resolve(t *testing.T) {
+	f, fetches := newTestFetchResolver(map[string]string{"A": "a", "B": "b"}, nil)
+	result, err := f.ResolveBatch(context.Background(), []string{"A", "C"})
+	if err != nil || !reflect.DeepEqual(result, map[string]string{"A": "a"}) {
+		t.Errorf("result, err = %v, %v", result, err)
+	}
+	if value, err := f.Resolve(context.Background(), "B"); value != "b" || err != nil {
+		t.Errorf("value, err = %q, %v", value, err)
+	}
+	var permanent *ErrPermanent
+	_, err = f.Resolve(context.Background(), "C")
+	if !errors.As(err, &permanent) || err.Error() != `permanent failure: vault: secret "C" does not exist` {
+		t.Errorf("err = %v", err)
+	}
+	if *fetches != 3 {
+		t.Errorf("fetches = %v", *fetches)
+	}
+
+	fetchErr := errors.New("unavailable")
+	f, _ = newTestFetchResolver(nil, fetchErr)
+	if _, err := f.Resolve(context.Background(), "A"); err != fetchErr {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := f.ResolveBatch(context.Background(), []string{"A"}); err != fetchErr {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := f.Env(context.Background(), NewDefault()); err != fetchErr {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestFetchResolver_Env(t *testing.T) {
+	f, fetches := newTestFetchResolver(map[string]string{"B": "b", "A": "a"}, nil)
+	s := NewDefault()
+	s.TransformValue = func(name, value string) (string, error) {
+		return name + "=" + value, nil
+	}
+	env, err := f.Env(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(env.Names(), []string{"A", "B"}) {
+		t.Errorf("names = %v", env.Names())
+	}
+	if value, err := env.Get("B"); value != "B=b" || err != nil {
+		t.Errorf("value, err = %q, %v", value, err)
+	}
+	if *fetches != 1 || s.Resolvers != nil {
+		t.Errorf("fetches, s.Resolvers = %v, %v", *fetches, s.Resolvers)
+	}
+}
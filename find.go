@@ -0,0 +1,42 @@
+package dotenv
+
+import (
+	"strings"
+)
+
+//FindValue returns the names of the variables in env whose values contain needle,
+//in the order of env.Names(), e.g. to find every variable that refers to a host
+//that is being decommissioned.
+//Values are resolved as by env.Get, and names whose values cannot be resolved are
+//skipped. An empty needle matches every name.
+func FindValue(env *Env, needle string) []string {
+	result := []string{}
+	for _, name := range env.Names() {
+		value, err := env.Get(name)
+		if err == nil && strings.Contains(value, needle) {
+			result = append(result, name)
+		}
+	}
+	return result
+}
+
+//MaskExcept returns value with every part that is not an occurrence of needle
+//replaced by DumpMask, so that a match can be shown without revealing the rest of
+//a value that may hold a secret, e.g. "user:pw@10.0.0.5:5432" with the needle
+//"10.0.0.5" becomes "********10.0.0.5********".
+//If needle is empty, then value is masked entirely, unless it is empty itself.
+func MaskExcept(value, needle string) string {
+	if needle == "" {
+		if value == "" {
+			return ""
+		}
+		return DumpMask
+	}
+	parts := strings.Split(value, needle)
+	for i, part := range parts {
+		if part != "" {
+			parts[i] = DumpMask
+		}
+	}
+	return strings.Join(parts, needle)
+}
@@ -0,0 +1,49 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFindValue(t *testing.T) {
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"vault": &countingResolver{calls: map[string]int{}}}
+	env, err := s.Env(strings.NewReader(`DB=postgres://u:p@10.0.0.5:5432/db
+CACHE=10.0.0.6
+HOSTS=10.0.0.5,10.0.0.7
+SECRET=vault:10.0.0.5
+BAD=vault:bad
+DB=postgres://u:p@10.0.0.5:5433/db
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := FindValue(env, "10.0.0.5"); !reflect.DeepEqual(names, []string{"DB", "HOSTS", "SECRET"}) {
+		t.Errorf("names = %v", names)
+	}
+	if names := FindValue(env, ""); len(names) != 4 {
+		t.Errorf("names = %v", names)
+	}
+	if names := FindValue(env, "missing"); len(names) != 0 {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestMaskExcept(t *testing.T) {
+	cases := []struct {
+		value, needle, want string
+	}{
+		{"u:p@10.0.0.5:5432", "10.0.0.5", DumpMask + "10.0.0.5" + DumpMask},
+		{"10.0.0.5,10.0.0.5", "10.0.0.5", "10.0.0.5" + DumpMask + "10.0.0.5"},
+		{"10.0.0.5", "10.0.0.5", "10.0.0.5"},
+		{"other", "10.0.0.5", DumpMask},
+		{"value", "", DumpMask},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if result := MaskExcept(c.value, c.needle); result != c.want {
+			t.Errorf("MaskExcept(%q, %q) = %q WANT %q", c.value, c.needle, result, c.want)
+		}
+	}
+}
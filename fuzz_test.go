@@ -0,0 +1,149 @@
+package dotenv
+
+import (
+	"bufio"
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+//fuzzSourcers are the Sourcers that fuzz targets parse inputs with.
+var fuzzSourcers = []*Sourcer{
+	NewDefault(),
+	NewSourcerWith(WithLenient(), WithInterpolate(true)),
+	NewSourcerWith(WithComment(";"), WithQuote("'", nil), WithInterpolateHermetic()),
+	NewSourcerWith(WithComment(""), WithExport("", false), WithPassThrough(true)),
+}
+
+//fuzzSeeds are the seed inputs of the fuzz targets.
+var fuzzSeeds = []string{
+	"",
+	"A=a",
+	"export A=\"a b\" # comment\n\nB=${A}\r\nC",
+	"A = 'a' ; comment",
+	"#dotenv-version: 2\nA=$A%A%$$",
+	"A=\"\\\"#\"#\"",
+	"A=${${}",
+	"\ufeffA=a\x00",
+}
+
+//isLineError determines whether or not err is one of the documented line errors
+//of NameVar for the Sourcers in fuzzSourcers.
+func isLineError(err error) bool {
+	switch err.(type) {
+	case ErrNonVariableLine, ErrInvalidName, ErrInvalidWhitespaceValuePrefix, *ErrValueUnclosedQuote:
+		return true
+	}
+	return err == ErrEmptyLine || err == ErrPassThrough || err == strconv.ErrSyntax
+}
+
+func FuzzSourcer_NameVar(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		for i, s := range fuzzSourcers {
+			name, v, err := s.NameVar(line)
+			if err != nil && !isLineError(err) {
+				t.Errorf("%v: NameVar(%q) error = %#v", i, line, err)
+			}
+			if (err == nil || err == ErrPassThrough) && (name == "" || strings.ContainsAny(name, SpaceTab)) {
+				t.Errorf("%v: NameVar(%q) = %q, %q, %v", i, line, name, v, err)
+			}
+		}
+	})
+}
+
+func FuzzSourcer_NameVars(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for i, s := range fuzzSourcers {
+			_, err := s.NameVars(strings.NewReader(input))
+			var sourcing *ErrSourcing
+			var binary *ErrBinaryInput
+			var encrypted ErrEncrypted
+			switch {
+			case err == nil, errors.As(err, &binary), errors.As(err, &encrypted):
+			case errors.As(err, &sourcing):
+				switch sourcing.LineError.(type) {
+				case ErrUndefinedReference, ErrUnsetPassThrough, ErrLineTooLong:
+					continue
+				}
+				if !isLineError(sourcing.LineError) {
+					t.Errorf("%v: NameVars(%q) line error = %#v", i, input, sourcing.LineError)
+				}
+			default:
+				t.Errorf("%v: NameVars(%q) error = %#v", i, input, err)
+			}
+		}
+	})
+}
+
+func FuzzSourcer_ParseDocument(f *testing.F) {
+	for _, seed := range fuzzSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		for i, s := range fuzzSourcers {
+			d, err := s.ParseDocument(strings.NewReader(input))
+			if err != nil {
+				continue
+			}
+			again, err := s.ParseDocument(strings.NewReader(d.String()))
+			if err != nil || !reflect.DeepEqual(again.Names(), d.Names()) {
+				t.Errorf("%v: ParseDocument(%q) reparsed = %v, %v", i, input, again, err)
+			}
+		}
+	})
+}
+
+func TestSourcer_NameVars_adversarial(t *testing.T) {
+	n := bufio.MaxScanTokenSize / 4
+	unquote := func(s string) (string, error) {
+		if strings.Count(s, "'") != 2 {
+			return "", strconv.ErrSyntax
+		}
+		return s[1 : len(s)-1], nil
+	}
+	tests := []struct {
+		s    *Sourcer
+		line string
+	}{
+		{NewDefault(), "A=\"" + strings.Repeat("\"#", n)},
+		{NewSourcerWith(WithQuote("'", unquote)), "A='" + strings.Repeat("' #", n)},
+		{NewSourcerWith(WithInterpolate(false)), "A=" + strings.Repeat("${", 2*n) + "}"},
+		{NewSourcerWith(WithInterpolate(true)), "A=" + strings.Repeat("%a ", n)},
+		{NewSourcerWith(WithPassThrough(false)), strings.Repeat("A", 3*n)},
+	}
+	for i, test := range tests {
+		start := time.Now()
+		test.s.NameVars(strings.NewReader(test.line))
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("%v: NameVars() took %v", i, elapsed)
+		}
+	}
+}
+
+func TestSourcer_NameVars_lineTooLong(t *testing.T) {
+	input := "A=a\nB=" + strings.Repeat("b", bufio.MaxScanTokenSize)
+	_, err := NewDefault().NameVars(strings.NewReader(input))
+	if !reflect.DeepEqual(err, &ErrSourcing{2, ErrLineTooLong(bufio.MaxScanTokenSize)}) {
+		t.Errorf("err = %v", err)
+	}
+	if err.Error() != "dotenv: line 2 line is longer than 65536 bytes" {
+		t.Errorf("Error() = %v", err)
+	}
+}
+
+func TestSourcer_NameVar_nilUnquote(t *testing.T) {
+	s := NewSourcerWith(WithQuote(`"`, nil))
+	name, v, err := s.NameVar(`A="a" #comment`)
+	if name != "A" || v != `"a"` || err != nil {
+		t.Errorf("NameVar() = %v, %v, %v", name, v, err)
+	}
+}
@@ -0,0 +1,223 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"fmt"
+	"go/format"
+	"go/token"
+	"io"
+	"strings"
+	"unicode"
+)
+
+//DefaultGenerateType is the name of the generated struct type used when a
+//GenerateOptions' Type is empty.
+const DefaultGenerateType = "Config"
+
+//GenerateOptions are the options for GenerateConfig.
+type GenerateOptions struct {
+	//Package is the package name of the generated file. If it is empty, then
+	//"main" is used.
+	Package string
+
+	//Type is the name of the generated struct type. If it is empty, then
+	//DefaultGenerateType is used.
+	Type string
+
+	//Generator is the name of the program included in the generated file's
+	//"Code generated" header. If it is empty, then "dotenv" is used.
+	Generator string
+}
+
+//goInitialisms are the name parts that are written in all capitals in Go
+//identifiers.
+var goInitialisms = map[string]bool{
+	"API": true, "CPU": true, "DB": true, "DNS": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "SSH": true, "TCP": true,
+	"TLS": true, "TTL": true, "UDP": true, "URI": true, "URL": true, "UUID": true,
+}
+
+//GenerateConfig writes Go source to w that declares a struct type with a typed
+//accessor method for each variable in sc, along with functions that read and
+//validate the variables using sc.Resolve().
+//For the default Type Config, the generated declarations are:
+//
+//	type Config struct { ... }
+//	func (c *Config) Name() T
+//	func LoadConfig() (*Config, error)
+//	func LoadConfigFunc(lookup func(name string) (string, bool)) (*Config, error)
+//
+//Accessor names are the variables' names in camel case, e.g. DATABASE_URL becomes
+//DatabaseURL.
+//If two names result in the same accessor name, then an ErrInvalidName is
+//returned for the second.
+//options may be nil.
+func GenerateConfig(w io.Writer, sc *Schema, options *GenerateOptions) error {
+	if options == nil {
+		options = &GenerateOptions{}
+	}
+	pkg, typeName, generator := options.Package, options.Type, options.Generator
+	if pkg == "" {
+		pkg = "main"
+	}
+	if typeName == "" {
+		typeName = DefaultGenerateType
+	}
+	if generator == "" {
+		generator = "dotenv"
+	}
+	receiver := strings.ToLower(typeName[:1])
+	schemaName := lowerFirst(typeName) + "Schema"
+
+	fields := make([][2]string, len(sc.Vars))
+	seen := map[string]bool{}
+	for i, v := range sc.Vars {
+		exported, unexported := goIdentifiers(v.Name)
+		if seen[exported] {
+			return ErrInvalidName(v.Name)
+		}
+		seen[exported] = true
+		fields[i] = [2]string{exported, unexported}
+	}
+
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "// Code generated by %v. DO NOT EDIT.\n\npackage %v\n\n", generator, pkg)
+	buf.WriteString("import (\n\"os\"\n")
+	for _, pkg := range generateImports(sc) {
+		fmt.Fprintf(buf, "%q\n", pkg)
+	}
+	buf.WriteString("\n\"github.com/gogolfing/dotenv\"\n)\n\n")
+
+	fmt.Fprintf(buf, "// %v contains the typed values of the variables described by %v.\n", typeName, schemaName)
+	fmt.Fprintf(buf, "type %v struct {\n", typeName)
+	for i, v := range sc.Vars {
+		fmt.Fprintf(buf, "%v %v\n", fields[i][1], generateGoType(v.Type))
+	}
+	buf.WriteString("}\n\n")
+
+	for i, v := range sc.Vars {
+		fmt.Fprintf(buf, "// %v returns the value of %v.\n", fields[i][0], v.Name)
+		if v.Description != "" {
+			fmt.Fprintf(buf, "//\n// %v\n", v.Description)
+		}
+		fmt.Fprintf(buf, "func (%v *%v) %v() %v {\nreturn %v.%v\n}\n\n",
+			receiver, typeName, fields[i][0], generateGoType(v.Type), receiver, fields[i][1])
+	}
+
+	fmt.Fprintf(buf, "// %v is the Schema %v is generated from.\n", schemaName, typeName)
+	fmt.Fprintf(buf, "var %v = &dotenv.Schema{Vars: []*dotenv.SchemaVar{\n", schemaName)
+	for _, v := range sc.Vars {
+		fmt.Fprintf(buf, "{Name: %q, Type: %q, Required: %v, Default: %q, HasDefault: %v, Pattern: %q, Description: %q, Example: %q, Secret: %v},\n",
+			v.Name, string(v.Type), v.Required, v.Default, v.HasDefault, v.Pattern, v.Description, v.Example, v.Secret)
+	}
+	buf.WriteString("}}\n\n")
+
+	fmt.Fprintf(buf, "// Load%v returns a %v read from the process's environment.\n", typeName, typeName)
+	fmt.Fprintf(buf, "func Load%v() (*%v, error) {\nreturn Load%vFunc(os.LookupEnv)\n}\n\n", typeName, typeName, typeName)
+
+	fmt.Fprintf(buf, "// Load%vFunc returns a %v read with lookup.\n", typeName, typeName)
+	fmt.Fprintf(buf, "// It returns the error from %v.Resolve() if any variable is missing or invalid.\n", schemaName)
+	fmt.Fprintf(buf, "func Load%vFunc(lookup func(name string) (string, bool)) (*%v, error) {\n", typeName, typeName)
+	fmt.Fprintf(buf, "values, err := %v.Resolve(lookup)\nif err != nil {\nreturn nil, err\n}\n", schemaName)
+	fmt.Fprintf(buf, "%v := &%v{}\n", receiver, typeName)
+	for i, v := range sc.Vars {
+		fmt.Fprintf(buf, "if value, ok := values[%q]; ok {\n", v.Name)
+		fmt.Fprintf(buf, generateParse(v.Type)+"\n}\n", receiver+"."+fields[i][1])
+	}
+	fmt.Fprintf(buf, "return %v, nil\n}\n", receiver)
+
+	source, err := format.Source([]byte(buf.String()))
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(source)
+	return err
+}
+
+//generateImports returns the standard library packages, other than os, that the
+//code generated for sc imports.
+func generateImports(sc *Schema) []string {
+	needStrconv, needTime := false, false
+	for _, v := range sc.Vars {
+		switch v.Type {
+		case TypeInt, TypeFloat, TypeBool:
+			needStrconv = true
+		case TypeDuration:
+			needTime = true
+		}
+	}
+	result := []string{}
+	if needStrconv {
+		result = append(result, "strconv")
+	}
+	if needTime {
+		result = append(result, "time")
+	}
+	return result
+}
+
+//generateGoType returns the Go type of values of t.
+func generateGoType(t Type) string {
+	switch t {
+	case TypeInt:
+		return "int"
+	case TypeFloat:
+		return "float64"
+	case TypeBool:
+		return "bool"
+	case TypeDuration:
+		return "time.Duration"
+	}
+	return "string"
+}
+
+//generateParse returns a format string for the statement that assigns the parsed
+//variable value to the field given as its argument.
+//Values have already been checked by Schema.Resolve(), so errors are ignored.
+func generateParse(t Type) string {
+	switch t {
+	case TypeInt:
+		return "%v, _ = strconv.Atoi(value)"
+	case TypeFloat:
+		return "%v, _ = strconv.ParseFloat(value, 64)"
+	case TypeBool:
+		return "%v, _ = strconv.ParseBool(value)"
+	case TypeDuration:
+		return "%v, _ = time.ParseDuration(value)"
+	}
+	return "%v = value"
+}
+
+//goIdentifiers returns the exported and unexported Go identifiers for the
+//variable name.
+func goIdentifiers(name string) (exported, unexported string) {
+	parts := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for i, part := range parts {
+		upper := strings.ToUpper(part)
+		if goInitialisms[upper] {
+			exported += upper
+		} else {
+			exported += strings.ToUpper(part[:1]) + strings.ToLower(part[1:])
+		}
+		if i == 0 {
+			unexported = strings.ToLower(part)
+		} else {
+			unexported += exported[len(exported)-len(part):]
+		}
+	}
+	if exported == "" || !unicode.IsLetter(rune(exported[0])) {
+		exported, unexported = "Var"+exported, "var"+exported
+	}
+	if token.IsKeyword(unexported) {
+		unexported += "_"
+	}
+	return exported, unexported
+}
+
+//lowerFirst returns s with its first byte changed to lower case.
+func lowerFirst(s string) string {
+	return strings.ToLower(s[:1]) + s[1:]
+}
@@ -0,0 +1,136 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGenerateConfig(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{
+		{Name: "HTTP_PORT", Type: TypeInt, Description: "Port to listen on."},
+		{Name: "TYPE", Type: TypeString, Secret: true},
+		{Name: "1_TIMEOUT", Type: TypeDuration},
+	}}
+	out := &strings.Builder{}
+	if err := GenerateConfig(out, sc, &GenerateOptions{Package: "app", Type: "Env", Generator: "gen"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"// Code generated by gen. DO NOT EDIT.\n\npackage app\n",
+		"\t\"strconv\"\n\t\"time\"\n",
+		"type Env struct {\n\thttpPort    int\n\ttype_       string\n\tvar1Timeout time.Duration\n}",
+		"// HTTPPort returns the value of HTTP_PORT.\n//\n// Port to listen on.\nfunc (e *Env) HTTPPort() int {",
+		"func (e *Env) Var1Timeout() time.Duration {",
+		"var envSchema = &dotenv.Schema{",
+		`{Name: "TYPE", Type: "string", Required: false, Default: "", HasDefault: false, Pattern: "", Description: "", Example: "", Secret: true},`,
+		"func LoadEnv() (*Env, error) {",
+		"e.httpPort, _ = strconv.Atoi(value)",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output does not contain %q:\n%v", want, out.String())
+		}
+	}
+}
+
+func TestGenerateConfig_defaults(t *testing.T) {
+	out := &strings.Builder{}
+	if err := GenerateConfig(out, &Schema{Vars: []*SchemaVar{{Name: "A", Type: TypeString}}}, nil); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out.String(), "package main\n") || !strings.Contains(out.String(), "func LoadConfig()") {
+		t.Errorf("out = %v", out.String())
+	}
+	if strings.Contains(out.String(), "strconv") {
+		t.Error("unused imports must not be generated")
+	}
+}
+
+func TestGenerateConfig_duplicateIdentifier(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{{Name: "A_B", Type: TypeString}, {Name: "A__B", Type: TypeString}}}
+	if err := GenerateConfig(&strings.Builder{}, sc, nil); err != ErrInvalidName("A__B") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+//generateMain is the main package that runs the Config generated by
+//TestGenerateConfig_run.
+const generateMain = `package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	c, err := LoadConfigFunc(func(name string) (string, bool) {
+		value, ok := map[string]string{
+			"NAME": "app", "PORT": "8080", "RATIO": "0.5", "DEBUG": "true", "TIMEOUT": "2s",
+			"API_URL": "https://example.com",
+		}[name]
+		return value, ok
+	})
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	fmt.Println(c.Name(), c.Port()+1, c.Ratio()*2, !c.Debug(), c.Timeout().Milliseconds(), c.APIURL(), c.Retries())
+}
+`
+
+func TestGenerateConfig_run(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds a program")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not available")
+	}
+	root, err := filepath.Abs(".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	sc := &Schema{Vars: []*SchemaVar{
+		{Name: "NAME", Type: TypeString, Required: true, Description: "The name."},
+		{Name: "PORT", Type: TypeInt},
+		{Name: "RATIO", Type: TypeFloat},
+		{Name: "DEBUG", Type: TypeBool},
+		{Name: "TIMEOUT", Type: TypeDuration},
+		{Name: "API_URL", Type: TypeURL},
+		{Name: "RETRIES", Type: TypeInt, Default: "3", HasDefault: true},
+	}}
+	config := &strings.Builder{}
+	if err := GenerateConfig(config, sc, nil); err != nil {
+		t.Fatal(err)
+	}
+	goMod := "module app\n\ngo 1.23\n\nrequire github.com/gogolfing/dotenv v0.0.0\n\nreplace github.com/gogolfing/dotenv => " + root + "\n"
+	for name, contents := range map[string]string{"go.mod": goMod, "config.go": config.String(), "main.go": generateMain} {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, args := range [][]string{{"vet", "."}, {"run", "."}} {
+		cmd := exec.Command(goTool, args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off", "GO111MODULE=on")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %v: %v\n%s\n%v", args[0], err, output, config.String())
+		}
+		if args[0] == "run" && string(output) != "app 8081 1 false 2000 https://example.com 3\n" {
+			t.Errorf("output = %q", output)
+		}
+	}
+}
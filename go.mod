@@ -0,0 +1,3 @@
+module github.com/gogolfing/dotenv
+
+go 1.23
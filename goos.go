@@ -0,0 +1,49 @@
+package dotenv
+
+import (
+	"runtime"
+	"strings"
+)
+
+//knownGOOS contains all GOOS values recognized as name suffixes by OSSuffix.
+var knownGOOS = map[string]bool{
+	"aix":       true,
+	"android":   true,
+	"darwin":    true,
+	"dragonfly": true,
+	"freebsd":   true,
+	"hurd":      true,
+	"illumos":   true,
+	"ios":       true,
+	"js":        true,
+	"linux":     true,
+	"nacl":      true,
+	"netbsd":    true,
+	"openbsd":   true,
+	"plan9":     true,
+	"solaris":   true,
+	"wasip1":    true,
+	"windows":   true,
+	"zos":       true,
+}
+
+//targetGOOS returns s.GOOS if it is not empty and runtime.GOOS otherwise.
+func (s *Sourcer) targetGOOS() string {
+	if s.GOOS != "" {
+		return s.GOOS
+	}
+	return runtime.GOOS
+}
+
+//selectOS removes a known GOOS suffix from name.
+//ok is false if the suffix is not the target operating system.
+func (s *Sourcer) selectOS(name string) (result string, ok bool, err error) {
+	dot := strings.LastIndex(name, ".")
+	if dot < 0 || !knownGOOS[name[dot+1:]] {
+		return name, true, nil
+	}
+	if dot == 0 {
+		return "", false, ErrInvalidName(name)
+	}
+	return name[:dot], name[dot+1:] == s.targetGOOS(), nil
+}
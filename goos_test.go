@@ -0,0 +1,69 @@
+package dotenv
+
+import (
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_NameVars_osSuffix(t *testing.T) {
+	source := `PATH=/bin
+PATH.windows=C:\bin
+PATH.linux=/usr/bin
+a.b=c
+`
+	s := NewDefault()
+	s.OSSuffix = true
+	s.GOOS = "windows"
+	nameVars, err := s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Error(err)
+	}
+	want := [][2]string{{"PATH", "/bin"}, {"PATH", `C:\bin`}, {"a.b", "c"}}
+	if !reflect.DeepEqual(nameVars, want) {
+		t.Errorf("nameVars = %v WANT %v", nameVars, want)
+	}
+
+	s.GOOS = "darwin"
+	nameVars, err = s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Error(err)
+	}
+	want = [][2]string{{"PATH", "/bin"}, {"a.b", "c"}}
+	if !reflect.DeepEqual(nameVars, want) {
+		t.Errorf("nameVars = %v WANT %v", nameVars, want)
+	}
+}
+
+func TestSourcer_NameVars_osSuffixDisabled(t *testing.T) {
+	nameVars, err := NewDefault().NameVars(strings.NewReader("PATH.windows=a"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"PATH.windows", "a"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestSourcer_NameVars_osSuffixWithPrefix(t *testing.T) {
+	s := NewDefault()
+	s.OSSuffix = true
+	s.StripPrefix = "APP_"
+	nameVars, err := s.NameVars(strings.NewReader("APP_HOME." + runtime.GOOS + "=a"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"HOME", "a"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestSourcer_NameVars_osSuffixError(t *testing.T) {
+	s := NewDefault()
+	s.OSSuffix = true
+	_, err := s.NameVars(strings.NewReader(".linux=a"))
+	if !reflect.DeepEqual(err, &ErrSourcing{1, ErrInvalidName(".linux")}) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package dotenv
+
+import "strings"
+
+//TokenKind is the kind of a Token.
+type TokenKind string
+
+//The kinds of Tokens.
+const (
+	TokenExport  TokenKind = "export"
+	TokenName    TokenKind = "name"
+	TokenQuote   TokenKind = "quote"
+	TokenValue   TokenKind = "value"
+	TokenComment TokenKind = "comment"
+)
+
+//Token is a part of a line of a Document, as returned from Tokens and At, e.g.
+//for syntax highlighting and hovers in editors.
+type Token struct {
+	//Kind is the kind of the Token.
+	Kind TokenKind
+
+	//Text is the text of the Token as it is written in the line.
+	Text string
+
+	//Start is the Location of the first byte of the Token, and End is the
+	//Location following its last byte.
+	Start, End Location
+
+	//Name is the name of the variable defined on the line of the Token. It is
+	//empty if the line does not define a variable.
+	Name string
+
+	//Value is the value of the definition on the line as parsed, i.e. without
+	//interpolation or resolving.
+	Value string
+
+	//Effective is the definition of Name that takes effect in the Document's
+	//Env, with its resolved value and the file and line it was defined on. It is
+	//set by At, and is nil if the Document has no Env, the Env does not define
+	//Name, or resolving the value failed with EffectiveErr.
+	Effective *Variable
+
+	//EffectiveErr is the error that occurred while resolving Effective.
+	EffectiveErr error
+}
+
+//Tokens returns the tokens of the line (1-based) of d in order, or nil if line is
+//not in d. Whitespace and equal signs are not part of any Token.
+func (d *Document) Tokens(line int) []*Token {
+	if line < 1 || line > len(d.lines) {
+		return nil
+	}
+	l, s := d.lines[line-1], d.sourcer
+	tokens := []*Token{}
+	add := func(kind TokenKind, start, end int) {
+		if 0 <= start && start < end && end <= len(l.raw) {
+			tokens = append(tokens, &Token{
+				Kind:  kind,
+				Text:  l.raw[start:end],
+				Start: Location{line, start + 1},
+				End:   Location{line, end + 1},
+				Name:  l.name,
+				Value: l.value,
+			})
+		}
+	}
+
+	if !l.isVariable {
+		text := strings.TrimLeft(l.raw, SpaceTab)
+		if s.Comment != "" && strings.HasPrefix(text, s.Comment) {
+			add(TokenComment, len(l.raw)-len(text), len(l.raw))
+		}
+		return tokens
+	}
+	if l.exported {
+		start := len(l.raw) - len(strings.TrimLeft(l.raw, SpaceTab))
+		add(TokenExport, start, start+len(s.Export))
+	}
+	//the name and the value are not tokens if their offsets are unknown.
+	if l.hasSpan() {
+		add(TokenName, l.nameOffset, l.nameOffset+len(l.name))
+		valueStart, valueEnd := l.valueOffset, l.valueOffset+len(l.rawValue)
+		if q := len(s.Quote); l.quoted && valueEnd-valueStart >= 2*q {
+			add(TokenQuote, valueStart, valueStart+q)
+			add(TokenValue, valueStart+q, valueEnd-q)
+			add(TokenQuote, valueEnd-q, valueEnd)
+		} else {
+			add(TokenValue, valueStart, valueEnd)
+		}
+	}
+	if l.hasComment {
+		add(TokenComment, len(l.raw)-len(l.comment)-len(s.Comment), len(l.raw))
+	}
+	return tokens
+}
+
+//At returns the Token under the byte column (1-based) of line in d, or nil if
+//there is none, e.g. for the hover of an editor. If d.Env is not nil and the line
+//defines a variable, then the Token's Effective and EffectiveErr are set, so
+//that the hover can show the effective value and which layer defined it.
+//Use RuneLocation or UTF16Location to convert the columns of editors.
+func (d *Document) At(line, column int) *Token {
+	for _, token := range d.Tokens(line) {
+		if token.Start.Column <= column && column < token.End.Column {
+			if name, ok := d.envName(token.Name); ok {
+				token.Effective, token.EffectiveErr = d.Env.Variable(name)
+				if _, missing := token.EffectiveErr.(ErrMissingVariables); missing {
+					token.EffectiveErr = nil
+				}
+			}
+			return token
+		}
+	}
+	return nil
+}
+
+//envName returns the name that the variable defined as name in d has in d.Env.
+//ok is false if d has no Env, name is empty, or the Sourcer ignores name.
+func (d *Document) envName(name string) (string, bool) {
+	if d.Env == nil || name == "" {
+		return "", false
+	}
+	return d.sourcer.sourcedName(name)
+}
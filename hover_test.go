@@ -0,0 +1,121 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Tokens(t *testing.T) {
+	d, err := NewSourcerWith(WithQuotedComments()).ParseDocument(strings.NewReader("  #comment\nexport A=\"a b\" #c\nB=\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		line int
+		want []string
+	}{
+		{1, []string{"comment:#comment:3"}},
+		{2, []string{"export:export:1", "name:A:8", "quote:\":10", "value:a b:11", "quote:\":14", "comment:#c:16"}},
+		{3, []string{"name:B:1"}},
+		{4, nil},
+	}
+	for _, test := range tests {
+		var got []string
+		for _, token := range d.Tokens(test.line) {
+			got = append(got, strings.Join([]string{string(token.Kind), token.Text, strconv.Itoa(token.Start.Column)}, ":"))
+			if token.End.Column-token.Start.Column != len(token.Text) {
+				t.Errorf("token = %v", token)
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Tokens(%v) = %v", test.line, got)
+		}
+	}
+}
+
+func TestDocument_At(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	base, local := filepath.Join(dir, ".env"), filepath.Join(dir, ".env.local")
+	ioutil.WriteFile(base, []byte("A=a\nB=b\n"), 0600)
+	ioutil.WriteFile(local, []byte("A=${B}2\n"), 0600)
+
+	s := NewDefault()
+	s.Interpolate = true
+	file, _ := os.Open(base)
+	d, err := s.ParseDocument(file)
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := d.At(1, 3)
+	if token == nil || token.Kind != TokenValue || token.Name != "A" || token.Value != "a" || token.Effective != nil {
+		t.Fatalf("token = %v", token)
+	}
+	if token := d.At(1, 2); token != nil {
+		t.Errorf("At(1, 2) = %v", token)
+	}
+
+	if d.Env, err = s.EnvFiles(base, local); err != nil {
+		t.Fatal(err)
+	}
+	token = d.At(1, 1)
+	want := &Variable{Name: "A", Value: "b2", File: local, Line: 1}
+	if token == nil || token.Kind != TokenName || !reflect.DeepEqual(token.Effective, want) || token.EffectiveErr != nil {
+		t.Errorf("token = %v", token)
+	}
+}
+
+func TestDocument_At_stripPrefix(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".env")
+	ioutil.WriteFile(path, []byte("APP_X=x\nOTHER=o\n"), 0600)
+
+	s := NewDefault()
+	s.StripPrefix, s.PrefixOnly = "APP_", true
+	d, err := s.ParseDocument(strings.NewReader("APP_X=x\nOTHER=o\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d.Env, err = s.EnvFiles(path); err != nil {
+		t.Fatal(err)
+	}
+	token := d.At(1, 1)
+	want := &Variable{Name: "X", Value: "x", File: path, Line: 1}
+	if token == nil || token.Name != "APP_X" || !reflect.DeepEqual(token.Effective, want) || token.EffectiveErr != nil {
+		t.Errorf("token = %v", token)
+	}
+	if token := d.At(2, 1); token == nil || token.Effective != nil || token.EffectiveErr != nil {
+		t.Errorf("token = %v", token)
+	}
+}
+
+func TestDocument_Tokens_unknownSpan(t *testing.T) {
+	s := NewDefault()
+	s.LineParser = LineParserFunc(func(line string) (string, string, error) {
+		return "PREFIX_" + strings.ToUpper(line[:1]), line[2:], nil
+	})
+	d, err := s.ParseDocument(strings.NewReader("x=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tokens := d.Tokens(1); len(tokens) != 0 {
+		t.Errorf("tokens = %v", tokens)
+	}
+	if token := d.At(1, 1); token != nil {
+		t.Errorf("token = %v", token)
+	}
+}
@@ -0,0 +1,326 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	//DefaultMaxIncludeDepth is the default value of Sourcer.MaxIncludeDepth.
+	DefaultMaxIncludeDepth = 8
+
+	//DefaultMaxIncludeFiles is the default value of Sourcer.MaxIncludeFiles.
+	DefaultMaxIncludeFiles = 64
+)
+
+//IncludeSite is a line of a file.
+type IncludeSite struct {
+	//File is the path of the file.
+	File string
+
+	//Line is the line number (1-based) in File, or 0 if the site is the file
+	//itself.
+	Line int
+}
+
+//String returns site in the form FILE:LINE, or FILE if site.Line is 0.
+func (site IncludeSite) String() string {
+	if site.Line == 0 {
+		return site.File
+	}
+	return fmt.Sprintf("%v:%v", site.File, site.Line)
+}
+
+//ErrInclude is an error that occurs within a file that is included by another
+//file, or while following an include directive.
+type ErrInclude struct {
+	//Chain contains the include directives that lead from the file read by path
+	//to the file the error occurred in, followed by the site of the error.
+	Chain []IncludeSite
+
+	//Err is the error. It is a line error if the error occurred on a line that
+	//is not a directive.
+	Err error
+}
+
+//Error is the error implementation for ErrInclude. It describes the chain of
+//includes, e.g. "dotenv: a.env:3 → common.env:12: ...".
+func (e *ErrInclude) Error() string {
+	sites := make([]string, len(e.Chain))
+	for i, site := range e.Chain {
+		sites[i] = site.String()
+	}
+	return fmt.Sprintf("dotenv: %v: %v", strings.Join(sites, " → "), e.Err)
+}
+
+//Unwrap returns e.Err.
+func (e *ErrInclude) Unwrap() error {
+	return e.Err
+}
+
+//ErrIncludeCycle is an error that occurs when a file includes itself, directly or
+//through other files. Its value is the path of the file.
+type ErrIncludeCycle string
+
+//Error is the error implementation for ErrIncludeCycle.
+func (e ErrIncludeCycle) Error() string {
+	return fmt.Sprintf("include cycle through %q", string(e))
+}
+
+//ErrIncludeDepth is an error that occurs when includes are nested deeper than
+//Sourcer.MaxIncludeDepth. Its value is the limit.
+type ErrIncludeDepth int
+
+//Error is the error implementation for ErrIncludeDepth.
+func (e ErrIncludeDepth) Error() string {
+	return fmt.Sprintf("includes nested more than %d deep", int(e))
+}
+
+//ErrIncludeFiles is an error that occurs when more includes than
+//Sourcer.MaxIncludeFiles are followed. Its value is the limit.
+type ErrIncludeFiles int
+
+//Error is the error implementation for ErrIncludeFiles.
+func (e ErrIncludeFiles) Error() string {
+	return fmt.Sprintf("more than %d files included", int(e))
+}
+
+//IncludeEdge is an include directive.
+type IncludeEdge struct {
+	//From is the site of the directive.
+	From IncludeSite
+
+	//To is the path of the included file.
+	To string
+}
+
+//IncludeGraph is the graph of the files included by a file.
+type IncludeGraph struct {
+	//Files contains the path of every file, without duplicates, in the order of
+	//its first include. The first file is the one the graph was built for.
+	Files []string
+
+	//Edges contains every include directive that is followed, in the order the
+	//directives are followed when sourcing. A file that is included more than
+	//once has its directives followed, and listed, every time.
+	Edges []*IncludeEdge
+}
+
+//IncludeGraph follows the include directives of the file at path, and of the
+//files it includes, and returns the resulting graph. Only directive lines are
+//parsed.
+//If s.Include is empty, then the graph contains only path.
+//If a file cannot be read, or a cycle or a limit of s is found, then the error is
+//returned, as an *ErrInclude unless it occurred in the file at path itself.
+func (s *Sourcer) IncludeGraph(path string) (*IncludeGraph, error) {
+	path = filepath.Clean(path)
+	graph := &IncludeGraph{Files: []string{path}, Edges: []*IncludeEdge{}}
+	seen := map[string]bool{path: true}
+	walk := &includeWalk{
+		sourcer: s,
+		onInclude: func(edge *IncludeEdge) {
+			graph.Edges = append(graph.Edges, edge)
+			if !seen[edge.To] {
+				seen[edge.To] = true
+				graph.Files = append(graph.Files, edge.To)
+			}
+		},
+	}
+	if err := walk.root(path); err != nil {
+		return nil, err
+	}
+	return graph, nil
+}
+
+//sourceIncludes sources the file at path, following includes, setting variables
+//with st, and returns the number of variables set.
+func (s *Sourcer) sourceIncludes(ctx context.Context, path string, st *setter) (count int, err error) {
+	if _, err := s.IncludeGraph(path); err != nil {
+		return 0, err
+	}
+	walk := &includeWalk{
+		sourcer: s,
+		ctx:     ctx,
+		defined: map[string]string{},
+		visit: func(variable *Variable) error {
+			ok, err := st.setenv(variable.Name, variable.Value)
+			if ok {
+				count++
+			}
+			return err
+		},
+	}
+	return count, walk.root(path)
+}
+
+//variablesIncludes returns the variables of the file at path, following
+//includes.
+func (s *Sourcer) variablesIncludes(path string) ([]*Variable, error) {
+	result := []*Variable{}
+	walk := &includeWalk{
+		sourcer: s,
+		ctx:     context.Background(),
+		defined: map[string]string{},
+		visit: func(variable *Variable) error {
+			result = append(result, variable)
+			return nil
+		},
+	}
+	if err := walk.root(path); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//includeWalk reads a file and the files it includes, depth first.
+type includeWalk struct {
+	sourcer *Sourcer
+	ctx     context.Context
+
+	//defined contains the variables visited so far, as in visitLine.
+	defined map[string]string
+
+	//visit is called with every variable. If it is nil, then only directive
+	//lines are parsed.
+	visit func(variable *Variable) error
+
+	//onInclude is called with every directive before it is followed, and may be
+	//nil.
+	onInclude func(edge *IncludeEdge)
+
+	//includes is the number of includes followed so far.
+	includes int
+}
+
+//root reads the file at path, which is not included by another file.
+//path is cleaned like the paths of included files, so that a cycle back to it is
+//found when it is first included again.
+func (w *includeWalk) root(path string) error {
+	return w.file(filepath.Clean(path), nil)
+}
+
+//file reads the file at path, which is included through the directives in
+//chain.
+func (w *includeWalk) file(path string, chain []IncludeSite) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return includeError(chain, IncludeSite{path, 0}, err)
+	}
+	defer file.Close()
+	s, in, dialectErr := w.sourcer.dialectFor(file)
+	if dialectErr != nil {
+		return includeError(chain, IncludeSite{path, 1}, dialectErr.LineError)
+	}
+
+	//lineErr is the error returned for a line, which is already wrapped.
+	var lineErr error
+	err = scanLines(in, func(lineNumber int, line string) error {
+		site := IncludeSite{path, lineNumber}
+		target, ok, err := s.includeTarget(line, path)
+		if err != nil {
+			lineErr = includeError(chain, site, err)
+			return lineErr
+		}
+		if ok {
+			lineErr = w.include(target, append(chain[:len(chain):len(chain)], site))
+			return lineErr
+		}
+		if w.visit == nil {
+			return nil
+		}
+		err = s.visitLine(w.ctx, lineNumber, line, w.defined, func(variable *Variable) error {
+			variable.File = path
+			return w.visit(variable)
+		})
+		if err != nil {
+			lineErr = includeError(chain, site, err)
+		}
+		return lineErr
+	})
+	if err != nil && err != lineErr {
+		return includeError(chain, IncludeSite{path, 0}, err)
+	}
+	return err
+}
+
+//include follows the directive at the last site of chain, which includes the
+//file at path, after checking the limits of the Sourcer.
+func (w *includeWalk) include(path string, chain []IncludeSite) error {
+	s := w.sourcer
+	maxDepth, maxFiles := s.MaxIncludeDepth, s.MaxIncludeFiles
+	if maxDepth == 0 {
+		maxDepth = DefaultMaxIncludeDepth
+	}
+	if maxFiles == 0 {
+		maxFiles = DefaultMaxIncludeFiles
+	}
+
+	for _, site := range chain {
+		if site.File == path {
+			return &ErrInclude{chain, ErrIncludeCycle(path)}
+		}
+	}
+	if len(chain) > maxDepth {
+		return &ErrInclude{chain, ErrIncludeDepth(maxDepth)}
+	}
+	if w.includes++; w.includes > maxFiles {
+		return &ErrInclude{chain, ErrIncludeFiles(maxFiles)}
+	}
+	if w.onInclude != nil {
+		w.onInclude(&IncludeEdge{chain[len(chain)-1], path})
+	}
+	return w.file(path, chain)
+}
+
+//includeError returns err, which occurred at site, as the error of reading a file
+//included through chain.
+//Errors in the file that is read by path are returned as an *ErrSourcing for line
+//errors, and unchanged otherwise.
+func includeError(chain []IncludeSite, site IncludeSite, err error) error {
+	if len(chain) == 0 {
+		if site.Line == 0 {
+			return err
+		}
+		return &ErrSourcing{site.Line, err}
+	}
+	return &ErrInclude{append(chain[:len(chain):len(chain)], site), err}
+}
+
+//includeTarget returns the cleaned path of the file included by line, which is in
+//the file at from.
+//ok is false if line is not an include directive. If the directive does not
+//contain a path, then an ErrNonVariableLine is returned.
+func (s *Sourcer) includeTarget(line, from string) (path string, ok bool, err error) {
+	if s.Include == "" {
+		return "", false, nil
+	}
+	text := strings.TrimLeft(line, SpaceTab)
+	if !strings.HasPrefix(text, s.Include) {
+		return "", false, nil
+	}
+	text = text[len(s.Include):]
+	if text == "" || !strings.ContainsRune(SpaceTab, rune(text[0])) {
+		return "", false, nil
+	}
+	text = strings.Trim(text, SpaceTab)
+
+	quoted := s.Quote != "" && len(text) >= 2*len(s.Quote) &&
+		strings.HasPrefix(text, s.Quote) && strings.HasSuffix(text, s.Quote)
+	if quoted && s.Unquote != nil {
+		if text, err = s.Unquote(text); err != nil {
+			return "", true, err
+		}
+	} else if i := strings.Index(text, s.Comment); i >= 0 && s.Comment != "" {
+		text = strings.TrimRight(text[:i], SpaceTab)
+	}
+	if text == "" {
+		return "", true, ErrNonVariableLine(line)
+	}
+	if !filepath.IsAbs(text) {
+		text = filepath.Join(filepath.Dir(from), text)
+	}
+	return filepath.Clean(text), true, nil
+}
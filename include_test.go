@@ -0,0 +1,179 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func newIncludeSourcer() *Sourcer {
+	s := NewDefault()
+	s.Include = "include"
+	s.Interpolate = true
+	return s
+}
+
+func TestErrInclude_Error(t *testing.T) {
+	err := &ErrInclude{[]IncludeSite{{"a.env", 3}, {"common.env", 12}}, ErrNonVariableLine("x")}
+	if err.Error() != `dotenv: a.env:3 → common.env:12: line does not contain a variable definition "x"` {
+		t.Errorf("err = %v", err)
+	}
+	if !errors.Is(err, ErrNonVariableLine("x")) {
+		t.Fail()
+	}
+	if (IncludeSite{"a.env", 0}).String() != "a.env" {
+		t.Fail()
+	}
+}
+
+func TestSourcer_VariablesFile_include(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.env":             "A=a\ninclude common/common.env # shared\nB=${C}b\n",
+		"common/common.env": "C=c\n  include \"more.env\"\n",
+		"common/more.env":   "D=${A}d",
+	})
+
+	variables, err := newIncludeSourcer().VariablesFile(filepath.Join(dir, "a.env"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := []string{}
+	for _, v := range variables {
+		rel, _ := filepath.Rel(dir, v.File)
+		got = append(got, v.Name+"="+v.Value+" "+(IncludeSite{rel, v.Line}).String())
+	}
+	want := []string{"A=a a.env:1", "C=c common/common.env:1", "D=ad common/more.env:1", "B=cb a.env:3"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("variables = %v WANT %v", got, want)
+	}
+
+	variables, err = NewDefault().VariablesFile(filepath.Join(dir, "common/more.env"))
+	if err != nil || len(variables) != 1 {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+	if _, err := NewDefault().VariablesFile(filepath.Join(dir, "a.env")); !reflect.DeepEqual(err, &ErrSourcing{2, ErrNonVariableLine("include common/common.env # shared")}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_IncludeGraph(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"a.env": "include b.env\ninclude c.env\nBAD LINE\n",
+		"b.env": "include c.env\n",
+		"c.env": "C=c\n",
+	})
+	a, b, c := filepath.Join(dir, "a.env"), filepath.Join(dir, "b.env"), filepath.Join(dir, "c.env")
+
+	graph, err := newIncludeSourcer().IncludeGraph(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &IncludeGraph{
+		Files: []string{a, b, c},
+		Edges: []*IncludeEdge{{IncludeSite{a, 1}, b}, {IncludeSite{b, 1}, c}, {IncludeSite{a, 2}, c}},
+	}
+	if !reflect.DeepEqual(graph, want) {
+		t.Errorf("graph = %v WANT %v", graph, want)
+	}
+
+	graph, err = NewDefault().IncludeGraph(a)
+	if err != nil || !reflect.DeepEqual(graph.Files, []string{a}) || len(graph.Edges) != 0 {
+		t.Errorf("graph, err = %v, %v", graph, err)
+	}
+}
+
+func TestSourcer_IncludeGraph_errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"cycle.env":   "A=a\ninclude cycle2.env\n",
+		"cycle2.env":  "include cycle.env\n",
+		"deep.env":    "include deep1.env\n",
+		"deep1.env":   "include deep2.env\n",
+		"deep2.env":   "D=d\n",
+		"missing.env": "include nope.env\n",
+		"empty.env":   "include   # nothing\n",
+		"wide.env":    "include deep2.env\ninclude deep2.env\ninclude deep2.env\n",
+		"binary.env":  "include bin.env\n",
+		"bin.env":     "\x00\x01",
+		"self.env":    "A=a\ninclude ./self.env\n",
+	})
+	path := func(name string) string {
+		return filepath.Join(dir, name)
+	}
+
+	s := newIncludeSourcer()
+	s.MaxIncludeDepth = 1
+	s.MaxIncludeFiles = 2
+	cases := map[string]error{
+		"cycle.env": &ErrInclude{
+			[]IncludeSite{{path("cycle.env"), 2}, {path("cycle2.env"), 1}},
+			ErrIncludeCycle(path("cycle.env")),
+		},
+		"deep.env": &ErrInclude{
+			[]IncludeSite{{path("deep.env"), 1}, {path("deep1.env"), 1}},
+			ErrIncludeDepth(1),
+		},
+		"wide.env": &ErrInclude{
+			[]IncludeSite{{path("wide.env"), 3}},
+			ErrIncludeFiles(2),
+		},
+		"empty.env": &ErrSourcing{1, ErrNonVariableLine("include   # nothing")},
+		"binary.env": &ErrInclude{
+			[]IncludeSite{{path("binary.env"), 1}, {path("bin.env"), 0}},
+			&ErrBinaryInput{path("bin.env")},
+		},
+	}
+	for name, want := range cases {
+		if _, err := s.IncludeGraph(path(name)); !reflect.DeepEqual(err, want) {
+			t.Errorf("IncludeGraph(%v) err = %v WANT %v", name, err, want)
+		}
+	}
+
+	//the cycle is found at the first include of the root, whose path is not
+	//clean.
+	unclean := dir + string(filepath.Separator) + "." + string(filepath.Separator) + "self.env"
+	want := &ErrInclude{[]IncludeSite{{path("self.env"), 2}}, ErrIncludeCycle(path("self.env"))}
+	if _, err := s.IncludeGraph(unclean); !reflect.DeepEqual(err, want) {
+		t.Errorf("IncludeGraph(%v) err = %v WANT %v", unclean, err, want)
+	}
+	if _, err := s.VariablesFile(unclean); !reflect.DeepEqual(err, want) {
+		t.Errorf("VariablesFile(%v) err = %v WANT %v", unclean, err, want)
+	}
+
+	_, err := s.IncludeGraph(path("missing.env"))
+	if include, ok := err.(*ErrInclude); !ok || !os.IsNotExist(include.Err) || len(include.Chain) != 2 {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := s.IncludeGraph(path("nope.env")); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_SourceFile_include(t *testing.T) {
+	skipUnlessSetenv(t)
+	dir := writeFiles(t, map[string]string{
+		"a.env":     "GOGOLFING_DOTENV_INCLUDE_A=a\ninclude b.env\n",
+		"b.env":     "GOGOLFING_DOTENV_INCLUDE_B=${GOGOLFING_DOTENV_INCLUDE_A}b\n",
+		"bad.env":   "GOGOLFING_DOTENV_INCLUDE_C=c\ninclude cycle.env\n",
+		"cycle.env": "include bad.env\n",
+	})
+	defer os.Unsetenv("GOGOLFING_DOTENV_INCLUDE_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_INCLUDE_B")
+	defer os.Unsetenv("GOGOLFING_DOTENV_INCLUDE_C")
+
+	s := newIncludeSourcer()
+	if err := s.SourceFile(filepath.Join(dir, "a.env")); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("GOGOLFING_DOTENV_INCLUDE_B") != "ab" {
+		t.Errorf("B = %q", os.Getenv("GOGOLFING_DOTENV_INCLUDE_B"))
+	}
+
+	if err := s.SourceFile(filepath.Join(dir, "bad.env")); err == nil {
+		t.Fail()
+	}
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_INCLUDE_C"); ok {
+		t.Error("SourceFile() must not set variables if includes are invalid")
+	}
+}
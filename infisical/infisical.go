@@ -0,0 +1,123 @@
+//Package infisical resolves secrets stored in Infisical, https://infisical.com,
+//and imports the secrets of an Infisical environment into a dotenv.Env.
+package infisical
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/gogolfing/dotenv"
+)
+
+//Scheme is the resolver scheme of Infisical references, e.g. "infisical:DB_PASSWORD".
+const Scheme = "infisical"
+
+//DefaultBaseURL is the base URL of the Infisical API.
+const DefaultBaseURL = "https://app.infisical.com"
+
+//Client fetches the secrets of a single path of an Infisical environment.
+//It is a dotenv.BatchResolver whose references are secret names, and every call
+//fetches all secrets of the path with one request.
+type Client struct {
+	//Token is the Infisical access token, e.g. that of a machine identity.
+	Token string
+
+	//WorkspaceID and Environment identify the project and the environment, e.g.
+	//"dev" or "prod", whose secrets are fetched.
+	WorkspaceID string
+	Environment string
+
+	//SecretPath is the folder whose secrets are fetched. An empty SecretPath
+	//means the root folder "/".
+	SecretPath string
+
+	//BaseURL is the base URL of the API. An empty BaseURL means DefaultBaseURL.
+	BaseURL string
+
+	//HTTPClient sends requests. A nil HTTPClient means http.DefaultClient.
+	HTTPClient *http.Client
+}
+
+//Secrets returns all secrets of the path by name.
+//Failed requests and server errors return a *dotenv.ErrTransient, and all other
+//error responses a *dotenv.ErrPermanent, so that c can be wrapped with
+//dotenv.WithRetry.
+func (c *Client) Secrets(ctx context.Context) (map[string]string, error) {
+	baseURL, httpClient := c.BaseURL, c.HTTPClient
+	if baseURL == "" {
+		baseURL = DefaultBaseURL
+	}
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	secretPath := c.SecretPath
+	if secretPath == "" {
+		secretPath = "/"
+	}
+	query := url.Values{
+		"workspaceId": {c.WorkspaceID},
+		"environment": {c.Environment},
+		"secretPath":  {secretPath},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+"/api/v3/secrets/raw?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, &dotenv.ErrTransient{Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		err := fmt.Errorf("infisical: %v: %s", resp.Status, body)
+		if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+			return nil, &dotenv.ErrTransient{Err: err}
+		}
+		return nil, &dotenv.ErrPermanent{Err: err}
+	}
+	body := struct {
+		Secrets []struct {
+			SecretKey   string `json:"secretKey"`
+			SecretValue string `json:"secretValue"`
+		} `json:"secrets"`
+	}{}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("infisical: decoding secrets: %v", err)
+	}
+	secrets := map[string]string{}
+	for _, secret := range body.Secrets {
+		secrets[secret.SecretKey] = secret.SecretValue
+	}
+	return secrets, nil
+}
+
+//Resolve implements dotenv.Resolver by returning the secret named ref.
+//If the path has no such secret, then a *dotenv.ErrPermanent is returned.
+func (c *Client) Resolve(ctx context.Context, ref string) (string, error) {
+	return c.resolver().Resolve(ctx, ref)
+}
+
+//ResolveBatch implements dotenv.BatchResolver by returning the secrets named
+//refs that exist in the path.
+func (c *Client) ResolveBatch(ctx context.Context, refs []string) (map[string]string, error) {
+	return c.resolver().ResolveBatch(ctx, refs)
+}
+
+//Env fetches all secrets of the path and returns them as a dotenv.Env parsed
+//by s, as with dotenv.FetchResolver.Env.
+func (c *Client) Env(ctx context.Context, s *dotenv.Sourcer) (*dotenv.Env, error) {
+	return c.resolver().Env(ctx, s)
+}
+
+//resolver returns the dotenv.FetchResolver that fetches with c.Secrets.
+func (c *Client) resolver() *dotenv.FetchResolver {
+	return &dotenv.FetchResolver{Scheme: Scheme, Fetch: c.Secrets}
+}
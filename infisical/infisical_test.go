@@ -0,0 +1,61 @@
+package infisical
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gogolfing/dotenv"
+)
+
+func newServer(t *testing.T, status int, body string) (*Client, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.URL.Path != "/api/v3/secrets/raw" || r.Header.Get("Authorization") != "Bearer st.token" {
+			t.Errorf("request = %v %v", r.URL, r.Header)
+		}
+		if q := r.URL.Query(); q.Get("workspaceId") != "app" || q.Get("environment") != "dev" || q.Get("secretPath") != "/" {
+			t.Errorf("query = %v", q)
+		}
+		w.WriteHeader(status)
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return &Client{Token: "st.token", WorkspaceID: "app", Environment: "dev", BaseURL: server.URL}, &requests
+}
+
+func TestClient_Secrets(t *testing.T) {
+	c, requests := newServer(t, http.StatusOK, `{"secrets": [{"secretKey": "A", "secretValue": "a"}, {"secretKey": "B", "secretValue": "b"}]}`)
+	secrets, err := c.Secrets(context.Background())
+	if err != nil || !reflect.DeepEqual(secrets, map[string]string{"A": "a", "B": "b"}) {
+		t.Errorf("secrets, err = %v, %v", secrets, err)
+	}
+	if value, err := c.Resolve(context.Background(), "B"); value != "b" || err != nil {
+		t.Errorf("value, err = %q, %v", value, err)
+	}
+	if *requests != 2 {
+		t.Errorf("requests = %v", *requests)
+	}
+}
+
+func TestClient_Secrets_errors(t *testing.T) {
+	c, _ := newServer(t, http.StatusServiceUnavailable, "down")
+	if _, err := c.Secrets(context.Background()); !dotenv.IsTransient(err) {
+		t.Errorf("err = %v", err)
+	}
+
+	c, _ = newServer(t, http.StatusUnauthorized, "bad token")
+	var permanent *dotenv.ErrPermanent
+	if _, err := c.Secrets(context.Background()); !errors.As(err, &permanent) || err.Error() != "permanent failure: infisical: 401 Unauthorized: bad token" {
+		t.Errorf("err = %v", err)
+	}
+
+	c, _ = newServer(t, http.StatusOK, "[")
+	if _, err := c.Secrets(context.Background()); err == nil {
+		t.Fail()
+	}
+}
@@ -0,0 +1,143 @@
+package dotenv
+
+import (
+	"os"
+	"strings"
+)
+
+//reference is a single variable reference or escape sequence found in a value.
+type reference struct {
+	//start and end are the byte offsets of the entire reference in the value.
+	start, end int
+
+	//name is the referenced variable name. It is empty for escape sequences.
+	name string
+
+	//literal is the text an escape sequence expands to.
+	literal string
+}
+
+//references returns all references and escape sequences in v in order.
+//$NAME, ${NAME}, and $$ are always recognized. %NAME% and %% are recognized if
+//percent is true.
+func references(v string, percent bool) []*reference {
+	result := []*reference{}
+	for i := 0; i < len(v); i++ {
+		var ref *reference
+		switch v[i] {
+		case '$':
+			ref = dollarReference(v, i)
+		case '%':
+			if percent {
+				ref = percentReference(v, i)
+			}
+		}
+		if ref != nil {
+			result = append(result, ref)
+			i = ref.end - 1
+		}
+	}
+	return result
+}
+
+//dollarReference returns the reference starting with the dollar sign at v[i],
+//or nil if there is none.
+func dollarReference(v string, i int) *reference {
+	rest := v[i+1:]
+	switch {
+	case strings.HasPrefix(rest, "$"):
+		return &reference{start: i, end: i + 2, literal: "$"}
+
+	case strings.HasPrefix(rest, "{"):
+		//stop at the first byte that cannot be in a braced name, so that scanning
+		//many unclosed braces is linear.
+		closing := strings.IndexAny(rest[1:], "}"+SpaceTab+"${") + 1
+		if closing <= 1 || rest[closing] != '}' {
+			return nil
+		}
+		return &reference{start: i, end: i + 2 + closing, name: rest[1:closing]}
+	}
+
+	length := 0
+	for length < len(rest) && isNameByte(rest[length], length == 0) {
+		length++
+	}
+	if length == 0 {
+		return nil
+	}
+	return &reference{start: i, end: i + 1 + length, name: rest[:length]}
+}
+
+//percentReference returns the reference starting with the percent sign at v[i],
+//or nil if there is none.
+func percentReference(v string, i int) *reference {
+	rest := v[i+1:]
+	closing := strings.Index(rest, "%")
+	if closing < 0 {
+		return nil
+	}
+	if closing == 0 {
+		return &reference{start: i, end: i + 2, literal: "%"}
+	}
+	name := rest[:closing]
+	if strings.ContainsAny(name, SpaceTab) {
+		return nil
+	}
+	return &reference{start: i, end: i + 2 + closing, name: name}
+}
+
+//isNameByte determines whether or not b can appear in an unbraced $NAME reference.
+func isNameByte(b byte, first bool) bool {
+	return b == '_' ||
+		('a' <= b && b <= 'z') ||
+		('A' <= b && b <= 'Z') ||
+		('0' <= b && b <= '9' && !first)
+}
+
+//interpolate returns v with all references expanded.
+//Names are looked up in defined and then with s.Lookup, unless
+//s.InterpolateHermetic is true, in which case an ErrUndefinedReference is
+//returned for names that are not in defined.
+func (s *Sourcer) interpolate(v string, defined map[string]string) (string, error) {
+	return s.expand(v, func(name string) (string, bool, error) {
+		value, ok := defined[name]
+		return value, ok, nil
+	})
+}
+
+//expand is the same as interpolate except that names are looked up with the
+//function defined, which may return an error that is then returned from expand.
+func (s *Sourcer) expand(v string, defined func(name string) (string, bool, error)) (string, error) {
+	refs := references(v, s.InterpolatePercent)
+	if len(refs) == 0 {
+		return v, nil
+	}
+	lookup := s.Lookup
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+
+	result := make([]byte, 0, len(v))
+	last := 0
+	for _, ref := range refs {
+		result = append(result, v[last:ref.start]...)
+		if ref.name == "" {
+			result = append(result, ref.literal...)
+			last = ref.end
+			continue
+		}
+		value, ok, err := defined(s.alias(ref.name))
+		if err != nil {
+			return "", err
+		}
+		if !ok && s.InterpolateHermetic {
+			return "", ErrUndefinedReference(ref.name)
+		}
+		if !ok {
+			value, _ = lookup(ref.name)
+		}
+		result = append(result, value...)
+		last = ref.end
+	}
+	return string(append(result, v[last:]...)), nil
+}
@@ -0,0 +1,112 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReferences(t *testing.T) {
+	cases := []struct {
+		v       string
+		percent bool
+		refs    []*reference
+	}{
+		{"", false, []*reference{}},
+		{"abc", false, []*reference{}},
+		{"$", false, []*reference{}},
+		{"a$ b", false, []*reference{}},
+		{"$1", false, []*reference{}},
+		{"${", false, []*reference{}},
+		{"${}", false, []*reference{}},
+		{"${a b}", false, []*reference{}},
+		{"$$", false, []*reference{{0, 2, "", "$"}}},
+		{"$$a", false, []*reference{{0, 2, "", "$"}}},
+		{"$a", false, []*reference{{0, 2, "a", ""}}},
+		{"x$A_1-y", false, []*reference{{1, 5, "A_1", ""}}},
+		{"${a.b}c", false, []*reference{{0, 6, "a.b", ""}}},
+		{"$a${b}", false, []*reference{{0, 2, "a", ""}, {2, 6, "b", ""}}},
+		{"%a%", false, []*reference{}},
+		{"%a%", true, []*reference{{0, 3, "a", ""}}},
+		{"%%a%", true, []*reference{{0, 2, "", "%"}}},
+		{"100%", true, []*reference{}},
+		{"% a%", true, []*reference{}},
+		{"%a%$b", true, []*reference{{0, 3, "a", ""}, {3, 5, "b", ""}}},
+	}
+	for _, c := range cases {
+		refs := references(c.v, c.percent)
+		if !reflect.DeepEqual(refs, c.refs) {
+			t.Errorf("references(%q, %v) = %v WANT %v", c.v, c.percent, refs, c.refs)
+		}
+	}
+}
+
+func TestSourcer_NameVars_interpolate(t *testing.T) {
+	os.Setenv("GOGOLFING_DOTENV_INTERPOLATE", "env")
+	defer os.Unsetenv("GOGOLFING_DOTENV_INTERPOLATE")
+	os.Unsetenv("GOGOLFING_DOTENV_UNDEFINED")
+
+	source := `a=1
+b=${a}2
+c="$b $GOGOLFING_DOTENV_INTERPOLATE"
+d=$GOGOLFING_DOTENV_UNDEFINED.$$a
+e=%a%%%
+`
+	s := NewDefault()
+	s.Interpolate = true
+	nameVars, err := s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Error(err)
+	}
+	want := [][2]string{{"a", "1"}, {"b", "12"}, {"c", "12 env"}, {"d", ".$a"}, {"e", "%a%%%"}}
+	if !reflect.DeepEqual(nameVars, want) {
+		t.Errorf("nameVars = %v WANT %v", nameVars, want)
+	}
+
+	s.InterpolatePercent = true
+	nameVars, err = s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Error(err)
+	}
+	want[4] = [2]string{"e", "1%"}
+	if !reflect.DeepEqual(nameVars, want) {
+		t.Errorf("nameVars = %v WANT %v", nameVars, want)
+	}
+}
+
+func TestSourcer_NameVars_interpolateDisabled(t *testing.T) {
+	s := NewDefault()
+	s.InterpolatePercent = true
+	nameVars, err := s.NameVars(strings.NewReader("a=1\nb=$a%a%"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"a", "1"}, {"b", "$a%a%"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestSourcer_NameVars_interpolateHermetic(t *testing.T) {
+	os.Setenv("GOGOLFING_DOTENV_HERMETIC", "env")
+	defer os.Unsetenv("GOGOLFING_DOTENV_HERMETIC")
+
+	s := NewDefault()
+	s.Interpolate = true
+	s.InterpolateHermetic = true
+	s.Lookup = LookupMap(map[string]string{"b": "map"})
+	nameVars, err := s.NameVars(strings.NewReader("a=1\nb=${a}$$2"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"a", "1"}, {"b", "1$2"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+
+	for _, name := range []string{"GOGOLFING_DOTENV_HERMETIC", "b"} {
+		_, err = s.NameVars(strings.NewReader("a=1\nb=${a}${" + name + "}"))
+		if !reflect.DeepEqual(err, &ErrSourcing{2, ErrUndefinedReference(name)}) {
+			t.Errorf("err = %v", err)
+		}
+	}
+}
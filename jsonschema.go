@@ -0,0 +1,86 @@
+package dotenv
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//JSONSchemaDraft is the JSON Schema dialect written by GenerateJSONSchema.
+const JSONSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+//jsonTypePatterns are the JSON Schema patterns that string values of each Type
+//must match. They approximate the values accepted by Type.Check().
+var jsonTypePatterns = map[Type]string{
+	TypeInt:      `^[+-]?[0-9]+$`,
+	TypeFloat:    `^[+-]?([0-9]+(\.[0-9]*)?|\.[0-9]+)([eE][+-]?[0-9]+)?$`,
+	TypeBool:     `^(1|t|T|TRUE|true|True|0|f|F|FALSE|false|False)$`,
+	TypeDuration: `^[+-]?(0|(([0-9]+(\.[0-9]*)?|\.[0-9]+)(ns|us|µs|μs|ms|s|m|h))+)$`,
+	TypeURL:      `^[A-Za-z][A-Za-z0-9+.-]*:`,
+}
+
+//jsonSchema is the JSON Schema document written by GenerateJSONSchema.
+type jsonSchema struct {
+	Schema     string                         `json:"$schema"`
+	Type       string                         `json:"type"`
+	Properties map[string]*jsonSchemaProperty `json:"properties"`
+	Required   []string                       `json:"required"`
+}
+
+//jsonSchemaProperty is the subschema of a single variable in a jsonSchema.
+type jsonSchemaProperty struct {
+	Type        string                `json:"type"`
+	Description string                `json:"description,omitempty"`
+	Pattern     string                `json:"pattern,omitempty"`
+	AllOf       []*jsonSchemaProperty `json:"allOf,omitempty"`
+	Default     *string               `json:"default,omitempty"`
+	Examples    []string              `json:"examples,omitempty"`
+}
+
+//GenerateJSONSchema writes a JSON Schema to w that describes the variables in sc
+//as an object whose members are the variables' names.
+//Values in an environment are strings, so every property has type string. The
+//Type of each variable is described with a pattern that approximates the values
+//accepted by Type.Check(), which is combined with the variable's own Pattern using
+//allOf. Defaults, descriptions, and examples are included when present.
+func GenerateJSONSchema(w io.Writer, sc *Schema) error {
+	doc := &jsonSchema{
+		Schema:     JSONSchemaDraft,
+		Type:       "object",
+		Properties: map[string]*jsonSchemaProperty{},
+		Required:   []string{},
+	}
+	for _, v := range sc.Vars {
+		property := &jsonSchemaProperty{Type: "string", Description: v.Description}
+		patterns := []string{}
+		if pattern, ok := jsonTypePatterns[v.Type]; ok {
+			patterns = append(patterns, pattern)
+		}
+		if v.Pattern != "" {
+			patterns = append(patterns, "^(?:"+v.Pattern+")$")
+		}
+		switch len(patterns) {
+		case 1:
+			property.Pattern = patterns[0]
+		case 2:
+			property.AllOf = []*jsonSchemaProperty{
+				{Type: "string", Pattern: patterns[0]},
+				{Type: "string", Pattern: patterns[1]},
+			}
+		}
+		if v.HasDefault {
+			property.Default = &v.Default
+		}
+		if v.Example != "" {
+			property.Examples = []string{v.Example}
+		}
+		doc.Properties[v.Name] = property
+		if v.Required {
+			doc.Required = append(doc.Required, v.Name)
+		}
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,75 @@
+package dotenv
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestGenerateJSONSchema(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{
+		{Name: "PORT", Type: TypeInt, Default: "8080", HasDefault: true, Description: "Port.", Example: "3000"},
+		{Name: "NAME", Type: TypeString, Required: true, Pattern: "[a-z]+"},
+		{Name: "RETRIES", Type: TypeInt, Pattern: "[0-5]"},
+		{Name: "EMPTY", Type: TypeString, Default: "", HasDefault: true},
+	}}
+	out := &strings.Builder{}
+	if err := GenerateJSONSchema(out, sc); err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "$schema": "https://json-schema.org/draft/2020-12/schema",
+  "type": "object",
+  "properties": {
+    "EMPTY": {
+      "type": "string",
+      "default": ""
+    },
+    "NAME": {
+      "type": "string",
+      "pattern": "^(?:[a-z]+)$"
+    },
+    "PORT": {
+      "type": "string",
+      "description": "Port.",
+      "pattern": "^[+-]?[0-9]+$",
+      "default": "8080",
+      "examples": [
+        "3000"
+      ]
+    },
+    "RETRIES": {
+      "type": "string",
+      "allOf": [
+        {
+          "type": "string",
+          "pattern": "^[+-]?[0-9]+$"
+        },
+        {
+          "type": "string",
+          "pattern": "^(?:[0-5])$"
+        }
+      ]
+    }
+  },
+  "required": [
+    "NAME"
+  ]
+}
+`
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+}
+
+func TestJSONTypePatterns(t *testing.T) {
+	values := []string{"0", "-12", "+3", "1.5", ".5", "1e3", "true", "F", "5s", "1h30m", "-1.5ms", "0", "yes", "1.2.3", "5"}
+	for typ, pattern := range jsonTypePatterns {
+		re := regexp.MustCompile(pattern)
+		for _, value := range values {
+			if re.MatchString(value) != (typ.Check(value) == nil) {
+				t.Errorf("pattern for %v and Check() disagree on %q", typ, value)
+			}
+		}
+	}
+}
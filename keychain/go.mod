@@ -0,0 +1,14 @@
+module github.com/gogolfing/dotenv/keychain
+
+go 1.23
+
+require (
+	github.com/gogolfing/dotenv v0.0.0-20261016144115-b9cea7ec471b
+	github.com/zalando/go-keyring v0.2.5
+)
+
+require (
+	github.com/alessio/shellescape v1.4.1 // indirect
+	github.com/danieljoos/wincred v1.2.0 // indirect
+	github.com/godbus/dbus/v5 v5.1.0 // indirect
+)
@@ -0,0 +1,126 @@
+//Package keychain stores secret variables in the operating system's credential
+//store, i.e. the macOS Keychain, the Secret Service (libsecret) on Linux, or the
+//Windows Credential Manager, and resolves references to them when sourcing, so
+//that .env files never hold plaintext secrets.
+//
+//It is a separate module that depends on github.com/zalando/go-keyring, so that
+//the dotenv module itself has no dependencies.
+package keychain
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/gogolfing/dotenv"
+	"github.com/zalando/go-keyring"
+)
+
+//Scheme is the resolver scheme of keychain references, e.g. "keychain:DB_PASSWORD".
+const Scheme = "keychain"
+
+//Keychain stores and resolves secrets of a single service in the operating
+//system's credential store. Each secret is stored with the service name and the
+//reference as its account.
+type Keychain struct {
+	//Service is the service name of the stored secrets, typically the name of
+	//the application.
+	Service string
+}
+
+//New returns a Keychain for service.
+func New(service string) *Keychain {
+	return &Keychain{Service: service}
+}
+
+//Resolve implements dotenv.Resolver by returning the secret stored for ref.
+//If no secret is stored for ref, then an *dotenv.ErrPermanent is returned.
+//Register k with a dotenv.Sourcer as
+//
+//	s.Resolvers = map[string]dotenv.Resolver{keychain.Scheme: k}
+func (k *Keychain) Resolve(ctx context.Context, ref string) (string, error) {
+	value, err := keyring.Get(k.Service, ref)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", &dotenv.ErrPermanent{Err: err}
+	}
+	return value, err
+}
+
+//Set stores value as the secret for ref, replacing any existing secret.
+func (k *Keychain) Set(ref, value string) error {
+	return keyring.Set(k.Service, ref, value)
+}
+
+//Delete removes the secret stored for ref.
+//If no secret is stored for ref, then nil is returned.
+func (k *Keychain) Delete(ref string) error {
+	if err := keyring.Delete(k.Service, ref); err != nil && !errors.Is(err, keyring.ErrNotFound) {
+		return err
+	}
+	return nil
+}
+
+//Store moves the values of names in d into k, replacing each value with a
+//reference of the form keychain:NAME. Names whose value already is a keychain
+//reference are left unchanged. d should then be written back to its file.
+//If a name is not defined in d, then a dotenv.ErrMissingVariables is returned and
+//no value is stored.
+//If storing a value fails, then that error is returned, and the values stored
+//before it remain replaced.
+func (k *Keychain) Store(d *dotenv.Document, names ...string) error {
+	if err := checkDefined(d, names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		value, _ := d.Get(name)
+		if strings.HasPrefix(value, Scheme+":") {
+			continue
+		}
+		if err := k.Set(name, value); err != nil {
+			return err
+		}
+		d.Set(name, Scheme+":"+name)
+	}
+	return nil
+}
+
+//Reveal is the inverse of Store. It replaces the keychain references of names in
+//d with the secrets they refer to, without removing the secrets from k.
+//Names whose value is not a keychain reference are left unchanged.
+//If a name is not defined in d, then a dotenv.ErrMissingVariables is returned and
+//nothing is replaced.
+//If resolving a reference fails, then that error is returned, and the values
+//revealed before it remain replaced.
+func (k *Keychain) Reveal(ctx context.Context, d *dotenv.Document, names ...string) error {
+	if err := checkDefined(d, names); err != nil {
+		return err
+	}
+	for _, name := range names {
+		value, _ := d.Get(name)
+		ref := strings.TrimPrefix(value, Scheme+":")
+		if ref == value {
+			continue
+		}
+		secret, err := k.Resolve(ctx, ref)
+		if err != nil {
+			return err
+		}
+		d.Set(name, secret)
+	}
+	return nil
+}
+
+//checkDefined returns a dotenv.ErrMissingVariables for the names not defined in
+//d, or nil if all are defined.
+func checkDefined(d *dotenv.Document, names []string) error {
+	missing := dotenv.ErrMissingVariables{}
+	for _, name := range names {
+		if _, ok := d.Get(name); !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return missing
+	}
+	return nil
+}
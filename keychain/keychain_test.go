@@ -0,0 +1,122 @@
+package keychain
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/dotenv"
+	"github.com/zalando/go-keyring"
+)
+
+func parseDocument(t *testing.T, source string) *dotenv.Document {
+	d, err := dotenv.NewDefault().ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func TestKeychain_StoreReveal(t *testing.T) {
+	keyring.MockInit()
+	k := New("app")
+	d := parseDocument(t, "USER=admin\nPASSWORD=secret\nTOKEN=keychain:OTHER\n")
+
+	if err := k.Store(d, "PASSWORD", "TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	if result := d.String(); result != "USER=admin\nPASSWORD=keychain:PASSWORD\nTOKEN=keychain:OTHER\n" {
+		t.Errorf("Store() = %q", result)
+	}
+	if value, err := keyring.Get("app", "PASSWORD"); value != "secret" || err != nil {
+		t.Errorf("keyring.Get() = %q, %v", value, err)
+	}
+	if _, err := keyring.Get("app", "TOKEN"); err != keyring.ErrNotFound {
+		t.Errorf("references must not be stored, err = %v", err)
+	}
+
+	if err := k.Set("OTHER", "token"); err != nil {
+		t.Fatal(err)
+	}
+	if err := k.Reveal(context.Background(), d, "USER", "PASSWORD", "TOKEN"); err != nil {
+		t.Fatal(err)
+	}
+	if result := d.String(); result != "USER=admin\nPASSWORD=secret\nTOKEN=token\n" {
+		t.Errorf("Reveal() = %q", result)
+	}
+	if value, err := keyring.Get("app", "PASSWORD"); value != "secret" || err != nil {
+		t.Errorf("Reveal() must keep the secret, keyring.Get() = %q, %v", value, err)
+	}
+}
+
+func TestKeychain_Store_missing(t *testing.T) {
+	keyring.MockInit()
+	k := New("app")
+	d := parseDocument(t, "PASSWORD=secret\n")
+
+	err := k.Store(d, "PASSWORD", "MISSING")
+	if !reflect.DeepEqual(err, dotenv.ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := keyring.Get("app", "PASSWORD"); err != keyring.ErrNotFound {
+		t.Errorf("nothing must be stored, err = %v", err)
+	}
+	err = k.Reveal(context.Background(), d, "MISSING")
+	if !reflect.DeepEqual(err, dotenv.ErrMissingVariables{"MISSING"}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestKeychain_Resolve(t *testing.T) {
+	keyring.MockInit()
+	k := New("app")
+	if err := k.Set("DB", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if value, err := k.Resolve(context.Background(), "DB"); value != "secret" || err != nil {
+		t.Errorf("Resolve() = %q, %v", value, err)
+	}
+
+	_, err := k.Resolve(context.Background(), "MISSING")
+	permanent := &dotenv.ErrPermanent{}
+	if !errors.As(err, &permanent) || !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("err = %#v, want a permanent keyring.ErrNotFound", err)
+	}
+	if _, err := New("other").Resolve(context.Background(), "DB"); !errors.Is(err, keyring.ErrNotFound) {
+		t.Errorf("secrets must be per service, err = %v", err)
+	}
+
+	unavailable := errors.New("unavailable")
+	keyring.MockInitWithError(unavailable)
+	_, err = k.Resolve(context.Background(), "DB")
+	if err != unavailable {
+		t.Errorf("err = %v, want the error unchanged", err)
+	}
+}
+
+func TestKeychain_Delete(t *testing.T) {
+	keyring.MockInit()
+	k := New("app")
+	if err := k.Set("DB", "secret"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := k.Delete("DB"); err != nil {
+		t.Errorf("Delete() = %v", err)
+	}
+	if _, err := keyring.Get("app", "DB"); err != keyring.ErrNotFound {
+		t.Errorf("err = %v", err)
+	}
+	if err := k.Delete("DB"); err != nil {
+		t.Errorf("Delete() of a missing secret = %v", err)
+	}
+
+	unavailable := errors.New("unavailable")
+	keyring.MockInitWithError(unavailable)
+	if err := k.Delete("DB"); err != unavailable {
+		t.Errorf("err = %v", err)
+	}
+}
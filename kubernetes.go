@@ -0,0 +1,200 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"encoding/json"
+	"io"
+)
+
+//KubernetesReference is a value in a Kubernetes container's environment that
+//refers to data stored elsewhere, which is not resolved.
+type KubernetesReference struct {
+	//Name is the name of the variable. It is empty for the envFrom kinds
+	//configMapRef and secretRef, which define a variable for every key of Ref.
+	Name string
+
+	//Kind is the kind of the reference, i.e. configMapKeyRef, secretKeyRef,
+	//fieldRef, resourceFieldRef, configMapRef, or secretRef.
+	Kind string
+
+	//Ref identifies the referenced data. It is NAME/KEY for configMapKeyRef and
+	//secretKeyRef, the field path for fieldRef, the resource for
+	//resourceFieldRef, and the name of the ConfigMap or Secret for configMapRef
+	//and secretRef.
+	Ref string
+}
+
+//KubernetesContainer is the environment of a container in a Kubernetes
+//manifest.
+type KubernetesContainer struct {
+	//Object identifies the manifest object that defines the container as
+	//KIND/NAME, e.g. "Deployment/web".
+	Object string
+
+	//Name is the name of the container.
+	Name string
+
+	//Init is true if the container is an init container.
+	Init bool
+
+	//Document contains a definition for every variable of the container's env,
+	//in order. Variables whose value is a reference are defined with the empty
+	//value and, if the Sourcer has a Comment, an inline comment of the form
+	//"KIND REF", e.g. "secretKeyRef db/password".
+	Document *Document
+
+	//References contains the references in the container's env and envFrom, in
+	//order.
+	References []*KubernetesReference
+}
+
+//kubernetesObject is the part of a Kubernetes object that is decoded.
+type kubernetesObject struct {
+	Kind     string `json:"kind"`
+	Metadata struct {
+		Name string `json:"name"`
+	} `json:"metadata"`
+	Spec  *kubernetesSpec     `json:"spec"`
+	Items []*kubernetesObject `json:"items"`
+}
+
+//kubernetesSpec is the spec of a Pod, or of an object with a Pod template.
+type kubernetesSpec struct {
+	InitContainers []*kubernetesContainer `json:"initContainers"`
+	Containers     []*kubernetesContainer `json:"containers"`
+
+	Template *struct {
+		Spec *kubernetesSpec `json:"spec"`
+	} `json:"template"`
+
+	JobTemplate *struct {
+		Spec *kubernetesSpec `json:"spec"`
+	} `json:"jobTemplate"`
+}
+
+//kubernetesContainer is the part of a container that is decoded.
+type kubernetesContainer struct {
+	Name string `json:"name"`
+	Env  []*struct {
+		Name      string `json:"name"`
+		Value     string `json:"value"`
+		ValueFrom *struct {
+			ConfigMapKeyRef *kubernetesKeyRef `json:"configMapKeyRef"`
+			SecretKeyRef    *kubernetesKeyRef `json:"secretKeyRef"`
+			FieldRef        *struct {
+				FieldPath string `json:"fieldPath"`
+			} `json:"fieldRef"`
+			ResourceFieldRef *struct {
+				Resource string `json:"resource"`
+			} `json:"resourceFieldRef"`
+		} `json:"valueFrom"`
+	} `json:"env"`
+	EnvFrom []*struct {
+		ConfigMapRef *kubernetesKeyRef `json:"configMapRef"`
+		SecretRef    *kubernetesKeyRef `json:"secretRef"`
+	} `json:"envFrom"`
+}
+
+//kubernetesKeyRef is a reference to a ConfigMap or Secret, or to a key of one.
+type kubernetesKeyRef struct {
+	Name string `json:"name"`
+	Key  string `json:"key"`
+}
+
+//DecodeKubernetes decodes the containers of the Kubernetes objects in in, in
+//order. in contains one or more JSON manifests, such as the output of kubectl
+//get -o json. YAML manifests must be converted to JSON first.
+//Containers are found in Pods, in objects with a Pod template such as
+//Deployments, StatefulSets, DaemonSets, and Jobs, in CronJobs, and in the items of
+//Lists. All other objects are ignored.
+//s is used for the Document of each container, so that it can be compared
+//against Documents parsed by s.
+//If in cannot be decoded, then that error is returned.
+func (s *Sourcer) DecodeKubernetes(in io.Reader) ([]*KubernetesContainer, error) {
+	result := []*KubernetesContainer{}
+	decoder := json.NewDecoder(in)
+	for {
+		object := &kubernetesObject{}
+		if err := decoder.Decode(object); err == io.EOF {
+			return result, nil
+		} else if err != nil {
+			return nil, err
+		}
+		result = s.appendKubernetesObject(result, object)
+	}
+}
+
+//appendKubernetesObject appends the containers of object, and of its items, to
+//result.
+func (s *Sourcer) appendKubernetesObject(result []*KubernetesContainer, object *kubernetesObject) []*KubernetesContainer {
+	for _, item := range object.Items {
+		result = s.appendKubernetesObject(result, item)
+	}
+	spec := object.Spec
+	for spec != nil && spec.Containers == nil {
+		switch {
+		case spec.Template != nil:
+			spec = spec.Template.Spec
+		case spec.JobTemplate != nil:
+			spec = spec.JobTemplate.Spec
+		default:
+			spec = nil
+		}
+	}
+	if spec == nil {
+		return result
+	}
+
+	id := object.Kind + "/" + object.Metadata.Name
+	for _, container := range spec.InitContainers {
+		result = append(result, s.kubernetesContainer(id, container, true))
+	}
+	for _, container := range spec.Containers {
+		result = append(result, s.kubernetesContainer(id, container, false))
+	}
+	return result
+}
+
+//kubernetesContainer returns the environment of container.
+func (s *Sourcer) kubernetesContainer(object string, container *kubernetesContainer, init bool) *KubernetesContainer {
+	result := &KubernetesContainer{
+		Object:     object,
+		Name:       container.Name,
+		Init:       init,
+		Document:   &Document{sourcer: s, lines: []*documentLine{}},
+		References: []*KubernetesReference{},
+	}
+	for _, env := range container.Env {
+		ref := &KubernetesReference{Name: env.Name}
+		if from := env.ValueFrom; from != nil {
+			switch {
+			case from.ConfigMapKeyRef != nil:
+				ref.Kind, ref.Ref = "configMapKeyRef", from.ConfigMapKeyRef.Name+"/"+from.ConfigMapKeyRef.Key
+			case from.SecretKeyRef != nil:
+				ref.Kind, ref.Ref = "secretKeyRef", from.SecretKeyRef.Name+"/"+from.SecretKeyRef.Key
+			case from.FieldRef != nil:
+				ref.Kind, ref.Ref = "fieldRef", from.FieldRef.FieldPath
+			case from.ResourceFieldRef != nil:
+				ref.Kind, ref.Ref = "resourceFieldRef", from.ResourceFieldRef.Resource
+			}
+		}
+		result.Document.Set(env.Name, env.Value)
+		if ref.Kind == "" {
+			continue
+		}
+		result.References = append(result.References, ref)
+		if s.Comment != "" {
+			result.Document.SetInlineComment(env.Name, ref.Kind+" "+ref.Ref)
+		}
+	}
+	for _, from := range container.EnvFrom {
+		switch {
+		case from.ConfigMapRef != nil:
+			result.References = append(result.References, &KubernetesReference{Kind: "configMapRef", Ref: from.ConfigMapRef.Name})
+		case from.SecretRef != nil:
+			result.References = append(result.References, &KubernetesReference{Kind: "secretRef", Ref: from.SecretRef.Name})
+		}
+	}
+	return result
+}
@@ -0,0 +1,95 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_DecodeKubernetes(t *testing.T) {
+	in := `{
+  "kind": "Deployment",
+  "metadata": {"name": "web"},
+  "spec": {"template": {"spec": {
+    "initContainers": [{"name": "migrate", "env": [{"name": "A", "value": "a"}]}],
+    "containers": [{
+      "name": "app",
+      "env": [
+        {"name": "PORT", "value": "8080"},
+        {"name": "MOTD", "value": "hello world #1"},
+        {"name": "DB_PASSWORD", "valueFrom": {"secretKeyRef": {"name": "db", "key": "password"}}},
+        {"name": "LEVEL", "valueFrom": {"configMapKeyRef": {"name": "cfg", "key": "level"}}},
+        {"name": "POD", "valueFrom": {"fieldRef": {"fieldPath": "metadata.name"}}},
+        {"name": "CPU", "valueFrom": {"resourceFieldRef": {"resource": "limits.cpu"}}}
+      ],
+      "envFrom": [{"configMapRef": {"name": "shared"}}, {"secretRef": {"name": "creds"}}]
+    }]
+  }}}
+}
+{"kind": "Service", "metadata": {"name": "web"}, "spec": {"ports": []}}
+{"kind": "List", "items": [
+  {"kind": "CronJob", "metadata": {"name": "nightly"}, "spec": {"jobTemplate": {"spec": {"template": {"spec": {"containers": [{"name": "job"}]}}}}}},
+  {"kind": "Pod", "metadata": {"name": "debug"}, "spec": {"containers": [{"name": "shell", "env": [{"name": "B", "value": "b"}]}]}}
+]}`
+	containers, err := NewDefault().DecodeKubernetes(strings.NewReader(in))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := []string{}
+	for _, container := range containers {
+		ids = append(ids, container.Object+" "+container.Name)
+	}
+	wantIDs := []string{"Deployment/web migrate", "Deployment/web app", "CronJob/nightly job", "Pod/debug shell"}
+	if !reflect.DeepEqual(ids, wantIDs) {
+		t.Errorf("ids = %v WANT %v", ids, wantIDs)
+	}
+	if !containers[0].Init || containers[1].Init {
+		t.Error("Init")
+	}
+
+	app := containers[1]
+	want := `PORT=8080
+MOTD="hello world #1"
+DB_PASSWORD= # secretKeyRef db/password
+LEVEL= # configMapKeyRef cfg/level
+POD= # fieldRef metadata.name
+CPU= # resourceFieldRef limits.cpu
+`
+	if app.Document.String() != want {
+		t.Errorf("Document = %v WANT %v", app.Document, want)
+	}
+	if value, _ := app.Document.Get("MOTD"); value != "hello world #1" {
+		t.Errorf("MOTD = %q", value)
+	}
+	wantRefs := []*KubernetesReference{
+		{"DB_PASSWORD", "secretKeyRef", "db/password"},
+		{"LEVEL", "configMapKeyRef", "cfg/level"},
+		{"POD", "fieldRef", "metadata.name"},
+		{"CPU", "resourceFieldRef", "limits.cpu"},
+		{"", "configMapRef", "shared"},
+		{"", "secretRef", "creds"},
+	}
+	if !reflect.DeepEqual(app.References, wantRefs) {
+		t.Errorf("References = %v", app.References)
+	}
+	if len(containers[2].Document.Names()) != 0 || len(containers[2].References) != 0 {
+		t.Fail()
+	}
+}
+
+func TestSourcer_DecodeKubernetes_errors(t *testing.T) {
+	s := &Sourcer{}
+	containers, err := s.DecodeKubernetes(strings.NewReader(`{"kind": "Pod", "spec": {"containers": [{"name": "c", "env": [{"name": "A", "valueFrom": {"secretKeyRef": {"name": "s", "key": "k"}}}]}]}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if containers[0].Document.String() != "A=\n" {
+		t.Errorf("Document = %q", containers[0].Document)
+	}
+
+	if _, err := s.DecodeKubernetes(strings.NewReader(`{"kind": "Pod"} [`)); err == nil {
+		t.Fail()
+	}
+}
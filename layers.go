@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"context"
+)
+
+//SourceFiles calls s.SourceFile() for each of paths in order, so that variables
+//defined in later files override those defined in earlier files.
+//As soon as an error occurs, that error is returned and no further files are
+//sourced.
+//With s.NoOverride, the files are sourced as a single call, so later files still
+//override variables set by earlier ones.
+func (s *Sourcer) SourceFiles(paths ...string) error {
+	return s.sourceFiles(s.newSetter(), paths)
+}
+
+//sourceFiles does the work of SourceFiles, setting variables with st.
+func (s *Sourcer) sourceFiles(st *setter, paths []string) error {
+	for _, path := range paths {
+		if err := s.sourceFile(context.Background(), path, st); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+//Shadow describes a name that is defined in more than one of a set of layered
+//files.
+type Shadow struct {
+	//Name is the shadowed name.
+	Name string
+
+	//Definitions contains every definition of Name, with File and Line set, in the
+	//order they would be sourced.
+	Definitions []*Variable
+}
+
+//Winner returns the definition of sh.Name that takes effect when sourcing, which
+//is the last one.
+func (sh *Shadow) Winner() *Variable {
+	return sh.Definitions[len(sh.Definitions)-1]
+}
+
+//Shadows attempts to parse all files in paths and report every name that is
+//defined in more than one of them, in the order of first definition.
+//Names defined more than once within a single file, but in no other file, are
+//not reported.
+//If an error occurs while opening or parsing a file, then that error is returned.
+func (s *Sourcer) Shadows(paths ...string) ([]*Shadow, error) {
+	byName := map[string]*Shadow{}
+	files := map[string]map[string]bool{}
+	result := []*Shadow{}
+	for _, path := range paths {
+		variables, err := s.VariablesFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, variable := range variables {
+			shadow, ok := byName[variable.Name]
+			if !ok {
+				shadow = &Shadow{Name: variable.Name}
+				byName[variable.Name] = shadow
+				files[variable.Name] = map[string]bool{}
+				result = append(result, shadow)
+			}
+			shadow.Definitions = append(shadow.Definitions, variable)
+			files[variable.Name][path] = true
+		}
+	}
+
+	shadows := []*Shadow{}
+	for _, shadow := range result {
+		if len(files[shadow.Name]) > 1 {
+			shadows = append(shadows, shadow)
+		}
+	}
+	return shadows, nil
+}
@@ -0,0 +1,81 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeLayerFiles(t *testing.T, sources ...string) []string {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	paths := []string{}
+	for i, source := range sources {
+		path := filepath.Join(dir, string(rune('a'+i))+".env")
+		if err := ioutil.WriteFile(path, []byte(source), 0600); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func TestSourcer_SourceFiles(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_LAYER_A=1\nGOGOLFING_DOTENV_LAYER_B=1", "GOGOLFING_DOTENV_LAYER_B=2")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	defer os.Unsetenv("GOGOLFING_DOTENV_LAYER_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_LAYER_B")
+
+	if err := NewDefault().SourceFiles(paths...); err != nil {
+		t.Error(err)
+	}
+	if os.Getenv("GOGOLFING_DOTENV_LAYER_A") != "1" || os.Getenv("GOGOLFING_DOTENV_LAYER_B") != "2" {
+		t.Fail()
+	}
+
+	if err := NewDefault().SourceFiles(paths[0] + ".missing"); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_Shadows(t *testing.T) {
+	paths := writeLayerFiles(t, "A=1\nB=1\nB=2\nC=1", "C=2\nD=1", "A=3\nC=3")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	shadows, err := NewDefault().Shadows(paths...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Shadow{
+		{"A", []*Variable{
+			{Name: "A", Value: "1", File: paths[0], Line: 1},
+			{Name: "A", Value: "3", File: paths[2], Line: 1},
+		}},
+		{"C", []*Variable{
+			{Name: "C", Value: "1", File: paths[0], Line: 4},
+			{Name: "C", Value: "2", File: paths[1], Line: 1},
+			{Name: "C", Value: "3", File: paths[2], Line: 2},
+		}},
+	}
+	if !reflect.DeepEqual(shadows, want) {
+		t.Errorf("shadows = %v WANT %v", shadows, want)
+	}
+	if shadows[1].Winner() != shadows[1].Definitions[2] {
+		t.Fail()
+	}
+}
+
+func TestSourcer_Shadows_error(t *testing.T) {
+	paths := writeLayerFiles(t, "A=1", "B")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	shadows, err := NewDefault().Shadows(paths...)
+	if shadows != nil || !reflect.DeepEqual(err, &ErrSourcing{1, ErrNonVariableLine("B")}) {
+		t.Errorf("shadows, err = %v, %v", shadows, err)
+	}
+}
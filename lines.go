@@ -0,0 +1,44 @@
+package dotenv
+
+import (
+	"errors"
+	"io"
+	"iter"
+)
+
+//byteOrderMark is the UTF-8 encoded byte order mark removed from the beginning
+//of inputs.
+const byteOrderMark = "\ufeff"
+
+//errStopLines is used to stop scanning when the consumer of Lines() stops early.
+var errStopLines = errors.New("stop lines")
+
+//Lines returns an iterator over the lines of in and their line numbers (1-based),
+//exactly as they are seen by the parsing methods of a Sourcer.
+//Line endings may be "\n" or "\r\n" and are not included in the lines, and a UTF-8
+//byte order mark at the beginning of in is removed.
+//Iteration stops silently if reading from in fails. Use LinesErr() to
+//distinguish a read failure from the end of in.
+func Lines(in io.Reader) iter.Seq2[int, string] {
+	seq, _ := LinesErr(in)
+	return seq
+}
+
+//LinesErr is the same as Lines except that it also returns a function that reports
+//the error, if any, that stopped iteration. The function should only be called
+//after iteration has finished.
+func LinesErr(in io.Reader) (seq iter.Seq2[int, string], err func() error) {
+	var scanErr error
+	seq = func(yield func(int, string) bool) {
+		scanErr = scanLines(in, func(lineNumber int, line string) error {
+			if !yield(lineNumber, line) {
+				return errStopLines
+			}
+			return nil
+		})
+		if scanErr == errStopLines {
+			scanErr = nil
+		}
+	}
+	return seq, func() error { return scanErr }
+}
@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestLines(t *testing.T) {
+	in := strings.NewReader("\ufeffa=1\r\n\r\nb=\ufeff2\nc=3")
+	numbers, lines := []int{}, []string{}
+	for n, line := range Lines(in) {
+		numbers = append(numbers, n)
+		lines = append(lines, line)
+	}
+	if !reflect.DeepEqual(numbers, []int{1, 2, 3, 4}) {
+		t.Errorf("numbers = %v", numbers)
+	}
+	if !reflect.DeepEqual(lines, []string{"a=1", "", "b=\ufeff2", "c=3"}) {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestLines_break(t *testing.T) {
+	lines := []string{}
+	for _, line := range Lines(strings.NewReader("a\nb\nc")) {
+		lines = append(lines, line)
+		if line == "b" {
+			break
+		}
+	}
+	if !reflect.DeepEqual(lines, []string{"a", "b"}) {
+		t.Errorf("lines = %q", lines)
+	}
+}
+
+func TestLinesErr(t *testing.T) {
+	readErr := errors.New("read error")
+	seq, err := LinesErr(io.MultiReader(strings.NewReader("a\n"), iotest.ErrReader(readErr)))
+	lines := []string{}
+	for _, line := range seq {
+		lines = append(lines, line)
+	}
+	if !reflect.DeepEqual(lines, []string{"a"}) || err() != readErr {
+		t.Errorf("lines, err = %q, %v", lines, err())
+	}
+}
+
+func TestSourcer_NameVars_byteOrderMark(t *testing.T) {
+	nameVars, err := NewDefault().NameVars(strings.NewReader("\ufeffa=1\r\nb=2\r\n"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"a", "1"}, {"b", "2"}}) {
+		t.Errorf("nameVars = %q", nameVars)
+	}
+}
@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"fmt"
+	"sort"
+)
+
+//Problem is a single finding reported by a Linter.
+type Problem struct {
+	//Location is where the problem occurs in the Document.
+	Location Location `json:"location"`
+
+	//Name is the name of the variable the problem concerns. It is empty if the
+	//problem does not concern a single variable.
+	Name string `json:"name,omitempty"`
+
+	//Rule is a short, stable identifier of the check that found the problem, e.g.
+	//"high-entropy".
+	Rule string `json:"rule"`
+
+	//Message is a human readable description of the problem.
+	Message string `json:"message"`
+}
+
+//String returns p in the form "LINE:COLUMN: RULE: MESSAGE".
+func (p *Problem) String() string {
+	return fmt.Sprintf("%v:%v: %v: %v", p.Location.Line, p.Location.Column, p.Rule, p.Message)
+}
+
+//Linter is a function that examines a Document and reports its problems.
+//See Document.Lint.
+type Linter func(d *Document) []*Problem
+
+//Lint calls each of linters with d and returns all reported problems sorted by
+//location. Problems at the same location remain in the order they were reported.
+//Nil linters are skipped.
+func (d *Document) Lint(linters ...Linter) []*Problem {
+	problems := []*Problem{}
+	for _, linter := range linters {
+		if linter != nil {
+			problems = append(problems, linter(d)...)
+		}
+	}
+	sortProblems(problems)
+	return problems
+}
+
+//sortProblems sorts problems by location, keeping problems at the same location
+//in their original order.
+func sortProblems(problems []*Problem) {
+	sort.SliceStable(problems, func(i, j int) bool {
+		a, b := problems[i].Location, problems[j].Location
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+}
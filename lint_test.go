@@ -0,0 +1,40 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProblem_String(t *testing.T) {
+	p := &Problem{Location{3, 5}, "A", "rule", "message"}
+	if p.String() != "3:5: rule: message" {
+		t.Errorf("p.String() = %q", p.String())
+	}
+}
+
+func TestDocument_Lint(t *testing.T) {
+	doc, err := NewDefault().ParseDocument(strings.NewReader("A=a\nB=b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	first := func(d *Document) []*Problem {
+		return []*Problem{{Location: Location{2, 1}, Rule: "first"}, {Location: Location{1, 3}, Rule: "first"}}
+	}
+	second := func(d *Document) []*Problem {
+		return []*Problem{{Location: Location{2, 1}, Rule: "second"}, {Location: Location{1, 1}, Rule: "second"}}
+	}
+	problems := doc.Lint(first, nil, second)
+	want := []*Problem{
+		{Location: Location{1, 1}, Rule: "second"},
+		{Location: Location{1, 3}, Rule: "first"},
+		{Location: Location{2, 1}, Rule: "first"},
+		{Location: Location{2, 1}, Rule: "second"},
+	}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v WANT %v", problems, want)
+	}
+	if problems := doc.Lint(); len(problems) != 0 {
+		t.Fail()
+	}
+}
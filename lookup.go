@@ -0,0 +1,38 @@
+package dotenv
+
+//LookupFunc is a function that returns the value of name and whether or not it
+//is defined, such as os.LookupEnv. See Sourcer.Lookup.
+type LookupFunc func(name string) (value string, ok bool)
+
+//LookupChain returns a LookupFunc that calls each of lookups in order and
+//returns the first value that is defined.
+//Nil lookups are skipped.
+func LookupChain(lookups ...LookupFunc) LookupFunc {
+	return func(name string) (string, bool) {
+		for _, lookup := range lookups {
+			if lookup == nil {
+				continue
+			}
+			if value, ok := lookup(name); ok {
+				return value, true
+			}
+		}
+		return "", false
+	}
+}
+
+//LookupMap returns a LookupFunc that looks up names in m.
+func LookupMap(m map[string]string) LookupFunc {
+	return func(name string) (string, bool) {
+		value, ok := m[name]
+		return value, ok
+	}
+}
+
+//LookupDefault returns a LookupFunc that defines every name with the result of
+//fn. It is intended to be the last LookupFunc in a LookupChain.
+func LookupDefault(fn func(name string) string) LookupFunc {
+	return func(name string) (string, bool) {
+		return fn(name), true
+	}
+}
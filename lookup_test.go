@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLookupChain(t *testing.T) {
+	lookup := LookupChain(
+		nil,
+		LookupMap(map[string]string{"A": "map", "E": ""}),
+		LookupMap(map[string]string{"A": "shadowed", "B": "second"}),
+	)
+	cases := []struct {
+		name  string
+		value string
+		ok    bool
+	}{
+		{"A", "map", true},
+		{"B", "second", true},
+		{"E", "", true},
+		{"C", "", false},
+	}
+	for _, c := range cases {
+		if value, ok := lookup(c.name); value != c.value || ok != c.ok {
+			t.Errorf("lookup(%q) = %q, %v", c.name, value, ok)
+		}
+	}
+}
+
+func TestLookupDefault(t *testing.T) {
+	lookup := LookupChain(LookupMap(map[string]string{"A": "a"}), LookupDefault(func(name string) string {
+		return "<" + name + ">"
+	}))
+	if value, ok := lookup("B"); value != "<B>" || !ok {
+		t.Errorf("lookup(B) = %q, %v", value, ok)
+	}
+	if value, _ := lookup("A"); value != "a" {
+		t.Fail()
+	}
+}
+
+func TestSourcer_Lookup(t *testing.T) {
+	os.Setenv("GOGOLFING_DOTENV_LOOKUP_ENV", "env")
+	defer os.Unsetenv("GOGOLFING_DOTENV_LOOKUP_ENV")
+
+	s := NewDefault()
+	s.Interpolate = true
+	s.Lookup = LookupChain(
+		LookupMap(map[string]string{"PROVIDED": "provided", "A": "shadowed"}),
+		LookupDefault(func(name string) string { return "default" }),
+	)
+	source := "A=a\nB=${A} ${PROVIDED} ${GOGOLFING_DOTENV_LOOKUP_ENV} ${OTHER}"
+	nameVars, err := s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nameVars[1], [2]string{"B", "a provided default default"}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+
+	s.Lookup = LookupChain(LookupMap(nil), os.LookupEnv)
+	nameVars, err = s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(nameVars[1], [2]string{"B", "a  env "}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
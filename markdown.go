@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+//GenerateMarkdown writes a Markdown table to w with a row for each variable in sc
+//and the columns Name, Type, Required, Default, Description, and Example.
+//Names, defaults, and examples are written as code spans. Cells are escaped so
+//that they cannot break the table.
+func GenerateMarkdown(w io.Writer, sc *Schema) error {
+	buf := &strings.Builder{}
+	buf.WriteString("| Name | Type | Required | Default | Description | Example |\n")
+	buf.WriteString("| ---- | ---- | -------- | ------- | ----------- | ------- |\n")
+	for _, v := range sc.Vars {
+		required := "no"
+		if v.Required {
+			required = "yes"
+		}
+		defaultValue := ""
+		if v.HasDefault {
+			defaultValue = markdownCode(v.Default)
+		}
+		fmt.Fprintf(buf, "| %v | %v | %v | %v | %v | %v |\n",
+			markdownCode(v.Name),
+			string(v.Type),
+			required,
+			defaultValue,
+			markdownCell(v.Description),
+			markdownCode(v.Example),
+		)
+	}
+	_, err := io.WriteString(w, buf.String())
+	return err
+}
+
+//markdownCell returns s escaped for use in a Markdown table cell.
+func markdownCell(s string) string {
+	s = strings.Replace(s, "\r\n", " ", -1)
+	s = strings.Replace(s, "\n", " ", -1)
+	s = strings.Replace(s, `\`, `\\`, -1)
+	return strings.Replace(s, "|", `\|`, -1)
+}
+
+//markdownCode returns s as a Markdown code span for use in a table cell.
+//The code span is delimited by one more backtick than the longest run of
+//backticks in s. The empty string is returned as the empty string.
+func markdownCode(s string) string {
+	if s == "" {
+		return ""
+	}
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '`' {
+			run++
+			if run > longest {
+				longest = run
+			}
+		} else {
+			run = 0
+		}
+	}
+	fence := strings.Repeat("`", longest+1)
+	s = strings.Replace(s, "\n", " ", -1)
+	s = strings.Replace(s, "|", `\|`, -1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return fence + s + fence
+}
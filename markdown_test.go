@@ -0,0 +1,39 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateMarkdown(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{
+		{Name: "PORT", Type: TypeInt, Default: "8080", HasDefault: true, Description: "Port to listen on.", Example: "3000"},
+		{Name: "FILTER", Type: TypeString, Required: true, Description: "a|b", Example: "x`y"},
+	}}
+	out := &strings.Builder{}
+	if err := GenerateMarkdown(out, sc); err != nil {
+		t.Fatal(err)
+	}
+	want := "| Name | Type | Required | Default | Description | Example |\n" +
+		"| ---- | ---- | -------- | ------- | ----------- | ------- |\n" +
+		"| `PORT` | int | no | `8080` | Port to listen on. | `3000` |\n" +
+		"| `FILTER` | string | yes |  | a\\|b | ``x`y`` |\n"
+	if out.String() != want {
+		t.Errorf("out = %v WANT %v", out.String(), want)
+	}
+}
+
+func TestMarkdownCode(t *testing.T) {
+	cases := map[string]string{
+		"":       "",
+		"a":      "`a`",
+		"`a":     "`` `a ``",
+		"a``b":   "```a``b```",
+		"a|b\nc": "`a\\|b c`",
+	}
+	for s, want := range cases {
+		if result := markdownCode(s); result != want {
+			t.Errorf("markdownCode(%q) = %q WANT %q", s, result, want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package dotenv
+
+import (
+	"os"
+	"time"
+)
+
+//Metrics receives measurements of sourcing operations, e.g. to export them to a
+//monitoring system. See Sourcer.Metrics.
+//Implementations must be safe for concurrent use.
+type Metrics interface {
+	//LineParsed is called for every line read from an input.
+	LineParsed()
+
+	//LineError is called for every line that results in a line error.
+	LineError()
+
+	//VariableSet is called for every variable set in the process's
+	//environment.
+	VariableSet()
+
+	//ResolverCalled is called after every call to a Resolver with the scheme of
+	//the Resolver, the duration of the call, and its error.
+	ResolverCalled(scheme string, duration time.Duration, err error)
+}
+
+//NopMetrics is a Metrics that discards all measurements.
+type NopMetrics struct{}
+
+//LineParsed is the Metrics implementation for NopMetrics.
+func (NopMetrics) LineParsed() {}
+
+//LineError is the Metrics implementation for NopMetrics.
+func (NopMetrics) LineError() {}
+
+//VariableSet is the Metrics implementation for NopMetrics.
+func (NopMetrics) VariableSet() {}
+
+//ResolverCalled is the Metrics implementation for NopMetrics.
+func (NopMetrics) ResolverCalled(scheme string, duration time.Duration, err error) {}
+
+//metrics returns s.Metrics, or NopMetrics if it is nil.
+func (s *Sourcer) metrics() Metrics {
+	if s.Metrics == nil {
+		return NopMetrics{}
+	}
+	return s.Metrics
+}
+
+//setenv calls os.Setenv() and records the variable with s.Metrics.
+func (s *Sourcer) setenv(name, value string) error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	if err := os.Setenv(name, value); err != nil {
+		return err
+	}
+	s.metrics().VariableSet()
+	return nil
+}
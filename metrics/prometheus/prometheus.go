@@ -0,0 +1,88 @@
+//Package prometheus provides a dotenv.Metrics implementation that exports
+//measurements as Prometheus metrics.
+//
+//It is a separate module that depends on github.com/prometheus/client_golang, so
+//that the dotenv module itself has no dependencies.
+package prometheus
+
+import (
+	"time"
+
+	"github.com/gogolfing/dotenv"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+//Metrics is a dotenv.Metrics that records measurements in Prometheus collectors.
+type Metrics struct {
+	linesParsed  prometheus.Counter
+	lineErrors   prometheus.Counter
+	variablesSet prometheus.Counter
+	resolverCall *prometheus.HistogramVec
+}
+
+var _ dotenv.Metrics = (*Metrics)(nil)
+
+//New returns a Metrics whose collectors are registered with registerer and have
+//names starting with namespace, e.g. "myapp_dotenv_lines_parsed_total".
+//An empty namespace results in names starting with "dotenv_".
+//If registering fails, then that error is returned.
+func New(registerer prometheus.Registerer, namespace string) (*Metrics, error) {
+	m := &Metrics{
+		linesParsed: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dotenv",
+			Name:      "lines_parsed_total",
+			Help:      "Number of lines read from dotenv inputs.",
+		}),
+		lineErrors: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dotenv",
+			Name:      "line_errors_total",
+			Help:      "Number of lines of dotenv inputs that resulted in an error.",
+		}),
+		variablesSet: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "dotenv",
+			Name:      "variables_set_total",
+			Help:      "Number of variables set in the process's environment.",
+		}),
+		resolverCall: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "dotenv",
+			Name:      "resolver_duration_seconds",
+			Help:      "Duration of calls to value Resolvers.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"scheme", "result"}),
+	}
+	for _, collector := range []prometheus.Collector{m.linesParsed, m.lineErrors, m.variablesSet, m.resolverCall} {
+		if err := registerer.Register(collector); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+//LineParsed is the dotenv.Metrics implementation for Metrics.
+func (m *Metrics) LineParsed() {
+	m.linesParsed.Inc()
+}
+
+//LineError is the dotenv.Metrics implementation for Metrics.
+func (m *Metrics) LineError() {
+	m.lineErrors.Inc()
+}
+
+//VariableSet is the dotenv.Metrics implementation for Metrics.
+func (m *Metrics) VariableSet() {
+	m.variablesSet.Inc()
+}
+
+//ResolverCalled is the dotenv.Metrics implementation for Metrics.
+//Calls are labeled with their scheme and a result of "ok" or "error".
+func (m *Metrics) ResolverCalled(scheme string, duration time.Duration, err error) {
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.resolverCall.WithLabelValues(scheme, result).Observe(duration.Seconds())
+}
@@ -0,0 +1,68 @@
+package prometheus
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m, err := New(registry, "app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m.LineParsed()
+	m.LineParsed()
+	m.LineError()
+	m.VariableSet()
+	m.ResolverCalled("vault", time.Second, nil)
+	m.ResolverCalled("vault", time.Second, errors.New("unavailable"))
+	m.ResolverCalled("vault", time.Second, nil)
+
+	tests := []struct {
+		collector prometheus.Collector
+		want      float64
+	}{
+		{m.linesParsed, 2},
+		{m.lineErrors, 1},
+		{m.variablesSet, 1},
+	}
+	for _, test := range tests {
+		if value := testutil.ToFloat64(test.collector); value != test.want {
+			t.Errorf("value = %v, want %v", value, test.want)
+		}
+	}
+
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	counts := map[string]uint64{}
+	for _, family := range families {
+		for _, metric := range family.GetMetric() {
+			if histogram := metric.GetHistogram(); histogram != nil {
+				result := ""
+				for _, label := range metric.GetLabel() {
+					result += label.GetName() + "=" + label.GetValue() + " "
+				}
+				counts[family.GetName()+" "+result] = histogram.GetSampleCount()
+			}
+		}
+	}
+	if counts["app_dotenv_resolver_duration_seconds result=ok scheme=vault "] != 2 ||
+		counts["app_dotenv_resolver_duration_seconds result=error scheme=vault "] != 1 || len(counts) != 2 {
+		t.Errorf("counts = %v", counts)
+	}
+
+	if _, err := New(registry, "app"); err == nil {
+		t.Errorf("registering twice must fail")
+	}
+	if _, err := New(registry, ""); err != nil {
+		t.Errorf("err = %v", err)
+	}
+}
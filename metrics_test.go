@@ -0,0 +1,81 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//recordingMetrics is a Metrics that records its calls.
+type recordingMetrics struct {
+	mu        sync.Mutex
+	counts    map[string]int
+	resolvers []string
+}
+
+func (m *recordingMetrics) inc(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.counts[name]++
+}
+
+func (m *recordingMetrics) LineParsed()  { m.inc("parsed") }
+func (m *recordingMetrics) LineError()   { m.inc("error") }
+func (m *recordingMetrics) VariableSet() { m.inc("set") }
+
+func (m *recordingMetrics) ResolverCalled(scheme string, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+	m.resolvers = append(m.resolvers, scheme+" "+result)
+}
+
+func TestNopMetrics(t *testing.T) {
+	var m Metrics = NopMetrics{}
+	m.LineParsed()
+	m.LineError()
+	m.VariableSet()
+	m.ResolverCalled("s", time.Second, nil)
+}
+
+func TestSourcer_Metrics(t *testing.T) {
+	skipUnlessSetenv(t)
+	defer os.Unsetenv("GOGOLFING_DOTENV_METRICS_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_METRICS_B")
+
+	m := &recordingMetrics{counts: map[string]int{}}
+	s := NewDefault()
+	s.Metrics = m
+	s.Resolvers = map[string]Resolver{
+		"ok": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+			return ref, nil
+		}),
+		"fail": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+			return "", errors.New("fail")
+		}),
+	}
+
+	source := "# comment\nGOGOLFING_DOTENV_METRICS_A=ok:a\n\nGOGOLFING_DOTENV_METRICS_B=b\n"
+	if err := s.Source(strings.NewReader(source)); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(m.counts, map[string]int{"parsed": 4, "set": 2}) {
+		t.Errorf("counts = %v", m.counts)
+	}
+
+	_, lineErrors, _ := s.NameVarsBestEffort(strings.NewReader("A=fail:a\nbad line\nC=c"))
+	if len(lineErrors) != 2 || m.counts["error"] != 2 || m.counts["parsed"] != 7 {
+		t.Errorf("lineErrors, counts = %v, %v", lineErrors, m.counts)
+	}
+	if !reflect.DeepEqual(m.resolvers, []string{"ok ok", "fail error"}) {
+		t.Errorf("resolvers = %v", m.resolvers)
+	}
+}
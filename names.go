@@ -0,0 +1,55 @@
+package dotenv
+
+import "io"
+
+//Stats is a summary of the variable definitions in an input.
+//It intentionally does not contain any variable values so that inputs holding
+//secrets can be inspected without retaining those secrets.
+type Stats struct {
+	//Definitions is the total number of variable definitions.
+	Definitions int
+
+	//Names contains every defined name once, in the order of first definition.
+	Names []string
+
+	//Duplicates contains every name defined more than once, in the order of
+	//second definition.
+	Duplicates []string
+}
+
+//Names attempts to parse all variable definitions from in and return the
+//defined names, without duplicates, in the order of first definition.
+//Values are discarded as soon as each line is parsed.
+//If an error occurs while parsing, then that *ErrSourcing is returned.
+func (s *Sourcer) Names(in io.Reader) ([]string, error) {
+	stats, err := s.Stats(in)
+	if err != nil {
+		return nil, err
+	}
+	return stats.Names, nil
+}
+
+//Stats attempts to parse all variable definitions from in and return a summary
+//of them.
+//Values are discarded as soon as each line is parsed.
+//If an error occurs while parsing, then that *ErrSourcing is returned.
+func (s *Sourcer) Stats(in io.Reader) (*Stats, error) {
+	stats := &Stats{Names: []string{}, Duplicates: []string{}}
+	counts := map[string]int{}
+
+	err := s.sourceVisitor(in, func(name, _ string) error {
+		stats.Definitions++
+		counts[name]++
+		switch counts[name] {
+		case 1:
+			stats.Names = append(stats.Names, name)
+		case 2:
+			stats.Duplicates = append(stats.Duplicates, name)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,39 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_Names_success(t *testing.T) {
+	names, err := NewDefault().Names(strings.NewReader("a=1\nb=2\n#c=3\na=4\n"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b"}) {
+		t.Errorf("names = %v", names)
+	}
+}
+
+func TestSourcer_Names_error(t *testing.T) {
+	names, err := NewDefault().Names(strings.NewReader("a=1\nb"))
+	if names != nil || !reflect.DeepEqual(err, &ErrSourcing{2, ErrNonVariableLine("b")}) {
+		t.Errorf("names, err = %v, %v", names, err)
+	}
+}
+
+func TestSourcer_Stats(t *testing.T) {
+	stats, err := NewDefault().Stats(strings.NewReader("a=1\nb=2\n\na=3\nc=4\na=5\nc=6"))
+	if err != nil {
+		t.Error(err)
+	}
+	want := &Stats{
+		Definitions: 6,
+		Names:       []string{"a", "b", "c"},
+		Duplicates:  []string{"a", "c"},
+	}
+	if !reflect.DeepEqual(stats, want) {
+		t.Errorf("stats = %v WANT %v", stats, want)
+	}
+}
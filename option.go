@@ -0,0 +1,234 @@
+package dotenv
+
+//Option configures a Sourcer created by NewSourcerWith.
+//Options are applied in order, so later options override earlier ones for the
+//same field.
+type Option func(s *Sourcer)
+
+//NewSourcerWith returns a Sourcer with the syntax of NewDefault() that is then
+//configured by opts.
+//It is equivalent to setting the fields of the Sourcer returned from NewDefault()
+//directly. The fields remain available, but options allow Sourcers to be
+//configured without depending on the fields that options set.
+func NewSourcerWith(opts ...Option) *Sourcer {
+	s := NewDefault()
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+//with returns s if opts is empty, and otherwise a clone of s that is changed by
+//opts.
+func (s *Sourcer) with(opts []Option) *Sourcer {
+	if len(opts) == 0 {
+		return s
+	}
+	clone := s.Clone()
+	for _, opt := range opts {
+		opt(clone)
+	}
+	return clone
+}
+
+//WithComment sets Comment. An empty comment disallows comments.
+func WithComment(comment string) Option {
+	return func(s *Sourcer) {
+		s.Comment = comment
+	}
+}
+
+//WithQuote sets Quote and Unquote. An empty quote disallows quoting.
+func WithQuote(quote string, unquote func(s string) (string, error)) Option {
+	return func(s *Sourcer) {
+		s.Quote, s.Unquote = quote, unquote
+	}
+}
+
+//WithExport sets Export and EmptyExport. An empty export disallows the export
+//keyword.
+func WithExport(export string, emptyExport bool) Option {
+	return func(s *Sourcer) {
+		s.Export, s.EmptyExport = export, emptyExport
+	}
+}
+
+//WithSpaceAroundEqual sets SpaceAroundEqual to true.
+func WithSpaceAroundEqual() Option {
+	return func(s *Sourcer) {
+		s.SpaceAroundEqual = true
+	}
+}
+
+//WithQuotedComments sets QuotedComments to true.
+func WithQuotedComments() Option {
+	return func(s *Sourcer) {
+		s.QuotedComments = true
+	}
+}
+
+//WithLenient sets EmptyExport, SpaceAroundEqual, and PassThrough to true, which
+//accepts most inputs written for POSIX shells and docker compose.
+func WithLenient() Option {
+	return func(s *Sourcer) {
+		s.EmptyExport, s.SpaceAroundEqual, s.PassThrough = true, true, true
+	}
+}
+
+//WithStripPrefix sets StripPrefix and PrefixOnly.
+func WithStripPrefix(prefix string, only bool) Option {
+	return func(s *Sourcer) {
+		s.StripPrefix, s.PrefixOnly = prefix, only
+	}
+}
+
+//WithInterpolate sets Interpolate to true and InterpolatePercent to percent.
+func WithInterpolate(percent bool) Option {
+	return func(s *Sourcer) {
+		s.Interpolate, s.InterpolatePercent = true, percent
+	}
+}
+
+//WithInterpolateHermetic sets Interpolate and InterpolateHermetic to true.
+func WithInterpolateHermetic() Option {
+	return func(s *Sourcer) {
+		s.Interpolate, s.InterpolateHermetic = true, true
+	}
+}
+
+//WithLookup sets Lookup.
+func WithLookup(lookup LookupFunc) Option {
+	return func(s *Sourcer) {
+		s.Lookup = lookup
+	}
+}
+
+//WithResolver adds resolver to Resolvers for scheme, replacing any Resolver
+//already added for scheme.
+func WithResolver(scheme string, resolver Resolver) Option {
+	return func(s *Sourcer) {
+		resolvers := map[string]Resolver{scheme: resolver}
+		for key, value := range s.Resolvers {
+			if key != scheme {
+				resolvers[key] = value
+			}
+		}
+		s.Resolvers = resolvers
+	}
+}
+
+//WithTransform adds transform after any TransformValue that is already set, as
+//if composed with Transforms().
+func WithTransform(transform Transform) Option {
+	return func(s *Sourcer) {
+		if s.TransformValue == nil {
+			s.TransformValue = transform
+			return
+		}
+		s.TransformValue = Transforms(s.TransformValue, transform)
+	}
+}
+
+//WithPolicy adds policy after any Policy that is already set, as if composed with
+//Policies().
+func WithPolicy(policy Policy) Option {
+	return func(s *Sourcer) {
+		if s.Policy == nil {
+			s.Policy = policy
+			return
+		}
+		s.Policy = Policies(s.Policy, policy)
+	}
+}
+
+//WithOSSuffix sets OSSuffix to true and GOOS to goos. An empty goos means
+//runtime.GOOS.
+func WithOSSuffix(goos string) Option {
+	return func(s *Sourcer) {
+		s.OSSuffix, s.GOOS = true, goos
+	}
+}
+
+//WithPassThrough sets PassThrough to true and PassThroughStrict to strict.
+func WithPassThrough(strict bool) Option {
+	return func(s *Sourcer) {
+		s.PassThrough, s.PassThroughStrict = true, strict
+	}
+}
+
+//WithInclude sets Include to directive.
+func WithInclude(directive string) Option {
+	return func(s *Sourcer) {
+		s.Include = directive
+	}
+}
+
+//WithIncludeLimits sets MaxIncludeDepth and MaxIncludeFiles. Zero values mean
+//the defaults.
+func WithIncludeLimits(maxDepth, maxFiles int) Option {
+	return func(s *Sourcer) {
+		s.MaxIncludeDepth, s.MaxIncludeFiles = maxDepth, maxFiles
+	}
+}
+
+//WithVersionDirective sets VersionDirective to true.
+func WithVersionDirective() Option {
+	return func(s *Sourcer) {
+		s.VersionDirective = true
+	}
+}
+
+//WithLineParser sets LineParser.
+func WithLineParser(parser LineParser) Option {
+	return func(s *Sourcer) {
+		s.LineParser = parser
+	}
+}
+
+//WithLineFilter sets LineFilter.
+func WithLineFilter(filter func(line string, n int) bool) Option {
+	return func(s *Sourcer) {
+		s.LineFilter = filter
+	}
+}
+
+//WithMetrics sets Metrics.
+func WithMetrics(metrics Metrics) Option {
+	return func(s *Sourcer) {
+		s.Metrics = metrics
+	}
+}
+
+//WithTracer sets Tracer.
+func WithTracer(tracer Tracer) Option {
+	return func(s *Sourcer) {
+		s.Tracer = tracer
+	}
+}
+
+//WithNoOverride sets NoOverride to true.
+func WithNoOverride() Option {
+	return func(s *Sourcer) {
+		s.NoOverride = true
+	}
+}
+
+//WithWarn sets Warn.
+func WithWarn(warn func(problem *Problem)) Option {
+	return func(s *Sourcer) {
+		s.Warn = warn
+	}
+}
+
+//WithAlias adds oldName as an alias of newName to Aliases.
+func WithAlias(oldName, newName string) Option {
+	return func(s *Sourcer) {
+		aliases := map[string]string{oldName: newName}
+		for key, value := range s.Aliases {
+			if key != oldName {
+				aliases[key] = value
+			}
+		}
+		s.Aliases = aliases
+	}
+}
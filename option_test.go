@@ -0,0 +1,159 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSourcerWith_default(t *testing.T) {
+	s := NewSourcerWith()
+	d := NewDefault()
+	if s.Comment != d.Comment || s.Quote != d.Quote || s.Export != d.Export || s.Unquote == nil {
+		t.Errorf("s = %v", s)
+	}
+}
+
+func TestNewSourcerWith(t *testing.T) {
+	counting := &countingResolver{}
+	s := NewSourcerWith(
+		WithComment(";"),
+		WithExport("set", true),
+		WithLenient(),
+		WithStripPrefix("APP_", true),
+		WithInterpolate(true),
+		WithResolver("a", ResolveFile),
+		WithResolver("b", ResolveFile),
+		WithResolver("a", counting),
+		WithTransform(func(name, value string) (string, error) { return value + "1", nil }),
+		WithTransform(func(name, value string) (string, error) { return value + "2", nil }),
+		WithPolicy(ForbidValues("x12")),
+		WithOSSuffix("plan9"),
+		WithPassThrough(true),
+		WithInclude("include"),
+		WithIncludeLimits(2, 3),
+		WithVersionDirective(),
+	)
+	if s.Comment != ";" || s.Export != "set" || !s.EmptyExport || !s.SpaceAroundEqual {
+		t.Error("syntax")
+	}
+	if s.StripPrefix != "APP_" || !s.PrefixOnly || !s.Interpolate || !s.InterpolatePercent {
+		t.Error("names and interpolation")
+	}
+	if len(s.Resolvers) != 2 || s.Resolvers["a"] != Resolver(counting) {
+		t.Errorf("Resolvers = %v", s.Resolvers)
+	}
+	if v, err := s.TransformValue("A", "v"); v != "v12" || err != nil {
+		t.Errorf("TransformValue() = %v, %v", v, err)
+	}
+	if s.Policy("A", "x12") == nil || s.Policy("A", "x") != nil {
+		t.Error("Policy")
+	}
+	if !s.OSSuffix || s.GOOS != "plan9" || !s.PassThrough || !s.PassThroughStrict {
+		t.Error("OSSuffix and PassThrough")
+	}
+	if s.Include != "include" || s.MaxIncludeDepth != 2 || s.MaxIncludeFiles != 3 || !s.VersionDirective {
+		t.Error("Include and VersionDirective")
+	}
+
+	nameVars, err := s.NameVars(strings.NewReader("set APP_A = a ;comment\nB=b\nset\n"))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a12"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+}
+
+func TestNewSourcerWith_hooks(t *testing.T) {
+	metrics, tracer := &recordingMetrics{counts: map[string]int{}}, &recordingTracer{}
+	parser := LineParserFunc(func(line string) (string, string, error) {
+		return "A", line, nil
+	})
+	s := NewSourcerWith(
+		WithQuote("'", nil),
+		WithInterpolateHermetic(),
+		WithLookup(LookupMap(map[string]string{"B": "b"})),
+		WithLineFilter(func(line string, n int) bool { return n == 1 }),
+		WithLineParser(parser),
+		WithMetrics(metrics),
+		WithTracer(tracer),
+	)
+	if s.Quote != "'" || s.Unquote != nil || !s.Interpolate || !s.InterpolateHermetic {
+		t.Error("quote and interpolation")
+	}
+	if v, ok := s.Lookup("B"); v != "b" || !ok {
+		t.Error("Lookup")
+	}
+	if s.Metrics != metrics || s.Tracer != tracer {
+		t.Error("Metrics and Tracer")
+	}
+	nameVars, err := s.NameVars(strings.NewReader("1\n2"))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "1"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+	if metrics.counts["parsed"] != 2 {
+		t.Errorf("counts = %v", metrics.counts)
+	}
+}
+
+func TestSourcer_perCallOptions(t *testing.T) {
+	s := NewDefault()
+	input := "APP_A=a\nB=b\n"
+	nameVars, err := s.NameVars(strings.NewReader(input), WithStripPrefix("APP_", true))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+	if s.StripPrefix != "" || s.PrefixOnly {
+		t.Error("per-call options must not change the Sourcer")
+	}
+	nameVars, err = s.NameVars(strings.NewReader(input))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"APP_A", "a"}, {"B", "b"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+
+	variables, err := s.Freeze().Variables(strings.NewReader(input), WithStripPrefix("APP_", true))
+	if err != nil || len(variables) != 1 || variables[0].Name != "A" {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+}
+
+func TestSourcer_Source_noOverride(t *testing.T) {
+	skipUnlessSetenv(t)
+	defer os.Unsetenv("GOGOLFING_DOTENV_NO_OVERRIDE_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_NO_OVERRIDE_B")
+	os.Setenv("GOGOLFING_DOTENV_NO_OVERRIDE_A", "before")
+	os.Unsetenv("GOGOLFING_DOTENV_NO_OVERRIDE_B")
+
+	input := `GOGOLFING_DOTENV_NO_OVERRIDE_A=a1
+GOGOLFING_DOTENV_NO_OVERRIDE_B=b1
+GOGOLFING_DOTENV_NO_OVERRIDE_A=a2
+GOGOLFING_DOTENV_NO_OVERRIDE_B=b2
+`
+	s := NewDefault()
+	plan, err := s.Plan(strings.NewReader(input))
+	if err != nil || len(plan.Unchanged()) != 0 {
+		t.Errorf("plan, err = %v, %v", plan, err)
+	}
+	if plan, err = s.Freeze().With(WithNoOverride()).Plan(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Unchanged()) != 2 || len(plan.Created()) != 1 || len(plan.Overwritten()) != 1 {
+		t.Errorf("plan.Entries = %v", plan.Entries)
+	}
+
+	if err := s.Source(strings.NewReader(input), WithNoOverride()); err != nil {
+		t.Fatal(err)
+	}
+	if s.NoOverride {
+		t.Error("per-call options must not change the Sourcer")
+	}
+	if a, b := os.Getenv("GOGOLFING_DOTENV_NO_OVERRIDE_A"), os.Getenv("GOGOLFING_DOTENV_NO_OVERRIDE_B"); a != "before" || b != "b2" {
+		t.Errorf("A, B = %q, %q", a, b)
+	}
+
+	if err := s.Source(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+	if a := os.Getenv("GOGOLFING_DOTENV_NO_OVERRIDE_A"); a != "a2" {
+		t.Errorf("A = %q", a)
+	}
+}
@@ -0,0 +1,160 @@
+package dotenv
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+//scanBufferSize is the initial size of the buffer lines are scanned into.
+const scanBufferSize = 4096
+
+//lineScanner contains the buffers that lines are read and scanned with, which are
+//reused between inputs.
+type lineScanner struct {
+	reader *bufio.Reader
+	buf    []byte
+}
+
+//newLineScanner returns a lineScanner with new buffers.
+func newLineScanner() *lineScanner {
+	return &lineScanner{
+		reader: bufio.NewReader(nil),
+		buf:    make([]byte, scanBufferSize),
+	}
+}
+
+//lineScanners contains the lineScanners used by scanLines.
+var lineScanners = sync.Pool{
+	New: func() interface{} {
+		return newLineScanner()
+	},
+}
+
+//scan does the work of scanLines with the buffers of ls.
+func (ls *lineScanner) scan(in io.Reader, fn func(lineNumber int, line string) error) error {
+	ls.reader.Reset(in)
+	//do not keep in reachable from ls once scanning is done.
+	defer ls.reader.Reset(nil)
+
+	if err := checkEncrypted(ls.reader); err != nil {
+		return err
+	}
+	//offset is the offset of the next line in the input, and lineOffset that
+	//of the line just scanned.
+	offset, lineOffset := 0, 0
+	lineNumber := 0
+	scanner := bufio.NewScanner(ls.reader)
+	scanner.Buffer(ls.buf, bufio.MaxScanTokenSize)
+	scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+		advance, token, err := bufio.ScanLines(data, atEOF)
+		if token != nil {
+			lineOffset = offset
+		}
+		offset += advance
+		return advance, token, err
+	})
+	for scanner.Scan() {
+		lineNumber++
+		line := scanner.Text()
+		if err := checkBinary(line, lineOffset, in); err != nil {
+			return err
+		}
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, byteOrderMark)
+		}
+		if err := fn(lineNumber, line); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != bufio.ErrTooLong {
+		return err
+	}
+	return &ErrSourcing{lineNumber + 1, ErrLineTooLong(bufio.MaxScanTokenSize)}
+}
+
+//Parser parses an input with a Sourcer, and can be Reset to parse another input
+//while reusing its buffers and state. This allows long-running processes that
+//parse many inputs to allocate less than they would with the methods of Sourcer.
+//A Parser is not safe for concurrent use, but multiple Parsers may share a
+//Sourcer.
+type Parser struct {
+	sourcer *Sourcer
+	in      io.Reader
+	scanner *lineScanner
+	defined map[string]string
+}
+
+//NewParser returns a Parser that parses in with s.
+func NewParser(s *Sourcer, in io.Reader) *Parser {
+	return &Parser{
+		sourcer: s,
+		in:      in,
+		scanner: newLineScanner(),
+		defined: map[string]string{},
+	}
+}
+
+//Reset discards any state of p and switches it to parse in, which allows p to be
+//reused instead of creating a new Parser.
+func (p *Parser) Reset(in io.Reader) {
+	p.in = in
+	for name := range p.defined {
+		delete(p.defined, name)
+	}
+}
+
+//Source is the same as Sourcer.Source for the input of p.
+func (p *Parser) Source() error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	st := p.sourcer.newSetter()
+	return p.visit(func(variable *Variable) error {
+		_, err := st.setenv(variable.Name, variable.Value)
+		return err
+	})
+}
+
+//AppendNameVars is the same as Sourcer.NameVars for the input of p except that the
+//name, value associations are appended to dst, which allows the caller to reuse
+//it. If an error occurs, then dst is returned with the associations found before
+//the error.
+func (p *Parser) AppendNameVars(dst [][2]string) ([][2]string, error) {
+	err := p.visit(func(variable *Variable) error {
+		dst = append(dst, [2]string{variable.Name, variable.Value})
+		return nil
+	})
+	return dst, err
+}
+
+//Variables is the same as Sourcer.Variables for the input of p.
+func (p *Parser) Variables() ([]*Variable, error) {
+	result := []*Variable{}
+	err := p.visit(func(variable *Variable) error {
+		result = append(result, variable)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+//visit does the work of sourceLineVisitorContext for the input of p with the
+//buffers and state of p.
+func (p *Parser) visit(visit func(variable *Variable) error) error {
+	s, in, dialectErr := p.sourcer.dialectFor(p.in)
+	if dialectErr != nil {
+		return dialectErr
+	}
+	return p.scanner.scan(in, func(lineNumber int, line string) error {
+		err := s.visitLine(context.Background(), lineNumber, line, p.defined, visit)
+		if err != nil {
+			return &ErrSourcing{lineNumber, err}
+		}
+		return nil
+	})
+}
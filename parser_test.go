@@ -0,0 +1,94 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParser(t *testing.T) {
+	s := NewSourcerWith(WithInterpolate(false))
+	p := NewParser(s, strings.NewReader("A=a\nB=${A}"))
+
+	nameVars, err := p.AppendNameVars(nil)
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}, {"B", "a"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+
+	p.Reset(strings.NewReader("C=${A}c"))
+	nameVars, err = p.AppendNameVars(nameVars[:0])
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"C", "c"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+
+	p.Reset(strings.NewReader("A=a\nB b\nC=c"))
+	nameVars, err = p.AppendNameVars(nil)
+	if err == nil || err.(*ErrSourcing).Line != 2 || !reflect.DeepEqual(nameVars, [][2]string{{"A", "a"}}) {
+		t.Errorf("nameVars, err = %v, %v", nameVars, err)
+	}
+
+	p.Reset(strings.NewReader("A=a\n\nB=b"))
+	variables, err := p.Variables()
+	if err != nil || !reflect.DeepEqual(variables, []*Variable{
+		{Name: "A", Value: "a", Line: 1},
+		{Name: "B", Value: "b", Line: 3},
+	}) {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+
+	p.Reset(strings.NewReader("\x00"))
+	if variables, err := p.Variables(); variables != nil || err == nil {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+}
+
+func TestParser_Source(t *testing.T) {
+	skipUnlessSetenv(t)
+	defer os.Unsetenv("GOGOLFING_DOTENV_PARSER")
+	p := NewParser(NewDefault(), strings.NewReader("GOGOLFING_DOTENV_PARSER=a"))
+	if err := p.Source(); err != nil || os.Getenv("GOGOLFING_DOTENV_PARSER") != "a" {
+		t.Errorf("Source() = %v", err)
+	}
+	p.Reset(strings.NewReader("GOGOLFING_DOTENV_PARSER=b"))
+	if err := p.Source(); err != nil || os.Getenv("GOGOLFING_DOTENV_PARSER") != "b" {
+		t.Errorf("Source() = %v", err)
+	}
+}
+
+func TestParser_allocations(t *testing.T) {
+	source := benchmarkSource(100)
+	s, in := NewDefault(), strings.NewReader(source)
+	p := NewParser(s, in)
+	nameVars := [][2]string{}
+	reused := testing.AllocsPerRun(10, func() {
+		in.Reset(source)
+		p.Reset(in)
+		nameVars, _ = p.AppendNameVars(nameVars[:0])
+	})
+	fresh := testing.AllocsPerRun(10, func() {
+		in.Reset(source)
+		s.NameVars(in)
+	})
+	if reused >= fresh {
+		t.Errorf("allocations: reused = %v, fresh = %v", reused, fresh)
+	}
+}
+
+func BenchmarkParser_AppendNameVars(b *testing.B) {
+	source := benchmarkSource(10000)
+	in := strings.NewReader(source)
+	p := NewParser(NewDefault(), in)
+	nameVars := [][2]string{}
+	b.SetBytes(int64(len(source)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		in.Reset(source)
+		p.Reset(in)
+		var err error
+		if nameVars, err = p.AppendNameVars(nameVars[:0]); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package dotenv
+
+import (
+	"io"
+	"os"
+)
+
+//PlanAction describes what applying a single PlanEntry would do to the process's
+//environment.
+type PlanAction int
+
+const (
+	//PlanCreate denotes a variable that is not currently set.
+	PlanCreate PlanAction = iota
+
+	//PlanOverwrite denotes a variable that is currently set to a different value.
+	PlanOverwrite
+
+	//PlanUnchanged denotes a variable that is currently set to the same value.
+	PlanUnchanged
+)
+
+//String returns a human readable description of a.
+func (a PlanAction) String() string {
+	switch a {
+	case PlanCreate:
+		return "create"
+	case PlanOverwrite:
+		return "overwrite"
+	case PlanUnchanged:
+		return "unchanged"
+	}
+	return "unknown"
+}
+
+//PlanEntry is a single variable definition found while planning.
+type PlanEntry struct {
+	//Line is the line number (1-based) that the variable was defined on.
+	Line int
+
+	//Name is the name of the variable.
+	Name string
+
+	//Old is the value of the variable before applying the entry.
+	//It is only meaningful if Action is PlanOverwrite or PlanUnchanged.
+	Old string
+
+	//New is the value the variable would be set to.
+	New string
+
+	//Action is what applying the entry would do.
+	Action PlanAction
+}
+
+//Plan is the result of parsing an input without setting any of its variables.
+//It allows client code to review the changes before calling Apply().
+type Plan struct {
+	//Entries contains all variable definitions in the order they were found.
+	Entries []*PlanEntry
+}
+
+//Plan attempts to parse all variable definitions from in and report what
+//s.Source() would do to the process's environment, without calling os.Setenv().
+//Entries are evaluated in order, so a variable defined twice in in is reported
+//against the value set by its previous definition.
+//If an error occurs while parsing, then that *ErrSourcing is returned.
+func (s *Sourcer) Plan(in io.Reader) (*Plan, error) {
+	plan := &Plan{Entries: []*PlanEntry{}}
+	pending := map[string]string{}
+	//preset contains the names that are left unchanged because of s.NoOverride.
+	preset := map[string]bool{}
+
+	err := s.sourceLineVisitor(in, func(line int, name, v string) error {
+		old, ok := pending[name]
+		if !ok {
+			old, ok = os.LookupEnv(name)
+			if ok && s.NoOverride {
+				preset[name] = true
+			}
+		}
+		if preset[name] {
+			v = old
+		}
+		entry := &PlanEntry{Line: line, Name: name, Old: old, New: v}
+		switch {
+		case !ok:
+			entry.Action = PlanCreate
+		case old != v:
+			entry.Action = PlanOverwrite
+		default:
+			entry.Action = PlanUnchanged
+		}
+		pending[name] = v
+		plan.Entries = append(plan.Entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return plan, nil
+}
+
+//Apply calls os.Setenv() for every entry in p in order.
+//If os.Setenv() errors, then an *ErrSourcing is returned for the entry's line and
+//no further entries are applied.
+func (p *Plan) Apply() error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	for _, entry := range p.Entries {
+		if err := os.Setenv(entry.Name, entry.New); err != nil {
+			return &ErrSourcing{entry.Line, err}
+		}
+	}
+	return nil
+}
+
+//Created returns all entries in p with Action PlanCreate.
+func (p *Plan) Created() []*PlanEntry {
+	return p.filter(PlanCreate)
+}
+
+//Overwritten returns all entries in p with Action PlanOverwrite.
+func (p *Plan) Overwritten() []*PlanEntry {
+	return p.filter(PlanOverwrite)
+}
+
+//Unchanged returns all entries in p with Action PlanUnchanged.
+func (p *Plan) Unchanged() []*PlanEntry {
+	return p.filter(PlanUnchanged)
+}
+
+//filter returns all entries in p with Action equal to action.
+func (p *Plan) filter(action PlanAction) []*PlanEntry {
+	result := []*PlanEntry{}
+	for _, entry := range p.Entries {
+		if entry.Action == action {
+			result = append(result, entry)
+		}
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPlanAction_String(t *testing.T) {
+	cases := map[PlanAction]string{
+		PlanCreate:     "create",
+		PlanOverwrite:  "overwrite",
+		PlanUnchanged:  "unchanged",
+		PlanAction(-1): "unknown",
+	}
+	for action, want := range cases {
+		if action.String() != want {
+			t.Errorf("%d.String() = %q WANT %q", action, action.String(), want)
+		}
+	}
+}
+
+func TestSourcer_Plan_success(t *testing.T) {
+	skipUnlessSetenv(t)
+	os.Unsetenv("GOGOLFING_DOTENV_PLAN_A")
+	os.Setenv("GOGOLFING_DOTENV_PLAN_B", "old")
+	os.Setenv("GOGOLFING_DOTENV_PLAN_C", "C")
+	defer os.Unsetenv("GOGOLFING_DOTENV_PLAN_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_PLAN_B")
+	defer os.Unsetenv("GOGOLFING_DOTENV_PLAN_C")
+
+	source := `GOGOLFING_DOTENV_PLAN_A=A
+GOGOLFING_DOTENV_PLAN_B=B
+GOGOLFING_DOTENV_PLAN_C=C
+
+GOGOLFING_DOTENV_PLAN_A=A2
+`
+	plan, err := NewDefault().Plan(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []*PlanEntry{
+		{1, "GOGOLFING_DOTENV_PLAN_A", "", "A", PlanCreate},
+		{2, "GOGOLFING_DOTENV_PLAN_B", "old", "B", PlanOverwrite},
+		{3, "GOGOLFING_DOTENV_PLAN_C", "C", "C", PlanUnchanged},
+		{5, "GOGOLFING_DOTENV_PLAN_A", "A", "A2", PlanOverwrite},
+	}
+	if !reflect.DeepEqual(plan.Entries, want) {
+		t.Errorf("plan.Entries = %v WANT %v", plan.Entries, want)
+	}
+	if len(plan.Created()) != 1 || len(plan.Overwritten()) != 2 || len(plan.Unchanged()) != 1 {
+		t.Fail()
+	}
+
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_PLAN_A"); ok {
+		t.Error("Plan() must not set variables")
+	}
+	if os.Getenv("GOGOLFING_DOTENV_PLAN_B") != "old" {
+		t.Error("Plan() must not overwrite variables")
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	if os.Getenv("GOGOLFING_DOTENV_PLAN_A") != "A2" || os.Getenv("GOGOLFING_DOTENV_PLAN_B") != "B" {
+		t.Fail()
+	}
+}
+
+func TestSourcer_Plan_error(t *testing.T) {
+	plan, err := NewDefault().Plan(strings.NewReader("a=b\nname"))
+	if plan != nil {
+		t.Fail()
+	}
+	if !reflect.DeepEqual(err, &ErrSourcing{2, ErrNonVariableLine("name")}) {
+		t.Errorf("err = %v", err)
+	}
+}
@@ -0,0 +1,97 @@
+package dotenv
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+//Policy is a function that checks the value of the variable name and returns a
+//non-nil error if it is not allowed. See Sourcer.Policy.
+type Policy func(name, value string) error
+
+//ErrPolicyViolation is an error that occurs when a variable's value is rejected by
+//a Policy. The value itself is not included since it may be a secret.
+type ErrPolicyViolation struct {
+	//Name is the name of the variable.
+	Name string
+
+	//Rule describes the rule that the value violates.
+	Rule string
+}
+
+//Error is the error implementation for ErrPolicyViolation.
+func (e *ErrPolicyViolation) Error() string {
+	return fmt.Sprintf("dotenv: value of %q violates policy: %v", e.Name, e.Rule)
+}
+
+//Policies returns a Policy that calls each of policies in order and returns the
+//first non-nil error.
+//Nil policies are skipped.
+func Policies(policies ...Policy) Policy {
+	return func(name, value string) error {
+		for _, policy := range policies {
+			if policy == nil {
+				continue
+			}
+			if err := policy(name, value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+//ForbidPattern returns a Policy that rejects values that contain a match of
+//pattern with an *ErrPolicyViolation whose Rule is rule.
+func ForbidPattern(rule string, pattern *regexp.Regexp) Policy {
+	return func(name, value string) error {
+		if pattern.MatchString(value) {
+			return &ErrPolicyViolation{name, rule}
+		}
+		return nil
+	}
+}
+
+//ForbidValues returns a Policy that rejects values that are equal to any of
+//values, ignoring case and surrounding whitespace, e.g. ForbidValues("changeme").
+func ForbidValues(values ...string) Policy {
+	forbidden := map[string]bool{}
+	for _, value := range values {
+		forbidden[strings.ToLower(strings.TrimSpace(value))] = true
+	}
+	return func(name, value string) error {
+		if forbidden[strings.ToLower(strings.TrimSpace(value))] {
+			return &ErrPolicyViolation{name, "forbidden value"}
+		}
+		return nil
+	}
+}
+
+//secretPatterns are the patterns of well known credential formats detected by
+//DetectSecrets.
+var secretPatterns = []struct {
+	rule    string
+	pattern *regexp.Regexp
+}{
+	{"AWS access key ID", regexp.MustCompile(`\b(AKIA|ASIA)[0-9A-Z]{16}\b`)},
+	{"GitHub token", regexp.MustCompile(`\b(gh[pousr]_[0-9A-Za-z]{36}|github_pat_[0-9A-Za-z_]{82})\b`)},
+	{"GitLab token", regexp.MustCompile(`\bglpat-[0-9A-Za-z_-]{20}\b`)},
+	{"Slack token", regexp.MustCompile(`\bxox[abprs]-[0-9A-Za-z-]{10,}`)},
+	{"Stripe live key", regexp.MustCompile(`\b[rs]k_live_[0-9A-Za-z]{16,}`)},
+	{"Google API key", regexp.MustCompile(`\bAIza[0-9A-Za-z_-]{35}`)},
+	{"private key", regexp.MustCompile(`-----BEGIN ([A-Z]+ )?PRIVATE KEY-----`)},
+}
+
+//DetectSecrets is a Policy that rejects values containing well known credential
+//formats, such as AWS access key IDs, GitHub, GitLab, and Slack tokens, Stripe
+//live keys, Google API keys, and PEM encoded private keys.
+//The Rule of the returned *ErrPolicyViolation names the detected format.
+func DetectSecrets(name, value string) error {
+	for _, secret := range secretPatterns {
+		if secret.pattern.MatchString(value) {
+			return &ErrPolicyViolation{name, secret.rule + " detected"}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+//Rules of the Problems reported by LintPortability.
+const (
+	RulePortableComment       = "portable-comment"
+	RulePortableEscape        = "portable-escape"
+	RulePortableQuote         = "portable-quote"
+	RulePortableInterpolation = "portable-interpolation"
+	RulePortableSpace         = "portable-space"
+)
+
+//LintPortability reports the variable definitions in d whose value, as parsed by
+//the Sourcer d was parsed with, differs from the value other popular
+//implementations parse from the same line. These are docker compose, godotenv,
+//Node's dotenv, and POSIX shells sourcing the file with "set -a".
+//It is intended for teams moving files between those implementations and this
+//package, and is a Linter. The rules are:
+//
+//	portable-comment: Comment starts a comment within an unquoted value without
+//	  preceding whitespace, e.g. A=b#c, while docker compose, godotenv, and
+//	  POSIX shells keep it in the value.
+//	portable-escape: a quoted value contains a backslash escape, e.g. "a\nb",
+//	  which Unquote interprets while Node's dotenv only expands \n and POSIX
+//	  shells keep most escapes literally.
+//	portable-quote: an unquoted value is surrounded by single quotes or
+//	  backticks, which other implementations remove but are kept here unless
+//	  they are the Sourcer's Quote.
+//	portable-interpolation: a value contains a reference such as $NAME, which
+//	  is kept literally here because Interpolate is false while docker compose,
+//	  godotenv, and POSIX shells expand it.
+//	portable-space: whitespace surrounds the equal sign, which POSIX shells do
+//	  not allow.
+//
+//At most one Problem is reported per rule and line.
+func LintPortability(d *Document) []*Problem {
+	s := d.sourcer
+	problems := []*Problem{}
+	for i, line := range d.lines {
+		//lines whose offsets are unknown are not in the syntax of the Sourcer.
+		if !line.isVariable || !line.hasSpan() {
+			continue
+		}
+		report := func(offset int, rule, format string, args ...interface{}) {
+			problems = append(problems, &Problem{
+				Location: Location{i + 1, offset + 1},
+				Name:     line.name,
+				Rule:     rule,
+				Message:  fmt.Sprintf(format, args...),
+			})
+		}
+		raw, valueEnd := line.raw, line.valueOffset+len(line.rawValue)
+
+		nameEnd := line.nameOffset + len(line.name)
+		if nameEnd <= line.valueOffset && strings.ContainsAny(raw[nameEnd:line.valueOffset], SpaceTab) {
+			report(nameEnd, RulePortableSpace,
+				"whitespace around the equal sign of %q is not allowed by POSIX shells", line.name)
+		}
+
+		if line.quoted {
+			if j := strings.IndexByte(line.rawValue, '\\'); j >= 0 {
+				report(line.valueOffset+j, RulePortableEscape,
+					"escape sequences in the value of %q are interpreted here, but only \\n is by Node's dotenv and few are by POSIX shells",
+					line.name)
+			}
+		} else if len(line.value) >= 2 && s.Quote != line.value[:1] && strings.Contains("'`", line.value[:1]) &&
+			line.value[0] == line.value[len(line.value)-1] {
+			report(line.valueOffset, RulePortableQuote,
+				"the %v quotes of the value of %q are part of the value here, but are removed by other implementations",
+				line.value[:1], line.name)
+		}
+
+		if line.hasComment && !line.quoted {
+			commentStart := len(raw) - len(line.comment) - len(s.Comment)
+			if commentStart == valueEnd && commentStart > line.valueOffset {
+				report(commentStart, RulePortableComment,
+					"%v after the value of %q starts a comment here, but is part of the value in docker compose, godotenv, and POSIX shells unless preceded by whitespace",
+					s.Comment, line.name)
+			}
+		}
+
+		if !s.Interpolate {
+			if j := referenceIndex(line.rawValue); j >= 0 && !(line.value != "" && line.value[0] == '\'') {
+				report(line.valueOffset+j, RulePortableInterpolation,
+					"the reference in the value of %q is kept literally here, but is expanded by docker compose, godotenv, and POSIX shells",
+					line.name)
+			}
+		}
+	}
+	return problems
+}
+
+//referenceIndex returns the index of the first dollar sign in v that starts a
+//reference, i.e. that is followed by a brace or the first byte of a name, or -1.
+func referenceIndex(v string) int {
+	for i := 0; i+1 < len(v); i++ {
+		if v[i] == '$' && (v[i+1] == '{' || isNameByte(v[i+1], true)) {
+			return i
+		}
+	}
+	return -1
+}
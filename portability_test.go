@@ -0,0 +1,64 @@
+package dotenv
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLintPortability(t *testing.T) {
+	source := `A=b#c
+B=b #c
+C="a\tb" #c
+D='single'
+E=` + "`tick`" + `
+F=$HOME/bin
+G=${HOME}
+H=cost $5
+I='$HOME'
+J=plain
+K="quoted"
+`
+	d, err := NewSourcerWith(WithQuotedComments()).ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := []string{}
+	for _, problem := range LintPortability(d) {
+		rules = append(rules, fmt.Sprintf("%v:%v %v %v", problem.Location.Line, problem.Location.Column, problem.Name, problem.Rule))
+	}
+	want := []string{
+		"1:4 A portable-comment",
+		"3:5 C portable-escape",
+		"4:3 D portable-quote",
+		"5:3 E portable-quote",
+		"6:3 F portable-interpolation",
+		"7:3 G portable-interpolation",
+		"9:3 I portable-quote",
+	}
+	if !reflect.DeepEqual(rules, want) {
+		t.Errorf("rules = %v WANT %v", rules, want)
+	}
+}
+
+func TestLintPortability_sourcer(t *testing.T) {
+	s := NewDefault()
+	s.SpaceAroundEqual = true
+	s.Interpolate = true
+	s.Quote = "'"
+	d, err := s.ParseDocument(strings.NewReader("A = $B\nC='c'\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	problems := LintPortability(d)
+	want := []*Problem{{
+		Location: Location{1, 2},
+		Name:     "A",
+		Rule:     RulePortableSpace,
+		Message:  `whitespace around the equal sign of "A" is not allowed by POSIX shells`,
+	}}
+	if !reflect.DeepEqual(problems, want) {
+		t.Errorf("problems = %v", problems)
+	}
+}
@@ -0,0 +1,147 @@
+package dotenv
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//AutoPort is the port that a PortAllocator replaces with a free local port.
+const AutoPort = "auto"
+
+//PortAllocator allocates free local TCP ports for values whose port is AutoPort,
+//so that environments running in parallel, e.g. for tests, do not need
+//hard-coded ports. Its Transform method is a Transform. See WithAutoPorts. The
+//values it replaces are:
+//
+//	PORT=auto                          the port, e.g. 51234
+//	REDIS_ADDR=localhost:auto          the port of a host, e.g. localhost:51234
+//	API_URL=http://localhost:auto/v1   the port of a URL, e.g. http://localhost:51234/v1
+//
+//A value that is only AutoPort is replaced only if its name is PORT or ends with
+//_PORT, so that values such as COLOR=auto are left unchanged. A value of the form
+//host:auto that is not a URL is replaced only if host is empty, localhost, an IP
+//address, or a domain name containing a dot, or if its name ends with _ADDR,
+//_ADDRESS, or _HOST, so that values such as MODE=scale:auto are left unchanged.
+//
+//The port of a name is allocated once, so it is the same in every Env loaded with
+//the same PortAllocator, e.g. when a Runner reloads, and URLs may reference it
+//with interpolation. Ports of different names are different.
+//Ports are free when they are allocated but not reserved, so another process may
+//take them before they are used.
+//
+//The zero value allocates ports on 127.0.0.1.
+type PortAllocator struct {
+	//Host is the host whose free ports are allocated. It defaults to 127.0.0.1.
+	Host string
+
+	mu    sync.Mutex
+	ports map[string]int
+	used  map[int]bool
+}
+
+//WithAutoPorts adds the Transform of a to the Transforms of the Sourcer, as with
+//WithTransform.
+func WithAutoPorts(a *PortAllocator) Option {
+	return WithTransform(a.Transform)
+}
+
+//Transform returns value with its AutoPort replaced with the port allocated for
+//name, or value unchanged if it does not have AutoPort as its port as described
+//by PortAllocator.
+//If no port can be allocated, then that error is returned.
+func (a *PortAllocator) Transform(name, value string) (string, error) {
+	i := autoPortIndex(name, value)
+	if i < 0 {
+		return value, nil
+	}
+	port, err := a.Port(name)
+	if err != nil {
+		return "", err
+	}
+	return value[:i] + strconv.Itoa(port) + value[i+len(AutoPort):], nil
+}
+
+//autoPortIndex returns the index of the AutoPort in the value of name that a
+//PortAllocator replaces, or -1 if there is none.
+func autoPortIndex(name, value string) int {
+	if value == AutoPort {
+		if name == "PORT" || strings.HasSuffix(name, "_PORT") {
+			return 0
+		}
+		return -1
+	}
+	start, authority := 0, value
+	if i := strings.Index(value, "://"); i > 0 {
+		start = i + len("://")
+		authority = value[start:]
+		if j := strings.IndexAny(authority, "/?#"); j >= 0 {
+			authority = authority[:j]
+		}
+	}
+	host := strings.TrimSuffix(authority, ":"+AutoPort)
+	if host == authority {
+		return -1
+	}
+	if start == 0 && !isAddressName(name) && !isHostName(host) {
+		return -1
+	}
+	return start + len(host) + 1
+}
+
+//isAddressName determines whether or not name is the name of a network address.
+func isAddressName(name string) bool {
+	for _, suffix := range []string{"_ADDR", "_ADDRESS", "_HOST"} {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+//isHostName determines whether or not host is clearly the host of a network
+//address, i.e. empty, localhost, an IP address, or a domain name containing a
+//dot.
+func isHostName(host string) bool {
+	if host == "" || host == "localhost" || net.ParseIP(strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")) != nil {
+		return true
+	}
+	for i := 0; i < len(host); i++ {
+		if c := host[i]; !isNameByte(c, false) && c != '.' && c != '-' {
+			return false
+		}
+	}
+	return strings.Contains(strings.Trim(host, "."), ".")
+}
+
+//Port returns the port allocated for name, allocating a free port if there is
+//none yet.
+func (a *PortAllocator) Port(name string) (int, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if port, ok := a.ports[name]; ok {
+		return port, nil
+	}
+	if a.ports == nil {
+		a.ports, a.used = map[string]int{}, map[int]bool{}
+	}
+	host := a.Host
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	for i := 0; i < 10; i++ {
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, "0"))
+		if err != nil {
+			return 0, err
+		}
+		port := listener.Addr().(*net.TCPAddr).Port
+		listener.Close()
+		if !a.used[port] {
+			a.ports[name], a.used[port] = port, true
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("dotenv: no free port on %v for %q", host, name)
+}
@@ -0,0 +1,77 @@
+package dotenv
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPortAllocator_Transform(t *testing.T) {
+	a := &PortAllocator{}
+	port, err := a.Port("PORT")
+	if err != nil {
+		t.Skip("cannot listen:", err)
+	}
+
+	tests := []struct {
+		name, value, want string
+	}{
+		{"PORT", "auto", "{port}"},
+		{"PORT", "localhost:auto", "localhost:{port}"},
+		{"PORT", "http://localhost:auto/v1?a=auto", "http://localhost:{port}/v1?a=auto"},
+		{"PORT", "postgres://user:auto@db:auto", "postgres://user:auto@db:{port}"},
+		{"PORT", "8080", "8080"},
+		{"PORT", "automatic", "automatic"},
+		{"PORT", "http://localhost/auto", "http://localhost/auto"},
+		{"PORT", "/bin:auto", "/bin:auto"},
+		{"ADMIN_PORT", "auto", "{port}"},
+		{"API_ADDR", ":auto", ":{port}"},
+		{"API_ADDR", "api.example.com:auto", "api.example.com:{port}"},
+		{"API_ADDR", "127.0.0.1:auto", "127.0.0.1:{port}"},
+		{"API_ADDR", "[::1]:auto", "[::1]:{port}"},
+		{"REDIS_HOST", "redis:auto", "redis:{port}"},
+		{"URL", "redis://redis:auto", "redis://redis:{port}"},
+		{"COLOR", "auto", "auto"},
+		{"CARGO_TERM_COLOR", "auto", "auto"},
+		{"PORTS", "auto", "auto"},
+		{"MODE", "scale:auto", "scale:auto"},
+		{"MODE", "a b:auto", "a b:auto"},
+		{"MODE", "x.y/z:auto", "x.y/z:auto"},
+	}
+	for _, test := range tests {
+		port, _ := a.Port(test.name)
+		want := strings.Replace(test.want, "{port}", strconv.Itoa(port), 1)
+		if v, err := a.Transform(test.name, test.value); v != want || err != nil {
+			t.Errorf("Transform(%q, %q) = %q, %v, want %q", test.name, test.value, v, err, want)
+		}
+	}
+
+	other, err := a.Port("ADMIN_PORT")
+	if err != nil || other == port {
+		t.Errorf("Port(ADMIN_PORT) = %v, %v", other, err)
+	}
+}
+
+func TestWithAutoPorts(t *testing.T) {
+	a := &PortAllocator{}
+	if _, err := a.Port("PORT"); err != nil {
+		t.Skip("cannot listen:", err)
+	}
+	s := NewSourcerWith(WithInterpolate(false), WithAutoPorts(a))
+	env, err := s.Env(strings.NewReader("PORT=auto\nURL=http://localhost:$PORT\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	port, _ := env.Get("PORT")
+	if url, _ := env.Get("URL"); port == "auto" || url != "http://localhost:"+port {
+		t.Errorf("PORT, URL = %v, %v", port, url)
+	}
+
+	env, err = s.Env(strings.NewReader("PORT=auto\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := env.Get("PORT"); again != port {
+		t.Errorf("PORT = %v, want %v", again, port)
+	}
+}
@@ -0,0 +1,117 @@
+package dotenv
+
+import (
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+//The functions in this file map positions within a Document between the byte
+//based Locations used by this package and the units used by editors, so that
+//tools such as language servers can report Problems and hovers precisely.
+//Offsets are within the text of a Document as returned from String, with all
+//lines written, i.e. as if CollapseBlankLines were false.
+
+//Offset returns the byte offset (0-based) of location in the text of d, and
+//whether or not location is within d. A Column one past the end of a line is
+//within d.
+func (d *Document) Offset(location Location) (int, bool) {
+	if location.Line < 1 || location.Line > len(d.lines) {
+		return 0, false
+	}
+	raw := d.lines[location.Line-1].raw
+	if location.Column < 1 || location.Column > len(raw)+1 {
+		return 0, false
+	}
+	offset, eol := 0, len(d.lineEnding())
+	for _, line := range d.lines[:location.Line-1] {
+		offset += len(line.raw) + eol
+	}
+	return offset + location.Column - 1, true
+}
+
+//LocationAt returns the Location of the byte offset (0-based) in the text of d,
+//and whether or not offset is within d. Offsets within a line ending are at the
+//end of their line.
+func (d *Document) LocationAt(offset int) (Location, bool) {
+	if offset < 0 {
+		return Location{}, false
+	}
+	eol := len(d.lineEnding())
+	for i, line := range d.lines {
+		if offset <= len(line.raw) {
+			return Location{i + 1, offset + 1}, true
+		}
+		if offset < len(line.raw)+eol {
+			return Location{i + 1, len(line.raw) + 1}, true
+		}
+		offset -= len(line.raw) + eol
+	}
+	return Location{}, false
+}
+
+//RuneColumn returns the column (1-based) of location counted in runes instead of
+//bytes, and whether or not location is within d.
+func (d *Document) RuneColumn(location Location) (int, bool) {
+	prefix, ok := d.linePrefix(location)
+	if !ok {
+		return 0, false
+	}
+	return utf8.RuneCountInString(prefix) + 1, true
+}
+
+//UTF16Column returns the column (1-based) of location counted in UTF-16 code
+//units, as used by the Language Server Protocol, and whether or not location is
+//within d. Note that the Language Server Protocol numbers lines and columns from
+//0.
+func (d *Document) UTF16Column(location Location) (int, bool) {
+	prefix, ok := d.linePrefix(location)
+	if !ok {
+		return 0, false
+	}
+	return len(utf16.Encode([]rune(prefix))) + 1, true
+}
+
+//RuneLocation returns the Location of the rune column (1-based) in line, and
+//whether or not it is within d.
+func (d *Document) RuneLocation(line, column int) (Location, bool) {
+	return d.locationOf(line, column, func(r rune) int { return 1 })
+}
+
+//UTF16Location returns the Location of the UTF-16 column (1-based) in line, and
+//whether or not it is within d. A column within a surrogate pair is at the start
+//of its rune.
+func (d *Document) UTF16Location(line, column int) (Location, bool) {
+	return d.locationOf(line, column, utf16.RuneLen)
+}
+
+//linePrefix returns the text of the line of location that precedes it.
+func (d *Document) linePrefix(location Location) (string, bool) {
+	if _, ok := d.Offset(location); !ok {
+		return "", false
+	}
+	return d.lines[location.Line-1].raw[:location.Column-1], true
+}
+
+//locationOf returns the Location of column in line, where column is counted in
+//units whose number per rune is returned from width.
+func (d *Document) locationOf(line, column int, width func(r rune) int) (Location, bool) {
+	if line < 1 || line > len(d.lines) || column < 1 {
+		return Location{}, false
+	}
+	raw, units := d.lines[line-1].raw, 1
+	for i, r := range raw {
+		w := width(r)
+		if w < 1 {
+			//invalid UTF-8 and unencodable runes count as one unit.
+			w = 1
+		}
+		if column < units+w {
+			return Location{line, i + 1}, true
+		}
+		units += w
+	}
+	if column == units {
+		return Location{line, len(raw) + 1}, true
+	}
+	return Location{}, false
+}
@@ -0,0 +1,71 @@
+package dotenv
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDocument_positions(t *testing.T) {
+	input := "A=é\r\nB=😀x\r\n\r\nC=c\r\n"
+	d, err := NewDefault().ParseDocument(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := d.String()
+	if text != input {
+		t.Fatalf("d = %q", text)
+	}
+
+	tests := []struct {
+		location  Location
+		offset    int
+		runes     int
+		utf16     int
+		withinDoc bool
+	}{
+		{Location{1, 1}, 0, 1, 1, true},
+		{Location{1, 3}, 2, 3, 3, true},
+		{Location{1, 5}, 4, 4, 4, true},
+		{Location{2, 3}, 8, 3, 3, true},
+		{Location{2, 7}, 12, 4, 5, true},
+		{Location{2, 8}, 13, 5, 6, true},
+		{Location{3, 1}, 15, 1, 1, true},
+		{Location{4, 3}, 19, 3, 3, true},
+		{Location{4, 5}, 0, 0, 0, false},
+		{Location{5, 1}, 0, 0, 0, false},
+		{Location{0, 1}, 0, 0, 0, false},
+	}
+	for _, test := range tests {
+		offset, ok := d.Offset(test.location)
+		if offset != test.offset || ok != test.withinDoc {
+			t.Errorf("Offset(%v) = %v, %v", test.location, offset, ok)
+		}
+		runes, _ := d.RuneColumn(test.location)
+		utf16, _ := d.UTF16Column(test.location)
+		if runes != test.runes || utf16 != test.utf16 {
+			t.Errorf("RuneColumn(%v), UTF16Column(%v) = %v, %v", test.location, test.location, runes, utf16)
+		}
+		if !test.withinDoc {
+			continue
+		}
+		if location, ok := d.LocationAt(offset); location != test.location || !ok {
+			t.Errorf("LocationAt(%v) = %v, %v", offset, location, ok)
+		}
+		if location, ok := d.RuneLocation(test.location.Line, runes); location != test.location || !ok {
+			t.Errorf("RuneLocation(%v, %v) = %v, %v", test.location.Line, runes, location, ok)
+		}
+		if location, ok := d.UTF16Location(test.location.Line, utf16); location != test.location || !ok {
+			t.Errorf("UTF16Location(%v, %v) = %v, %v", test.location.Line, utf16, location, ok)
+		}
+	}
+
+	if location, ok := d.LocationAt(5); location != (Location{1, 5}) || !ok {
+		t.Errorf("LocationAt(5) = %v, %v", location, ok)
+	}
+	if location, ok := d.UTF16Location(2, 4); location != (Location{2, 3}) || !ok {
+		t.Errorf("UTF16Location(2, 4) = %v, %v", location, ok)
+	}
+	if _, ok := d.LocationAt(len(text) + 1); ok {
+		t.Error("LocationAt() past the end must not be within d")
+	}
+}
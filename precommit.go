@@ -0,0 +1,84 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+//ErrPreCommit is an error that occurs when Checker.PreCommit finds problems. Its
+//value is the number of staged files with problems.
+type ErrPreCommit int
+
+//Error is the error implementation for ErrPreCommit.
+func (e ErrPreCommit) Error() string {
+	return fmt.Sprintf("dotenv: %d staged file(s) have problems", int(e))
+}
+
+//IsEnvFile determines whether or not the base name of path is that of an
+//environment file, i.e. ".env", ".env.SUFFIX" such as ".env.example", or
+//"NAME.env".
+func IsEnvFile(path string) bool {
+	base := filepath.Base(path)
+	return base == ".env" || strings.HasPrefix(base, ".env.") || (strings.HasSuffix(base, ".env") && len(base) > len(".env"))
+}
+
+//PreCommit checks, with c, the contents staged in the git repository at dir of
+//every staged environment file, as determined by IsEnvFile, that is added,
+//copied, modified, or renamed. It is intended to be run from a git pre-commit
+//hook, so that files that do not parse, contain plaintext secrets (with an
+//EntropyDetector in c.Linters), or do not match c.Example are not committed.
+//
+//Problems are written to w, one per line, in the form
+//"PATH:LINE:COLUMN: RULE: MESSAGE", where PATH is relative to dir. If any are
+//found, then an ErrPreCommit is returned. If git fails, then its error is
+//returned.
+func (c *Checker) PreCommit(dir string, w io.Writer) error {
+	output, err := git(dir, "diff", "--cached", "--name-only", "--relative", "-z", "--diff-filter=ACMR")
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" || !IsEnvFile(path) {
+			continue
+		}
+		staged, err := git(dir, "show", ":./"+path)
+		if err != nil {
+			return err
+		}
+		err = c.check(path, bytes.NewReader(staged))
+		report, ok := err.(*CheckReport)
+		if err != nil && !ok {
+			return err
+		}
+		if ok {
+			failed++
+			for _, problem := range report.Problems {
+				fmt.Fprintf(w, "%v:%v\n", path, problem)
+			}
+		}
+	}
+	if failed > 0 {
+		return ErrPreCommit(failed)
+	}
+	return nil
+}
+
+//git runs git with args in dir and returns its standard output. The returned
+//error includes the standard error of git.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("dotenv: git %v: %v: %v", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+	return output, nil
+}
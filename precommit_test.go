@@ -0,0 +1,79 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestIsEnvFile(t *testing.T) {
+	tests := map[string]bool{
+		".env":             true,
+		"dir/.env.example": true,
+		"prod.env":         true,
+		".envrc":           false,
+		"env":              false,
+		"main.go":          false,
+	}
+	for path, want := range tests {
+		if got := IsEnvFile(path); got != want {
+			t.Errorf("IsEnvFile(%q) = %v", path, got)
+		}
+	}
+}
+
+func TestChecker_PreCommit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := git(dir, "init", "-q"); err != nil {
+		t.Skip(err)
+	}
+
+	write(".env.example", "A=\nB=\n")
+	write(".env", "A=a\nB=b\n")
+	write("prod.env", "A=a\n")
+	write("notes.txt", "not parsed\n")
+	if _, err := git(dir, "add", "."); err != nil {
+		t.Fatal(err)
+	}
+	//unstaged changes are not checked.
+	write("prod.env", "A=a\nB=b\n")
+
+	c := &Checker{Example: filepath.Join(dir, ".env.example"), Linters: []Linter{NewEntropyDetector().Lint}}
+	out := &bytes.Buffer{}
+	if err := c.PreCommit(dir, out); err != ErrPreCommit(1) {
+		t.Errorf("err = %v", err)
+	}
+	if lines := strings.Split(strings.TrimSpace(out.String()), "\n"); len(lines) != 1 || !strings.HasPrefix(lines[0], "prod.env:0:0: "+RuleExampleMissing+": ") {
+		t.Errorf("out = %q", out.String())
+	}
+
+	if _, err := git(dir, "add", "prod.env"); err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	if err := c.PreCommit(dir, out); err != nil || out.Len() != 0 {
+		t.Errorf("err = %v, out = %q", err, out.String())
+	}
+
+	if err := c.PreCommit(filepath.Join(dir, "missing"), out); err == nil {
+		t.Error("err = nil")
+	}
+}
@@ -0,0 +1,79 @@
+package dotenv
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+//ErrUnknownPreset is an error that occurs when a preset that is not registered is
+//requested from Preset. Its value is the preset's name.
+type ErrUnknownPreset string
+
+//Error is the error implementation for ErrUnknownPreset.
+func (e ErrUnknownPreset) Error() string {
+	return fmt.Sprintf("dotenv: unknown preset %q", string(e))
+}
+
+//presets contains the registered presets by name. It is guarded by presetsMu.
+var (
+	presetsMu sync.RWMutex
+	presets   = map[string][]Option{
+		"default": nil,
+		"docker":  {WithPassThrough(false)},
+		"compose": {WithPassThrough(false), WithInterpolate(false)},
+		"shell":   {WithExport(DefaultExport, true), WithInterpolate(false)},
+		"lenient": {WithLenient()},
+	}
+)
+
+//RegisterPreset makes a named configuration available through Preset, so that
+//applications can select it by name, e.g. from a flag or a configuration file.
+//The configuration is that of NewSourcerWith(opts...).
+//The built in presets are:
+//
+//	default: the syntax of NewDefault().
+//	docker:  default with PassThrough, as in docker --env-file.
+//	compose: docker with Interpolate, as in docker compose environment files.
+//	shell:   default with EmptyExport and Interpolate, as in POSIX shells.
+//	lenient: default with the options of WithLenient().
+//
+//RegisterPreset panics if name is empty or already registered.
+//It is safe for concurrent use with Preset and Presets.
+func RegisterPreset(name string, opts ...Option) {
+	presetsMu.Lock()
+	defer presetsMu.Unlock()
+	if name == "" {
+		panic("dotenv: RegisterPreset with an empty name")
+	}
+	if _, ok := presets[name]; ok {
+		panic(fmt.Sprintf("dotenv: RegisterPreset called twice for %q", name))
+	}
+	presets[name] = append([]Option(nil), opts...)
+}
+
+//Preset returns a new Sourcer with the configuration registered as name.
+//Every call returns a new Sourcer that can be changed without affecting the
+//preset.
+//If name is not registered, then an ErrUnknownPreset is returned.
+func Preset(name string) (*Sourcer, error) {
+	presetsMu.RLock()
+	opts, ok := presets[name]
+	presetsMu.RUnlock()
+	if !ok {
+		return nil, ErrUnknownPreset(name)
+	}
+	return NewSourcerWith(opts...), nil
+}
+
+//Presets returns the names of all registered presets in sorted order.
+func Presets() []string {
+	presetsMu.RLock()
+	defer presetsMu.RUnlock()
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
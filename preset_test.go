@@ -0,0 +1,79 @@
+package dotenv
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPreset(t *testing.T) {
+	os.Setenv("GOGOLFING_DOTENV_PRESET", "env")
+	defer os.Unsetenv("GOGOLFING_DOTENV_PRESET")
+
+	tests := []struct {
+		name     string
+		source   string
+		nameVars [][2]string
+		err      bool
+	}{
+		{"default", "A=a\nB=$A", [][2]string{{"A", "a"}, {"B", "$A"}}, false},
+		{"default", "GOGOLFING_DOTENV_PRESET", nil, true},
+		{"docker", "GOGOLFING_DOTENV_PRESET\nB=$A", [][2]string{{"GOGOLFING_DOTENV_PRESET", "env"}, {"B", "$A"}}, false},
+		{"compose", "A=a\nGOGOLFING_DOTENV_PRESET\nB=$A", [][2]string{{"A", "a"}, {"GOGOLFING_DOTENV_PRESET", "env"}, {"B", "a"}}, false},
+		{"shell", "export\nA=a\nexport B=$A", [][2]string{{"A", "a"}, {"B", "a"}}, false},
+		{"lenient", "A = a\nexport", [][2]string{{"A", "a"}}, false},
+	}
+	for i, test := range tests {
+		s, err := Preset(test.name)
+		if err != nil {
+			t.Fatalf("%v: Preset() = %v", i, err)
+		}
+		nameVars, err := s.NameVars(strings.NewReader(test.source))
+		if (err != nil) != test.err || (!test.err && !reflect.DeepEqual(nameVars, test.nameVars)) {
+			t.Errorf("%v: nameVars, err = %v, %v want %v", i, nameVars, err, test.nameVars)
+		}
+	}
+}
+
+func TestPreset_unknown(t *testing.T) {
+	s, err := Preset("unknown")
+	if s != nil || err != ErrUnknownPreset("unknown") {
+		t.Errorf("Preset() = %v, %v", s, err)
+	}
+	if err.Error() != `dotenv: unknown preset "unknown"` {
+		t.Errorf("Error() = %v", err)
+	}
+}
+
+func TestRegisterPreset(t *testing.T) {
+	RegisterPreset("test-semicolon", WithComment(";"))
+	defer func() {
+		presetsMu.Lock()
+		delete(presets, "test-semicolon")
+		presetsMu.Unlock()
+	}()
+
+	s, err := Preset("test-semicolon")
+	if err != nil || s.Comment != ";" {
+		t.Fatalf("Preset() = %v, %v", s, err)
+	}
+	s.Comment = "#"
+	if s, _ := Preset("test-semicolon"); s.Comment != ";" {
+		t.Error("Preset() must return a new Sourcer")
+	}
+	if names := Presets(); !reflect.DeepEqual(names, []string{"compose", "default", "docker", "lenient", "shell", "test-semicolon"}) {
+		t.Errorf("Presets() = %v", names)
+	}
+
+	for _, name := range []string{"", "docker"} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("RegisterPreset(%q) did not panic", name)
+				}
+			}()
+			RegisterPreset(name)
+		}()
+	}
+}
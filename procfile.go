@@ -0,0 +1,78 @@
+package dotenv
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+//Process is a single process type of a Procfile, as used by foreman and
+//Heroku.
+type Process struct {
+	//Name is the name of the process type, e.g. "web".
+	Name string
+
+	//Command is the command line that runs the process, which is run by a
+	//shell.
+	Command string
+}
+
+//ErrInvalidProcess is a line error that occurs when a line of a Procfile is not
+//of the form "name: command".
+type ErrInvalidProcess string
+
+//Error is the error implementation for ErrInvalidProcess.
+func (e ErrInvalidProcess) Error() string {
+	return fmt.Sprintf("line does not contain a process %q", string(e))
+}
+
+//ErrDuplicateProcess is a line error that occurs when a Procfile defines a
+//process more than once.
+type ErrDuplicateProcess string
+
+//Error is the error implementation for ErrDuplicateProcess.
+func (e ErrDuplicateProcess) Error() string {
+	return fmt.Sprintf("process %q is already defined", string(e))
+}
+
+//ParseProcfile parses the processes of the Procfile in, in order. Every line is
+//of the form "name: command", where name consists of letters, digits,
+//underscores, and hyphens, except for empty lines and comments starting with #.
+//If a line is invalid or defines a name again, then an *ErrSourcing with an
+//ErrInvalidProcess or ErrDuplicateProcess is returned.
+func ParseProcfile(in io.Reader) ([]*Process, error) {
+	processes := []*Process{}
+	seen := map[string]bool{}
+	scanner := bufio.NewScanner(in)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.Trim(scanner.Text(), SpaceTab+"\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		colon := strings.IndexByte(line, ':')
+		if colon <= 0 || !isProcessName(line[:colon]) {
+			return nil, &ErrSourcing{lineNumber, ErrInvalidProcess(line)}
+		}
+		name, command := line[:colon], strings.Trim(line[colon+1:], SpaceTab)
+		if command == "" {
+			return nil, &ErrSourcing{lineNumber, ErrInvalidProcess(line)}
+		}
+		if seen[name] {
+			return nil, &ErrSourcing{lineNumber, ErrDuplicateProcess(name)}
+		}
+		seen[name] = true
+		processes = append(processes, &Process{name, command})
+	}
+	return processes, scanner.Err()
+}
+
+//isProcessName determines whether or not name is a valid name of a Process.
+func isProcessName(name string) bool {
+	for i := 0; i < len(name); i++ {
+		if !isNameByte(name[i], false) && name[i] != '-' {
+			return false
+		}
+	}
+	return true
+}
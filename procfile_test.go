@@ -0,0 +1,38 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseProcfile(t *testing.T) {
+	input := "# processes\nweb: bundle exec rails s -p $PORT\r\n\nworker-1:  sidekiq -q a:b \nrelease_task:rake db:migrate\n"
+	processes, err := ParseProcfile(strings.NewReader(input))
+	want := []*Process{
+		{"web", "bundle exec rails s -p $PORT"},
+		{"worker-1", "sidekiq -q a:b"},
+		{"release_task", "rake db:migrate"},
+	}
+	if err != nil || !reflect.DeepEqual(processes, want) {
+		t.Errorf("processes, err = %v, %v", processes, err)
+	}
+}
+
+func TestParseProcfile_errors(t *testing.T) {
+	tests := []struct {
+		input string
+		err   error
+	}{
+		{"web: a\nworker\n", &ErrSourcing{2, ErrInvalidProcess("worker")}},
+		{"web:\n", &ErrSourcing{1, ErrInvalidProcess("web:")}},
+		{": a\n", &ErrSourcing{1, ErrInvalidProcess(": a")}},
+		{"web server: a\n", &ErrSourcing{1, ErrInvalidProcess("web server: a")}},
+		{"web: a\n\nweb: b\n", &ErrSourcing{3, ErrDuplicateProcess("web")}},
+	}
+	for _, test := range tests {
+		if _, err := ParseProcfile(strings.NewReader(test.input)); !reflect.DeepEqual(err, test.err) {
+			t.Errorf("ParseProcfile(%q) error = %v, want %v", test.input, err, test.err)
+		}
+	}
+}
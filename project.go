@@ -0,0 +1,502 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ProjectFile is the name of the file that configures a project, which is
+//searched for by FindProject.
+const ProjectFile = ".dotenvrc"
+
+//ErrUnknownProjectKey is an error that occurs when a project configuration
+//defines a name that is not one of its keys. Its value is the name.
+type ErrUnknownProjectKey string
+
+//Error is the error implementation for ErrUnknownProjectKey.
+func (e ErrUnknownProjectKey) Error() string {
+	return fmt.Sprintf("dotenv: unknown project configuration key %q", string(e))
+}
+
+//ErrUnknownLinter is an error that occurs when a project configuration names a
+//linter that does not exist. Its value is the linter's name.
+type ErrUnknownLinter string
+
+//Error is the error implementation for ErrUnknownLinter.
+func (e ErrUnknownLinter) Error() string {
+	return fmt.Sprintf("dotenv: unknown linter %q", string(e))
+}
+
+//projectLinters contains the linters that can be named in a project
+//configuration, by name.
+var projectLinters = map[string]func() Linter{
+	"deprecated":  func() Linter { return LintDeprecated },
+	"entropy":     func() Linter { return NewEntropyDetector().Lint },
+	"expiry":      func() Linter { return LintExpiry(time.Now(), 0) },
+	"portability": func() Linter { return LintPortability },
+}
+
+//ErrUnknownResolver is an error that occurs when a project configuration names a
+//Resolver that does not exist. Its value is the Resolver's scheme.
+type ErrUnknownResolver string
+
+//Error is the error implementation for ErrUnknownResolver.
+func (e ErrUnknownResolver) Error() string {
+	return fmt.Sprintf("dotenv: unknown resolver %q", string(e))
+}
+
+//projectResolvers contains the functions returning the Resolvers that can be
+//enabled in a project configuration for a Project, by scheme.
+var projectResolvers = map[string]func(p *Project) Resolver{
+	"now":      func(p *Project) Resolver { return ResolveNow },
+	"hostname": func(p *Project) Resolver { return ResolveHostname },
+}
+
+//Project is the configuration shared by every tool that works with the
+//environment files of a project, so that they all parse the files identically.
+//It is read from a ProjectFile at the root of the project, which is itself an
+//environment file, parsed with NewDefault(), that may define the keys:
+//
+//	PRESET: the name of the preset files are parsed with. See Preset.
+//	FILES:  the environment files in layering order, where later files
+//	        override earlier ones. "{env}" in a path is replaced with the
+//	        selected environment, and paths containing it are skipped if no
+//	        environment is selected.
+//	ENVIRONMENT:     the environment that is selected by default.
+//	ENVIRONMENT_VAR: the variable of the process's environment that selects
+//	        the environment instead, DOTENV_ENV by default.
+//	PROFILE_NAME_FILES: the files of the profile named name, the lower case
+//	        of NAME, which replace FILES when the profile is selected.
+//	PROFILE_NAME_SET: the variables of the form VAR=VALUE that override the
+//	        files when the profile is selected.
+//	SCHEMA: the file whose comments define the Schema of the variables, such
+//	        as .env.example. See Document.Schema.
+//	LINT:   the linters files are checked with, of deprecated, entropy,
+//	        expiry, and portability.
+//	RESOLVERS: the Resolvers that are enabled by their schemes, of now,
+//	        hostname, and gitsha, which is unavailable with TinyGo and reads
+//	        the repository of the project's directory. See ResolveNow,
+//	        ResolveHostname, and GitSHAResolver.
+//	AUTO_PORTS: whether or not values with a port of AutoPort, e.g. PORT=auto,
+//	        are replaced with free local ports. See PortAllocator.
+//
+//Lists are separated by whitespace or commas, and paths are relative to the
+//directory of the ProjectFile, e.g.
+//
+//	PRESET=compose
+//	FILES=".env .env.{env} .env.local"
+//	SCHEMA=.env.example
+//	LINT=entropy,expiry
+//	PROFILE_WORKER_FILES=".env .env.worker"
+//	PROFILE_WORKER_SET="QUEUE=jobs CONCURRENCY=4"
+type Project struct {
+	//Dir is the directory of the project, which contains its ProjectFile.
+	Dir string
+
+	//Preset is the name of the preset files are parsed with. It defaults to
+	//"default".
+	Preset string
+
+	//Files contains the paths of the environment files in layering order. It
+	//defaults to ".env".
+	Files []string
+
+	//Schema is the path of the file that defines the Schema, or empty if the
+	//project has no Schema.
+	Schema string
+
+	//Lint contains the names of the linters files are checked with.
+	Lint []string
+
+	//Environment is the environment that is selected if the variable
+	//EnvironmentVar is not set in the process's environment.
+	Environment string
+
+	//EnvironmentVar is the name of the variable that selects the environment.
+	//It defaults to DefaultEnvironmentVar.
+	EnvironmentVar string
+
+	//Profiles contains the profiles of the project by name. See WithProfile.
+	Profiles map[string]*Profile
+
+	//Overrides contains the names and values of variables that override those
+	//defined in the files when the Project is loaded, in order. It is set by
+	//WithProfile.
+	Overrides [][2]string
+
+	//Resolvers contains the schemes of the Resolvers that are enabled.
+	Resolvers []string
+
+	//Ports allocates the ports of values with a port of AutoPort if it is not
+	//nil. It is shared by copies of the Project, so that ports are stable
+	//across loads.
+	Ports *PortAllocator
+}
+
+//Profile is a named configuration of a Project for a single command, such as
+//a worker process, so that several commands run from the same directory can use
+//different files and variables.
+type Profile struct {
+	//Files replaces the Files of the Project if it is not nil.
+	Files []string
+
+	//Overrides contains the names and values of variables that override those
+	//defined in the files, in order.
+	Overrides [][2]string
+}
+
+//ErrUnknownProfile is an error that occurs when a Project does not have a
+//requested profile. Its value is the profile's name.
+type ErrUnknownProfile string
+
+//Error is the error implementation for ErrUnknownProfile.
+func (e ErrUnknownProfile) Error() string {
+	return fmt.Sprintf("dotenv: unknown profile %q", string(e))
+}
+
+//DefaultEnvironmentVar is the default EnvironmentVar of a Project.
+const DefaultEnvironmentVar = "DOTENV_ENV"
+
+//newProject returns a Project in dir with the defaults for all keys.
+func newProject(dir string) *Project {
+	return &Project{
+		Dir:            dir,
+		Preset:         "default",
+		Files:          []string{".env"},
+		EnvironmentVar: DefaultEnvironmentVar,
+		Profiles:       map[string]*Profile{},
+	}
+}
+
+//FindProject returns the Project whose ProjectFile is in dir or the closest of
+//its parent directories. If there is no such file, then the returned Project
+//has the defaults for all keys and its Dir is dir.
+//If a ProjectFile cannot be read or is invalid, then that error is returned.
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for search := dir; ; {
+		path := filepath.Join(search, ProjectFile)
+		if _, err := os.Stat(path); err == nil {
+			return ReadProject(path)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+		parent := filepath.Dir(search)
+		if parent == search {
+			break
+		}
+		search = parent
+	}
+	return newProject(dir), nil
+}
+
+//ReadProject reads the Project whose ProjectFile is at path.
+//If a key is unknown, then an ErrUnknownProjectKey is returned, and if the
+//preset or a linter does not exist, then an ErrUnknownPreset or an
+//ErrUnknownLinter is returned.
+func ReadProject(path string) (*Project, error) {
+	variables, err := NewDefault().VariablesFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := newProject(filepath.Dir(path))
+	for _, variable := range variables {
+		switch name, v := variable.Name, variable.Value; name {
+		case "PRESET":
+			p.Preset = v
+		case "FILES":
+			p.Files = splitList(v)
+		case "SCHEMA":
+			p.Schema = v
+		case "LINT":
+			p.Lint = splitList(v)
+		case "ENVIRONMENT":
+			p.Environment = v
+		case "ENVIRONMENT_VAR":
+			p.EnvironmentVar = v
+		case "RESOLVERS":
+			p.Resolvers = splitList(v)
+		case "AUTO_PORTS":
+			autoPorts, err := strconv.ParseBool(v)
+			if err != nil {
+				return nil, fmt.Errorf("dotenv: %v: %w", name, err)
+			}
+			if autoPorts {
+				p.Ports = &PortAllocator{}
+			}
+		default:
+			if err := p.setProfileKey(name, v); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if _, err := Preset(p.Preset); err != nil {
+		return nil, err
+	}
+	if _, err := p.Linters(); err != nil {
+		return nil, err
+	}
+	for _, scheme := range p.Resolvers {
+		if _, ok := projectResolvers[scheme]; !ok {
+			return nil, ErrUnknownResolver(scheme)
+		}
+	}
+	return p, nil
+}
+
+//setProfileKey sets the profile key name of the form PROFILE_NAME_FILES or
+//PROFILE_NAME_SET to v, or returns an ErrUnknownProjectKey if name is not a
+//profile key.
+func (p *Project) setProfileKey(name, v string) error {
+	key := strings.TrimPrefix(name, "PROFILE_")
+	i := strings.LastIndexByte(key, '_')
+	if key == name || i <= 0 {
+		return ErrUnknownProjectKey(name)
+	}
+	profileName := strings.ToLower(key[:i])
+	profile, ok := p.Profiles[profileName]
+	if !ok {
+		profile = &Profile{}
+		p.Profiles[profileName] = profile
+	}
+	switch key[i+1:] {
+	case "FILES":
+		profile.Files = splitList(v)
+	case "SET":
+		for _, item := range splitList(v) {
+			j := strings.IndexByte(item, '=')
+			if j <= 0 {
+				return fmt.Errorf("dotenv: %v: %q is not of the form VAR=VALUE", name, item)
+			}
+			profile.Overrides = append(profile.Overrides, [2]string{item[:j], item[j+1:]})
+		}
+	default:
+		return ErrUnknownProjectKey(name)
+	}
+	return nil
+}
+
+//WithProfile returns a copy of p with the profile name selected, i.e. whose
+//Files are those of the profile, if it has any, and whose Overrides are those
+//of the profile. An empty name returns p unchanged.
+//If p does not have the profile, then an ErrUnknownProfile is returned.
+func (p *Project) WithProfile(name string) (*Project, error) {
+	if name == "" {
+		return p, nil
+	}
+	profile, ok := p.Profiles[name]
+	if !ok {
+		return nil, ErrUnknownProfile(name)
+	}
+	result := *p
+	if profile.Files != nil {
+		result.Files = profile.Files
+	}
+	result.Overrides = profile.Overrides
+	return &result, nil
+}
+
+//splitList splits v at whitespace and commas.
+func splitList(v string) []string {
+	return strings.FieldsFunc(v, func(r rune) bool {
+		return r == ',' || strings.ContainsRune(SpaceTab+"\n", r)
+	})
+}
+
+//Path returns path relative to p.Dir, unless it is absolute.
+func (p *Project) Path(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(p.Dir, path)
+}
+
+//SelectedEnvironment returns the value of p.EnvironmentVar in the process's
+//environment if it is not empty, and p.Environment otherwise.
+func (p *Project) SelectedEnvironment() string {
+	if environment := os.Getenv(p.EnvironmentVar); environment != "" {
+		return environment
+	}
+	return p.Environment
+}
+
+//Paths returns the paths of p.Files relative to p.Dir for the selected
+//environment. See SelectedEnvironment.
+func (p *Project) Paths() []string {
+	environment := p.SelectedEnvironment()
+	paths := make([]string, 0, len(p.Files))
+	for _, file := range p.Files {
+		if strings.Contains(file, environmentPlaceholder) {
+			if environment == "" {
+				continue
+			}
+			file = strings.Replace(file, environmentPlaceholder, environment, -1)
+		}
+		paths = append(paths, p.Path(file))
+	}
+	return paths
+}
+
+//ExistingPaths returns the paths returned from Paths() whose files exist.
+func (p *Project) ExistingPaths() ([]string, error) {
+	paths := []string{}
+	for _, path := range p.Paths() {
+		if _, err := os.Stat(path); err == nil {
+			paths = append(paths, path)
+		} else if !os.IsNotExist(err) {
+			return nil, err
+		}
+	}
+	return paths, nil
+}
+
+//environmentPlaceholder is replaced with the selected environment in the Files
+//of a Project.
+const environmentPlaceholder = "{env}"
+
+//LoadProject returns the effective Env of the Project found by FindProject(dir),
+//as returned from Project.Load.
+func LoadProject(dir string) (*Env, error) {
+	p, err := FindProject(dir)
+	if err != nil {
+		return nil, err
+	}
+	return p.Load()
+}
+
+//Load parses the files of p that exist, as returned from ExistingPaths(), into
+//a single Env with EnvFiles, adds p.Overrides to it, and validates it against
+//the Schema of p, if any.
+//It is intended to replace the code that services run at startup to find and check
+//their configuration.
+//Files that do not exist are skipped, so that optional layers such as
+//.env.local need not be present. If a required variable of the Schema is not
+//defined, then an ErrMissingVariables is returned, and if a value is invalid or
+//cannot be resolved, then that error is returned. Defaults of the Schema are
+//not added to the Env.
+func (p *Project) Load() (*Env, error) {
+	s, err := p.Sourcer()
+	if err != nil {
+		return nil, err
+	}
+	paths, err := p.ExistingPaths()
+	if err != nil {
+		return nil, err
+	}
+	env, err := s.EnvFiles(paths...)
+	if err != nil {
+		return nil, err
+	}
+	for _, override := range p.Overrides {
+		env.addValue(override[0], override[1], 0)
+	}
+
+	schema, err := p.LoadSchema()
+	if err != nil || schema == nil {
+		return env, err
+	}
+	var getErr error
+	_, err = schema.Resolve(func(name string) (string, bool) {
+		value, err := env.Get(name)
+		if _, missing := err.(ErrMissingVariables); err != nil && !missing && getErr == nil {
+			getErr = err
+		}
+		return value, err == nil
+	})
+	if getErr != nil {
+		return nil, getErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return env, nil
+}
+
+//Sourcer returns a new Sourcer with the configuration of p.Preset and the
+//Resolvers of p.Resolvers, which allocates ports with p.Ports if it is not nil.
+func (p *Project) Sourcer() (*Sourcer, error) {
+	s, err := Preset(p.Preset)
+	if err != nil {
+		return nil, err
+	}
+	for _, scheme := range p.Resolvers {
+		if resolver, ok := projectResolvers[scheme]; ok {
+			WithResolver(scheme, resolver(p))(s)
+		}
+	}
+	if p.Ports != nil {
+		WithAutoPorts(p.Ports)(s)
+	}
+	return s, nil
+}
+
+//LoadSchema parses the Schema from p.Schema with p.Sourcer(). It returns nil if
+//p.Schema is empty.
+func (p *Project) LoadSchema() (*Schema, error) {
+	if p.Schema == "" {
+		return nil, nil
+	}
+	s, err := p.Sourcer()
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(p.Path(p.Schema))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	d, err := s.ParseDocument(file)
+	if err != nil {
+		return nil, err
+	}
+	return d.Schema()
+}
+
+//Linters returns the linters named by p.Lint, or an ErrUnknownLinter.
+func (p *Project) Linters() ([]Linter, error) {
+	linters := make([]Linter, 0, len(p.Lint))
+	for _, name := range p.Lint {
+		linter, ok := projectLinters[name]
+		if !ok {
+			return nil, ErrUnknownLinter(name)
+		}
+		linters = append(linters, linter())
+	}
+	return linters, nil
+}
+
+//Checker returns a Checker with the Sourcer, Schema, and Linters of p.
+func (p *Project) Checker() (*Checker, error) {
+	s, err := p.Sourcer()
+	if err != nil {
+		return nil, err
+	}
+	schema, err := p.LoadSchema()
+	if err != nil {
+		return nil, err
+	}
+	linters, err := p.Linters()
+	if err != nil {
+		return nil, err
+	}
+	return &Checker{Sourcer: s, Schema: schema, Linters: linters}, nil
+}
+
+//ProjectLinters returns the names of the linters that can be named in a
+//project configuration, in sorted order.
+func ProjectLinters() []string {
+	names := make([]string, 0, len(projectLinters))
+	for name := range projectLinters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,224 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestFindProject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sub := filepath.Join(dir, "a", "b")
+	os.MkdirAll(sub, 0700)
+
+	p, err := FindProject(sub)
+	if err != nil || p.Dir != sub || p.Preset != "default" || !reflect.DeepEqual(p.Files, []string{".env"}) {
+		t.Errorf("p, err = %v, %v", p, err)
+	}
+
+	config := "#project\nPRESET=compose\nFILES=\".env, .env.local /etc/app.env\"\nSCHEMA=.env.example\nLINT=entropy,expiry\n"
+	ioutil.WriteFile(filepath.Join(dir, ProjectFile), []byte(config), 0600)
+	ioutil.WriteFile(filepath.Join(dir, ".env.example"), []byte("#@required\nA=\n"), 0600)
+	p, err = FindProject(sub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Project{dir, "compose", []string{".env", ".env.local", "/etc/app.env"}, ".env.example", []string{"entropy", "expiry"}, "", DefaultEnvironmentVar, map[string]*Profile{}, nil, nil, nil}
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("p = %v", p)
+	}
+	if paths := p.Paths(); !reflect.DeepEqual(paths, []string{filepath.Join(dir, ".env"), filepath.Join(dir, ".env.local"), "/etc/app.env"}) {
+		t.Errorf("Paths() = %v", paths)
+	}
+	c, err := p.Checker()
+	if err != nil || !c.Sourcer.Interpolate || len(c.Linters) != 2 || len(c.Schema.Vars) != 1 || !c.Schema.Vars[0].Required {
+		t.Errorf("c, err = %v, %v", c, err)
+	}
+}
+
+func TestReadProject_invalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ProjectFile)
+
+	tests := []struct {
+		config string
+		err    error
+	}{
+		{"PRESETS=default\n", ErrUnknownProjectKey("PRESETS")},
+		{"PRESET=unknown\n", ErrUnknownPreset("unknown")},
+		{"LINT=entropy spelling\n", ErrUnknownLinter("spelling")},
+		{"PROFILE_WORKER=a\n", ErrUnknownProjectKey("PROFILE_WORKER")},
+		{"RESOLVERS=now,cmd\n", ErrUnknownResolver("cmd")},
+		{"PROFILE_WORKER_ENV=a\n", ErrUnknownProjectKey("PROFILE_WORKER_ENV")},
+	}
+	for _, test := range tests {
+		ioutil.WriteFile(path, []byte(test.config), 0600)
+		if _, err := ReadProject(path); err != test.err {
+			t.Errorf("ReadProject(%q) error = %v, want %v", test.config, err, test.err)
+		}
+	}
+}
+
+func TestLoadProject(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(ProjectFile, "PRESET=compose\nFILES=.env .env.{env} .env.local\nSCHEMA=.env.example\nENVIRONMENT=test\nENVIRONMENT_VAR=GOGOLFING_DOTENV_UNSET_ENVIRONMENT\n")
+	write(".env.example", "#@required\nA=\n#@type int\nPORT=\n")
+	write(".env", "A=a\nPORT=80\n")
+	write(".env.test", "PORT=8080\nURL=http://$A:$PORT\n")
+
+	env, err := LoadProject(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := env.Names(); !reflect.DeepEqual(names, []string{"A", "PORT", "URL"}) {
+		t.Errorf("Names() = %v", names)
+	}
+	if v, err := env.Get("URL"); v != "http://a:8080" || err != nil {
+		t.Errorf("Get(URL) = %v, %v", v, err)
+	}
+
+	write(".env.local", "PORT=http\n")
+	if _, err := LoadProject(dir); err == nil {
+		t.Error("an invalid value must fail validation")
+	}
+	write(".env.local", "")
+	write(".env", "PORT=80\n")
+	if _, err := LoadProject(dir); !reflect.DeepEqual(err, ErrMissingVariables{"A"}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestProject_WithProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	write := func(name, contents string) {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(ProjectFile, "FILES=.env\nPROFILE_WORKER_FILES=.env .env.worker\nPROFILE_WORKER_SET=\"QUEUE=jobs, B=\"\nPROFILE_WEB_SET=PORT=80\n")
+	write(".env", "A=a\nB=b\n")
+	write(".env.worker", "QUEUE=default\n")
+
+	p, err := ReadProject(filepath.Join(dir, ProjectFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*Profile{
+		"worker": {[]string{".env", ".env.worker"}, [][2]string{{"QUEUE", "jobs"}, {"B", ""}}},
+		"web":    {nil, [][2]string{{"PORT", "80"}}},
+	}
+	if !reflect.DeepEqual(p.Profiles, want) {
+		t.Errorf("Profiles = %v", p.Profiles)
+	}
+
+	if _, err := p.WithProfile("db"); err != ErrUnknownProfile("db") {
+		t.Errorf("WithProfile(db) error = %v", err)
+	}
+	if same, err := p.WithProfile(""); same != p || err != nil {
+		t.Errorf("WithProfile() = %v, %v", same, err)
+	}
+
+	tests := []struct {
+		profile string
+		want    [][2]string
+	}{
+		{"worker", [][2]string{{"A", "a"}, {"B", ""}, {"QUEUE", "jobs"}}},
+		{"web", [][2]string{{"A", "a"}, {"B", "b"}, {"PORT", "80"}}},
+	}
+	for _, test := range tests {
+		profile, err := p.WithProfile(test.profile)
+		if err != nil {
+			t.Fatal(err)
+		}
+		env, err := profile.Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := [][2]string{}
+		for _, name := range env.Names() {
+			v, err := env.Get(name)
+			if err != nil {
+				t.Fatal(err)
+			}
+			result = append(result, [2]string{name, v})
+		}
+		if !reflect.DeepEqual(result, test.want) {
+			t.Errorf("%v: result = %v, want %v", test.profile, result, test.want)
+		}
+	}
+	if len(p.Files) != 1 || p.Overrides != nil {
+		t.Error("WithProfile must not change p")
+	}
+
+	write(ProjectFile, "PROFILE_WORKER_SET=QUEUE\n")
+	if _, err := ReadProject(filepath.Join(dir, ProjectFile)); err == nil {
+		t.Error("an override without an equal sign must fail")
+	}
+}
+
+func TestLoadProject_autoPorts(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	ioutil.WriteFile(filepath.Join(dir, ProjectFile), []byte("AUTO_PORTS=true\n"), 0600)
+	ioutil.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=auto\n"), 0600)
+
+	p, err := ReadProject(filepath.Join(dir, ProjectFile))
+	if err != nil || p.Ports == nil {
+		t.Fatalf("p, err = %v, %v", p, err)
+	}
+	env, err := p.Load()
+	if err != nil {
+		t.Skip("cannot allocate a port:", err)
+	}
+	port, _ := env.Get("PORT")
+	if _, err := strconv.Atoi(port); err != nil {
+		t.Errorf("PORT = %q", port)
+	}
+	if env, err = p.Load(); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := env.Get("PORT"); again != port {
+		t.Errorf("PORT = %v, want %v", again, port)
+	}
+
+	ioutil.WriteFile(filepath.Join(dir, ProjectFile), []byte("AUTO_PORTS=maybe\n"), 0600)
+	if _, err := ReadProject(filepath.Join(dir, ProjectFile)); err == nil {
+		t.Error("an invalid boolean must fail")
+	}
+}
+
+func TestProject_Sourcer_resolvers(t *testing.T) {
+	p := newProject(".")
+	p.Resolvers = []string{"now", "hostname"}
+	s, err := p.Sourcer()
+	if err != nil || s.Resolvers["now"] == nil || s.Resolvers["hostname"] == nil || len(s.Resolvers) != 2 {
+		t.Errorf("s, err = %v, %v", s, err)
+	}
+}
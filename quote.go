@@ -0,0 +1,172 @@
+package dotenv
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+//shellSafe contains all characters, other than letters and digits, that do not
+//need to be quoted in a POSIX shell word.
+const shellSafe = "@%+=:,./-_"
+
+//ShellQuote returns value quoted such that a POSIX shell interprets the result
+//as the single word value.
+//If value is non-empty and contains only letters, digits, and characters that are
+//not special to the shell, then value is returned unchanged.
+//Otherwise value is surrounded by single quotes and each single quote within value
+//is replaced with '\''.
+func ShellQuote(value string) string {
+	if len(value) > 0 && strings.IndexFunc(value, isShellUnsafe) < 0 {
+		return value
+	}
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+//isShellUnsafe determines whether or not r needs quoting in a POSIX shell word.
+func isShellUnsafe(r rune) bool {
+	if r <= unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+		return false
+	}
+	return !strings.ContainsRune(shellSafe, r)
+}
+
+//MaybeQuote returns value as it should appear after the equal sign of a variable
+//definition parsed by a Sourcer returned from NewDefault().
+//If value would be parsed unchanged without quotes, then it is returned unchanged.
+//Otherwise value is quoted with strconv.Quote(), the inverse of DefaultQuote and
+//strconv.Unquote().
+func MaybeQuote(value string) string {
+	if needsQuote(value, DefaultComment, DefaultQuote) {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+//needsQuote determines whether or not value must be quoted to be parsed unchanged
+//by a Sourcer with comment and quote as its Comment and Quote.
+func needsQuote(value, comment, quote string) bool {
+	if len(value) == 0 {
+		return false
+	}
+	if strings.TrimLeft(value, SpaceTab) != value || strings.TrimRight(value, SpaceTab) != value {
+		return true
+	}
+	if comment != "" && strings.Contains(value, comment) {
+		return true
+	}
+	if quote != "" && strings.HasPrefix(value, quote) {
+		return true
+	}
+	return strings.IndexFunc(value, func(r rune) bool {
+		return r != ' ' && r != '\t' && !unicode.IsPrint(r)
+	}) >= 0 || !utf8.ValidString(value)
+}
+
+//Unquote interprets s as a double quoted Go string literal, returning the string
+//value that s quotes, in the same way as strconv.Unquote() does for such
+//literals. Strings that are not double quoted cause strconv.ErrSyntax.
+//Unquote is an alternative to strconv.Unquote() for values quoted with
+//DefaultQuote, and is the Unquote of NewDefault() in TinyGo builds.
+func Unquote(s string) (string, error) {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return "", strconv.ErrSyntax
+	}
+	s = s[1 : len(s)-1]
+	buf := make([]byte, 0, len(s))
+	for len(s) > 0 {
+		c := s[0]
+		switch {
+		case c == '"' || c == '\n':
+			return "", strconv.ErrSyntax
+		case c >= utf8.RuneSelf:
+			r, size := utf8.DecodeRuneInString(s)
+			buf = utf8.AppendRune(buf, r)
+			s = s[size:]
+		case c != '\\':
+			buf = append(buf, c)
+			s = s[1:]
+		default:
+			var err error
+			if buf, s, err = unquoteEscape(buf, s); err != nil {
+				return "", err
+			}
+		}
+	}
+	return string(buf), nil
+}
+
+//escapes contains the characters of single character escape sequences and the
+//bytes they denote.
+var escapes = map[byte]byte{
+	'a': '\a', 'b': '\b', 'f': '\f', 'n': '\n', 'r': '\r', 't': '\t', 'v': '\v',
+	'\\': '\\', '"': '"',
+}
+
+//unquoteEscape appends the value of the escape sequence at the beginning of s to
+//buf, and returns the remainder of s after the escape sequence.
+func unquoteEscape(buf []byte, s string) ([]byte, string, error) {
+	if len(s) < 2 {
+		return nil, "", strconv.ErrSyntax
+	}
+	c := s[1]
+	if b, ok := escapes[c]; ok {
+		return append(buf, b), s[2:], nil
+	}
+
+	digits, base := 0, uint32(16)
+	switch c {
+	case 'x':
+		digits = 2
+	case 'u':
+		digits = 4
+	case 'U':
+		digits = 8
+	case '0', '1', '2', '3', '4', '5', '6', '7':
+		digits, base = 3, 8
+	default:
+		return nil, "", strconv.ErrSyntax
+	}
+	start := 2
+	if base == 8 {
+		start = 1
+	}
+	if len(s) < start+digits {
+		return nil, "", strconv.ErrSyntax
+	}
+	value := uint32(0)
+	for i := start; i < start+digits; i++ {
+		digit, ok := digitValue(s[i])
+		if !ok || digit >= base {
+			return nil, "", strconv.ErrSyntax
+		}
+		value = value*base + digit
+	}
+	s = s[start+digits:]
+
+	switch c {
+	case 'u', 'U':
+		if !utf8.ValidRune(rune(value)) {
+			return nil, "", strconv.ErrSyntax
+		}
+		return utf8.AppendRune(buf, rune(value)), s, nil
+	}
+	if value > 255 {
+		return nil, "", strconv.ErrSyntax
+	}
+	return append(buf, byte(value)), s, nil
+}
+
+//digitValue returns the value of the hexadecimal digit c.
+func digitValue(c byte) (uint32, bool) {
+	switch {
+	case '0' <= c && c <= '9':
+		return uint32(c - '0'), true
+	case 'a' <= c && c <= 'f':
+		return uint32(c-'a') + 10, true
+	case 'A' <= c && c <= 'F':
+		return uint32(c-'A') + 10, true
+	}
+	return 0, false
+}
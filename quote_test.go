@@ -0,0 +1,94 @@
+package dotenv
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestShellQuote(t *testing.T) {
+	cases := map[string]string{
+		"":             "''",
+		"abc":          "abc",
+		"/usr/bin:a.b": "/usr/bin:a.b",
+		"a b":          "'a b'",
+		"it's":         `'it'\''s'`,
+		"$HOME":        "'$HOME'",
+		"a\nb":         "'a\nb'",
+		"héllo":        "'héllo'",
+	}
+	for value, want := range cases {
+		if result := ShellQuote(value); result != want {
+			t.Errorf("ShellQuote(%q) = %q WANT %q", value, result, want)
+		}
+	}
+}
+
+func TestMaybeQuote(t *testing.T) {
+	cases := map[string]string{
+		"":          "",
+		"abc":       "abc",
+		"a b":       "a b",
+		`a"b`:       `a"b`,
+		" a":        `" a"`,
+		"a\t":       `"a\t"`,
+		"a#b":       `"a#b"`,
+		`"a`:        `"\"a"`,
+		"a\nb":      `"a\nb"`,
+		"\xff":      `"\xff"`,
+		"héllo":     "héllo",
+		`back\path`: `back\path`,
+	}
+	for value, want := range cases {
+		if result := MaybeQuote(value); result != want {
+			t.Errorf("MaybeQuote(%q) = %q WANT %q", value, result, want)
+		}
+	}
+}
+
+func TestMaybeQuote_roundTrip(t *testing.T) {
+	s := NewDefault()
+	values := []string{"", "abc", " a ", "a#b", `"a"`, "a\nb", "\x00\xff", "héllo wörld"}
+	for _, value := range values {
+		_, v, err := s.NameVar("name=" + MaybeQuote(value))
+		if err != nil || v != value {
+			t.Errorf("NameVar(MaybeQuote(%q)) = %q, %v", value, v, err)
+		}
+	}
+}
+
+//unquoteSeeds are double quoted literals that Unquote must handle like
+//strconv.Unquote().
+var unquoteSeeds = []string{
+	`""`, `"abc"`, `"a\nb\t\"c\"\\"`, `"\a\b\f\r\v"`, `"\x41\xff"`, `"\101\377"`,
+	`"\400"`, `"\u00e9\U0001F600"`, `"\ud800"`, `"\'"`, `"\q"`, `"\x4"`, `"a"b"`, `"a`,
+	`'a'`, "`a`", "\"a\nb\"", "\"\xff\"", "\"h\u00e9llo\"", `"\`,
+}
+
+func TestUnquote(t *testing.T) {
+	for _, seed := range unquoteSeeds {
+		testUnquote(t, seed)
+	}
+}
+
+func FuzzUnquote(f *testing.F) {
+	for _, seed := range unquoteSeeds {
+		f.Add(seed)
+	}
+	f.Fuzz(testUnquote)
+}
+
+//testUnquote checks that Unquote(s) is the same as strconv.Unquote(s) if s is
+//double quoted, and an error otherwise.
+func testUnquote(t *testing.T, s string) {
+	value, err := Unquote(s)
+	if len(s) == 0 || s[0] != '"' {
+		if err != strconv.ErrSyntax {
+			t.Errorf("Unquote(%q) = %q, %v", s, value, err)
+		}
+		return
+	}
+	want, wantErr := strconv.Unquote(s)
+	if value != want || err != wantErr {
+		t.Errorf("Unquote(%q) = %q, %v WANT %q, %v", s, value, err, want, wantErr)
+	}
+}
@@ -0,0 +1,182 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+//FileRename is the change that a RenamePlan makes to a single file.
+type FileRename struct {
+	//Path is the path of the file.
+	Path string
+
+	//Old and New are the contents of the file before and after the rename.
+	Old, New string
+
+	//Skipped contains the locations of references that are not changed, as
+	//returned from Document.Rename.
+	Skipped []Location
+}
+
+//Diff returns the change to r.Path in the unified diff format, with a hunk for
+//every changed line. Renaming never adds or removes lines, so every hunk replaces
+//a single line.
+func (r *FileRename) Diff() string {
+	buf := &strings.Builder{}
+	fmt.Fprintf(buf, "--- %v\n+++ %v\n", r.Path, r.Path)
+	oldLines, newLines := strings.Split(r.Old, "\n"), strings.Split(r.New, "\n")
+	for i := 0; i < len(oldLines) && i < len(newLines); i++ {
+		if oldLines[i] != newLines[i] {
+			fmt.Fprintf(buf, "@@ -%d +%d @@\n-%v\n+%v\n", i+1, i+1,
+				strings.TrimSuffix(oldLines[i], "\r"), strings.TrimSuffix(newLines[i], "\r"))
+		}
+	}
+	return buf.String()
+}
+
+//RenamePlan is the set of changes that renaming a variable makes to files, as
+//returned from Sourcer.RenameFiles. Nothing is written until Apply is called,
+//so a RenamePlan can be previewed with Diff as a dry run.
+type RenamePlan struct {
+	//Files contains the files that change or contain skipped references, in the
+	//order they were given.
+	Files []*FileRename
+}
+
+//Diff returns the diffs of all files in p, as returned from FileRename.Diff.
+func (p *RenamePlan) Diff() string {
+	buf := &strings.Builder{}
+	for _, file := range p.Files {
+		buf.WriteString(file.Diff())
+	}
+	return buf.String()
+}
+
+//Apply writes the changes of p to all of its files, such that either all of them
+//are changed or none of them are.
+//The new contents are first written to temporary files next to the files, which
+//then replace them. If a file no longer has the contents it had when p was
+//planned, or a temporary file cannot be written, then an error is returned and
+//no file is changed. If replacing a file fails, then the files already replaced
+//are restored with their old contents.
+func (p *RenamePlan) Apply() error {
+	temps := make([]string, 0, len(p.Files))
+	defer func() {
+		for _, temp := range temps {
+			os.Remove(temp)
+		}
+	}()
+	for _, file := range p.Files {
+		temp, err := writeRenameTemp(file)
+		if err != nil {
+			return err
+		}
+		temps = append(temps, temp)
+	}
+
+	for i, file := range p.Files {
+		if err := os.Rename(temps[i], file.Path); err != nil {
+			for _, done := range p.Files[:i] {
+				os.WriteFile(done.Path, []byte(done.Old), 0600)
+			}
+			return err
+		}
+	}
+	temps = nil
+	return nil
+}
+
+//writeRenameTemp checks that the file of r still contains r.Old, and writes r.New
+//to a temporary file in the same directory with the same permissions. It returns
+//the path of the temporary file.
+func writeRenameTemp(r *FileRename) (string, error) {
+	current, err := os.ReadFile(r.Path)
+	if err != nil {
+		return "", err
+	}
+	if !bytes.Equal(current, []byte(r.Old)) {
+		return "", fmt.Errorf("dotenv: %v changed since the rename was planned", r.Path)
+	}
+	info, err := os.Stat(r.Path)
+	if err != nil {
+		return "", err
+	}
+	temp, err := os.CreateTemp(filepath.Dir(r.Path), "."+filepath.Base(r.Path)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+	_, err = temp.WriteString(r.New)
+	if err == nil {
+		err = temp.Chmod(info.Mode().Perm())
+	}
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(temp.Name())
+		return "", err
+	}
+	return temp.Name(), nil
+}
+
+//RenameFiles plans renaming the variable oldName to newName in every file that
+//matches one of patterns, which are paths or filepath.Match() patterns, e.g.
+//".env*". Definitions of oldName and, if s.Interpolate is true, references to it
+//are changed as by Document.Rename, except that references before the first
+//definition in a file, or in files that do not define oldName, are changed too,
+//since they refer to the definition in another of the files.
+//The files are parsed as Documents, so their formatting and line endings are
+//kept. Files that neither change nor contain skipped references are not part of
+//the returned RenamePlan, and files matched by more than one pattern are only
+//renamed once.
+//If a pattern is malformed, a file cannot be read or parsed, or newName is
+//invalid or already defined in one of the files, then an error is returned and
+//nothing is planned.
+func (s *Sourcer) RenameFiles(oldName, newName string, patterns ...string) (*RenamePlan, error) {
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if matches == nil {
+			//a path without a match is read so that its error is returned.
+			matches = []string{pattern}
+		}
+		for _, path := range matches {
+			if !seen[path] {
+				seen[path] = true
+				paths = append(paths, path)
+			}
+		}
+	}
+
+	plan := &RenamePlan{Files: []*FileRename{}}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		d, err := s.ParseDocument(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", path, err)
+		}
+		d.FinalNewline = FinalNewlinePreserve
+		skipped, err := d.rename(oldName, newName, true)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %w", path, err)
+		}
+		text := d.String()
+		if bytes.HasPrefix(data, []byte(byteOrderMark)) {
+			text = byteOrderMark + text
+		}
+		if text != string(data) || len(skipped) > 0 {
+			plan.Files = append(plan.Files, &FileRename{path, string(data), text, skipped})
+		}
+	}
+	return plan, nil
+}
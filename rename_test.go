@@ -0,0 +1,105 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_RenameFiles(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		".env":       "DB_URL=postgres://db\r\nOTHER=1\r\n",
+		".env.local": "URL=${DB_URL}/local\nDB_URL = x",
+		".env.test":  "UNRELATED=1\n",
+	})
+	//the mode differs from that of temporary files so that Apply must keep it.
+	if err := os.Chmod(filepath.Join(dir, ".env"), 0640); err != nil {
+		t.Fatal(err)
+	}
+	s := NewDefault()
+	s.Interpolate = true
+	plan, err := s.RenameFiles("DB_URL", "DATABASE_URL", filepath.Join(dir, ".env"), filepath.Join(dir, ".env.*"))
+	if err == nil {
+		t.Fatal("SpaceAroundEqual is required to parse .env.local")
+	}
+	s.SpaceAroundEqual = true
+	if plan, err = s.RenameFiles("DB_URL", "DATABASE_URL", filepath.Join(dir, ".env"), filepath.Join(dir, ".env*")); err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Files) != 2 {
+		t.Fatalf("plan.Files = %v", plan.Files)
+	}
+	wantDiff := "--- " + filepath.Join(dir, ".env") + "\n+++ " + filepath.Join(dir, ".env") + `
+@@ -1 +1 @@
+-DB_URL=postgres://db
++DATABASE_URL=postgres://db
+--- ` + filepath.Join(dir, ".env.local") + "\n+++ " + filepath.Join(dir, ".env.local") + `
+@@ -1 +1 @@
+-URL=${DB_URL}/local
++URL=${DATABASE_URL}/local
+@@ -2 +2 @@
+-DB_URL = x
++DATABASE_URL = x
+`
+	if plan.Diff() != wantDiff {
+		t.Errorf("plan.Diff() = %v WANT %v", plan.Diff(), wantDiff)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".env")); string(data) != "DB_URL=postgres://db\r\nOTHER=1\r\n" {
+		t.Error("nothing is written before Apply")
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		".env":       "DATABASE_URL=postgres://db\r\nOTHER=1\r\n",
+		".env.local": "URL=${DATABASE_URL}/local\nDATABASE_URL = x",
+		".env.test":  "UNRELATED=1\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil || string(data) != content {
+			t.Errorf("%v = %q, %v WANT %q", name, data, err, content)
+		}
+	}
+	if info, _ := os.Stat(filepath.Join(dir, ".env")); info.Mode().Perm() != 0640 {
+		t.Errorf("mode = %v", info.Mode())
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 3 {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+func TestRenamePlan_Apply_stale(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"a.env": "A=1\n", "b.env": "A=2\n"})
+	plan, err := NewDefault().RenameFiles("A", "B", filepath.Join(dir, "*.env"))
+	if err != nil || len(plan.Files) != 2 {
+		t.Fatalf("plan, err = %v, %v", plan, err)
+	}
+	os.WriteFile(filepath.Join(dir, "b.env"), []byte("A=3\n"), 0640)
+	if err := plan.Apply(); err == nil || !strings.Contains(err.Error(), "changed since") {
+		t.Errorf("err = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "a.env")); string(data) != "A=1\n" {
+		t.Errorf("a.env = %q", data)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Errorf("entries = %v", entries)
+	}
+}
+
+func TestSourcer_RenameFiles_errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"a.env": "A=1\n", "b.env": "B=2\n"})
+	_, err := NewDefault().RenameFiles("A", "B", filepath.Join(dir, "*.env"))
+	if !reflect.DeepEqual(err.Error(), filepath.Join(dir, "b.env")+": "+ErrNameDefined("B").Error()) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := NewDefault().RenameFiles("A", "B", filepath.Join(dir, "missing.env")); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+	if _, err := NewDefault().RenameFiles("A", "B", "["); err == nil {
+		t.Error("malformed pattern")
+	}
+}
@@ -0,0 +1,225 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"strings"
+	"sync"
+	"time"
+)
+
+//Resolver resolves a reference to a value stored elsewhere, such as in a file or
+//a secrets manager. See Sourcer.Resolvers.
+type Resolver interface {
+	//Resolve returns the value referred to by ref, which is the part of a value
+	//following its SCHEME: prefix.
+	Resolve(ctx context.Context, ref string) (string, error)
+}
+
+//BatchResolver is a Resolver that can also resolve many references in a single
+//call, e.g. with one request to a remote secrets manager instead of one request
+//per variable.
+//
+//Env.Resolve() and Env.Source() resolve all pending references of each
+//BatchResolver with one call to ResolveBatch, so variables should be resolved
+//through them when batching matters. Other ways of sourcing, and Env.Get() for
+//a single variable, call Resolve.
+type BatchResolver interface {
+	Resolver
+
+	//ResolveBatch returns the values referred to by refs, which contains no
+	//duplicates, keyed by reference. References missing from the result are
+	//resolved individually with Resolve. A non-nil error fails the resolution of
+	//all of refs.
+	ResolveBatch(ctx context.Context, refs []string) (map[string]string, error)
+}
+
+//NameResolver is a Resolver whose values are bound to the name of the variable
+//that refers to them, e.g. because the name is authenticated with the value, so
+//that a reference copied to another variable does not resolve.
+//
+//Sourcers call ResolveName instead of Resolve, with the name the variable is
+//sourced as, i.e. after StripPrefix, OSSuffix, and Aliases are applied, and
+//never resolve it in batches. Wrapping a NameResolver, e.g. with WithRetry,
+//hides its ResolveName method.
+type NameResolver interface {
+	Resolver
+
+	//ResolveName returns the value referred to by ref in the definition of the
+	//variable name.
+	ResolveName(ctx context.Context, name, ref string) (string, error)
+}
+
+//ResolverFunc is an adapter that allows the use of an ordinary function as a
+//Resolver.
+type ResolverFunc func(ctx context.Context, ref string) (string, error)
+
+//Resolve calls f(ctx, ref).
+func (f ResolverFunc) Resolve(ctx context.Context, ref string) (string, error) {
+	return f(ctx, ref)
+}
+
+//ErrResolve is a line error that occurs when a Resolver fails to resolve the
+//value of a variable.
+type ErrResolve struct {
+	//Name is the name of the variable.
+	Name string
+
+	//Scheme is the scheme of the Resolver.
+	Scheme string
+
+	//Err is the error returned from the Resolver.
+	Err error
+}
+
+//Error is the error implementation for ErrResolve.
+func (e *ErrResolve) Error() string {
+	return fmt.Sprintf("resolving %q with %v: %v", e.Name, e.Scheme, e.Err)
+}
+
+//Unwrap returns e.Err.
+func (e *ErrResolve) Unwrap() error {
+	return e.Err
+}
+
+//ResolveFile is a Resolver that returns the contents of the file at ref, with a
+//single trailing newline removed, e.g. for "file:/run/secrets/db_password".
+var ResolveFile Resolver = ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+	contents, err := ioutil.ReadFile(ref)
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(contents)), nil
+})
+
+//trimNewline returns s with a single trailing "\n" or "\r\n" removed.
+func trimNewline(s string) string {
+	s = strings.TrimSuffix(s, "\n")
+	return strings.TrimSuffix(s, "\r")
+}
+
+//resolverScheme returns the Resolver in s.Resolvers for the scheme of v, and the
+//reference following the scheme.
+//ok is false if v does not start with a scheme in s.Resolvers.
+func (s *Sourcer) resolverScheme(v string) (scheme, ref string, resolver Resolver, ok bool) {
+	i := strings.IndexByte(v, ':')
+	if i <= 0 || len(s.Resolvers) == 0 {
+		return "", "", nil, false
+	}
+	scheme = v[:i]
+	resolver, ok = s.Resolvers[scheme]
+	return scheme, v[i+1:], resolver, ok && resolver != nil
+}
+
+//resolve returns v resolved with the Resolver for its scheme, or v unchanged if
+//it has no such scheme.
+//Results in cache are used instead of calling the Resolver, and new results are
+//added to it. cache may be nil.
+func (s *Sourcer) resolve(ctx context.Context, name, v string, cache *resolveCache) (string, error) {
+	scheme, ref, resolver, ok := s.resolverScheme(v)
+	if !ok {
+		return v, nil
+	}
+	//the results of a NameResolver are cached per name.
+	nameResolver, byName := resolver.(NameResolver)
+	key := ref
+	if byName {
+		key = name + "\x00" + ref
+	}
+	result, ok := cache.get(scheme, key)
+	if !ok {
+		spanCtx, span := s.tracer().StartSpan(ctx, "dotenv.Resolve",
+			Attribute{AttributeBackend, scheme}, Attribute{AttributeVariable, name})
+		start := time.Now()
+		result = &resolveResult{}
+		if byName {
+			result.value, result.err = nameResolver.ResolveName(spanCtx, name, ref)
+		} else {
+			result.value, result.err = resolver.Resolve(spanCtx, ref)
+		}
+		s.metrics().ResolverCalled(scheme, time.Since(start), result.err)
+		span.End(result.err)
+		cache.set(scheme, key, result)
+	}
+	if result.err != nil {
+		return "", &ErrResolve{name, scheme, result.err}
+	}
+	return result.value, nil
+}
+
+//resolveResult is the result of resolving a single reference.
+type resolveResult struct {
+	value string
+	err   error
+}
+
+//resolveCache contains the results of resolved references by scheme and
+//reference. It is safe for concurrent use, and a nil *resolveCache is an empty
+//cache that ignores additions.
+type resolveCache struct {
+	mu      sync.Mutex
+	results map[[2]string]*resolveResult
+}
+
+//get returns the result for ref with scheme, if there is one.
+func (c *resolveCache) get(scheme, ref string) (*resolveResult, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	result, ok := c.results[[2]string{scheme, ref}]
+	return result, ok
+}
+
+//set adds result for ref with scheme.
+func (c *resolveCache) set(scheme, ref string, result *resolveResult) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.results == nil {
+		c.results = map[[2]string]*resolveResult{}
+	}
+	c.results[[2]string{scheme, ref}] = result
+}
+
+//resolveBatches calls ResolveBatch of each BatchResolver in s.Resolvers with the
+//references in values that use it, and adds the results to cache.
+//Values whose references are already in cache are skipped.
+func (s *Sourcer) resolveBatches(ctx context.Context, values []string, cache *resolveCache) {
+	batches := map[string][]string{}
+	seen := map[[2]string]bool{}
+	for _, v := range values {
+		scheme, ref, resolver, ok := s.resolverScheme(v)
+		if !ok {
+			continue
+		}
+		_, isBatch := resolver.(BatchResolver)
+		if _, byName := resolver.(NameResolver); !isBatch || byName || seen[[2]string{scheme, ref}] {
+			continue
+		}
+		if _, cached := cache.get(scheme, ref); cached {
+			continue
+		}
+		seen[[2]string{scheme, ref}] = true
+		batches[scheme] = append(batches[scheme], ref)
+	}
+	for scheme, refs := range batches {
+		spanCtx, span := s.tracer().StartSpan(ctx, "dotenv.ResolveBatch",
+			Attribute{AttributeBackend, scheme}, Attribute{AttributeVariableCount, len(refs)})
+		start := time.Now()
+		values, err := s.Resolvers[scheme].(BatchResolver).ResolveBatch(spanCtx, refs)
+		s.metrics().ResolverCalled(scheme, time.Since(start), err)
+		span.End(err)
+		for _, ref := range refs {
+			if err != nil {
+				cache.set(scheme, ref, &resolveResult{err: err})
+			} else if value, ok := values[ref]; ok {
+				cache.set(scheme, ref, &resolveResult{value: value})
+			}
+		}
+	}
+}
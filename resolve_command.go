@@ -0,0 +1,56 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+func init() {
+	projectResolvers["gitsha"] = func(p *Project) Resolver {
+		return GitSHAResolver(p.Dir)
+	}
+}
+
+//ResolveCommand is a Resolver that runs ref with "sh -c" and returns its standard
+//output, with a single trailing newline removed, in the manner of shell command
+//substitution, e.g. for "cmd:pass show db".
+//Since it runs arbitrary commands, it should only be used with trusted input.
+var ResolveCommand Resolver = ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+	output, err := exec.CommandContext(ctx, "sh", "-c", ref).Output()
+	if err != nil {
+		return "", err
+	}
+	return trimNewline(string(output)), nil
+})
+
+//ResolveGitSHA is a Resolver that returns the commit hash of the HEAD of the git
+//repository of the working directory for "gitsha:", or its abbreviation for
+//"gitsha:short", e.g. to embed the built revision.
+var ResolveGitSHA = GitSHAResolver("")
+
+//GitSHAResolver returns a Resolver like ResolveGitSHA for the git repository of
+//dir instead of the working directory. An empty dir is the working directory.
+func GitSHAResolver(dir string) Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		args := []string{"rev-parse", "HEAD"}
+		switch ref {
+		case "":
+		case "short":
+			args = []string{"rev-parse", "--short", "HEAD"}
+		default:
+			return "", fmt.Errorf("dotenv: unknown gitsha format %q", ref)
+		}
+		if dir != "" {
+			args = append([]string{"-C", dir}, args...)
+		}
+		output, err := exec.CommandContext(ctx, "git", args...).Output()
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSpace(string(output)), nil
+	})
+}
@@ -0,0 +1,74 @@
+//go:build !tinygo
+
+package dotenv
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestResolveCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	value, err := ResolveCommand.Resolve(context.Background(), "echo hello; echo world")
+	if value != "hello\nworld" || err != nil {
+		t.Errorf("Resolve() = %q, %v", value, err)
+	}
+	if _, err := ResolveCommand.Resolve(context.Background(), "exit 3"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestResolveGitSHA(t *testing.T) {
+	output, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Skip("not in a git repository")
+	}
+	sha := strings.TrimSpace(string(output))
+	if v, err := ResolveGitSHA.Resolve(context.Background(), ""); v != sha || err != nil {
+		t.Errorf("Resolve() = %q, %v, want %q", v, err, sha)
+	}
+	if v, err := ResolveGitSHA.Resolve(context.Background(), "short"); !strings.HasPrefix(sha, v) || len(v) >= len(sha) || err != nil {
+		t.Errorf("Resolve(short) = %q, %v", v, err)
+	}
+	if _, err := ResolveGitSHA.Resolve(context.Background(), "long"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestProject_Sourcer_gitsha(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	gitInit := exec.Command("git", "-C", dir, "-c", "user.name=dotenv", "-c", "user.email=dotenv@example.com", "init", "-q")
+	commit := exec.Command("git", "-C", dir, "-c", "user.name=dotenv", "-c", "user.email=dotenv@example.com", "commit", "-q", "--allow-empty", "-m", "initial")
+	if gitInit.Run() != nil || commit.Run() != nil {
+		t.Skip("git is not available")
+	}
+	output, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sha := strings.TrimSpace(string(output))
+
+	p := newProject(dir)
+	p.Resolvers = []string{"gitsha"}
+	s, err := p.Sourcer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	env, err := s.Env(strings.NewReader("SHA=gitsha:\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := env.Get("SHA"); v != sha || err != nil {
+		t.Errorf("SHA = %q, %v, want the HEAD of the project's directory %q", v, err, sha)
+	}
+}
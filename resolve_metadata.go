@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//ResolveNow is a Resolver that returns the current time in UTC, e.g. for
+//"now:" as a build or start timestamp. See NowResolver.
+var ResolveNow = NowResolver(time.Now)
+
+//NowResolver returns a Resolver that returns the time returned from now in UTC,
+//formatted as determined by ref:
+//
+//	now:            RFC 3339, e.g. 2006-01-02T15:04:05Z
+//	now:unix        the seconds since the Unix epoch, e.g. 1136214245
+//	now:LAYOUT      formatted with the time.Format layout, e.g. now:2006-01-02
+func NowResolver(now func() time.Time) Resolver {
+	return ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		t := now().UTC()
+		switch ref {
+		case "":
+			return t.Format(time.RFC3339), nil
+		case "unix":
+			return strconv.FormatInt(t.Unix(), 10), nil
+		}
+		return t.Format(ref), nil
+	})
+}
+
+//ResolveHostname is a Resolver that returns the host name of the machine, as
+//reported by os.Hostname(), for "hostname:", or only its first label for
+//"hostname:short".
+var ResolveHostname Resolver = ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		return "", err
+	}
+	switch ref {
+	case "":
+		return hostname, nil
+	case "short":
+		return strings.SplitN(hostname, ".", 2)[0], nil
+	}
+	return "", fmt.Errorf("dotenv: unknown hostname format %q", ref)
+})
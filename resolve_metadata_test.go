@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNowResolver(t *testing.T) {
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("MST", -7*60*60))
+	resolver := NowResolver(func() time.Time { return now })
+	tests := []struct {
+		ref, want string
+	}{
+		{"", "2006-01-02T22:04:05Z"},
+		{"unix", "1136239445"},
+		{"2006-01-02", "2006-01-02"},
+		{"15:04", "22:04"},
+	}
+	for _, test := range tests {
+		if v, err := resolver.Resolve(context.Background(), test.ref); v != test.want || err != nil {
+			t.Errorf("Resolve(%q) = %q, %v, want %q", test.ref, v, err, test.want)
+		}
+	}
+}
+
+func TestResolveHostname(t *testing.T) {
+	hostname, err := os.Hostname()
+	if err != nil {
+		t.Skip(err)
+	}
+	if v, err := ResolveHostname.Resolve(context.Background(), ""); v != hostname || err != nil {
+		t.Errorf("Resolve() = %q, %v", v, err)
+	}
+	if v, err := ResolveHostname.Resolve(context.Background(), "short"); !strings.HasPrefix(hostname, v) || strings.Contains(v, ".") || err != nil {
+		t.Errorf("Resolve(short) = %q, %v", v, err)
+	}
+	if _, err := ResolveHostname.Resolve(context.Background(), "long"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestSourcer_metadataResolvers(t *testing.T) {
+	s := NewSourcerWith(WithInterpolate(false), WithResolver("now", ResolveNow), WithResolver("hostname", ResolveHostname))
+	env, err := s.Env(strings.NewReader("BUILT=now:\nHOST=hostname:\nSTAMP=${HOST}-$BUILT\nSHA=gitsha:\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	built, _ := env.Get("BUILT")
+	if _, err := time.Parse(time.RFC3339, built); err != nil {
+		t.Errorf("BUILT = %q", built)
+	}
+	host, _ := env.Get("HOST")
+	if stamp, _ := env.Get("STAMP"); stamp != host+"-"+built {
+		t.Errorf("STAMP = %q", stamp)
+	}
+	if sha, _ := env.Get("SHA"); sha != "gitsha:" {
+		t.Errorf("a Resolver that is not enabled must not resolve, SHA = %q", sha)
+	}
+}
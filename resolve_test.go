@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrResolve_Error(t *testing.T) {
+	inner := errors.New("inner")
+	err := &ErrResolve{"A", "vault", inner}
+	if err.Error() != `resolving "A" with vault: inner` {
+		t.Errorf("err.Error() = %q", err.Error())
+	}
+	if !errors.Is(err, inner) {
+		t.Fail()
+	}
+}
+
+func TestResolveFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "secret")
+	ioutil.WriteFile(path, []byte("s3cret\n\n"), 0600)
+
+	value, err := ResolveFile.Resolve(context.Background(), path)
+	if value != "s3cret\n" || err != nil {
+		t.Errorf("Resolve() = %q, %v", value, err)
+	}
+	if _, err := ResolveFile.Resolve(context.Background(), path+".missing"); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_NameVars_resolvers(t *testing.T) {
+	s := NewDefault()
+	s.Interpolate = true
+	s.Resolvers = map[string]Resolver{
+		"upper": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+			return strings.ToUpper(ref), nil
+		}),
+		"fail": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+			return "", errors.New(ref)
+		}),
+		"nil": nil,
+	}
+	s.TransformValue = func(name, value string) (string, error) {
+		return value + "!", nil
+	}
+	source := "A=a\nB=upper:${A}b\nC=postgres://host\nD=nil:d\nE=:e"
+	nameVars, err := s.NameVars(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][2]string{{"A", "a!"}, {"B", "A!B!"}, {"C", "postgres://host!"}, {"D", "nil:d!"}, {"E", ":e!"}}
+	if !reflect.DeepEqual(nameVars, want) {
+		t.Errorf("nameVars = %v WANT %v", nameVars, want)
+	}
+
+	_, err = s.NameVars(strings.NewReader("A=a\nB=fail:oops"))
+	if !reflect.DeepEqual(err, &ErrSourcing{2, &ErrResolve{"B", "fail", errors.New("oops")}}) {
+		t.Errorf("err = %v", err)
+	}
+}
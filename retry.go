@@ -0,0 +1,164 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+//ErrTransient is an error that indicates that an operation on a remote source
+//failed in a way that may succeed if tried again, e.g. a timeout or an
+//unavailable backend.
+//Resolvers return it to allow Retry to try again.
+type ErrTransient struct {
+	Err error
+}
+
+//Error is the error implementation for ErrTransient.
+func (e *ErrTransient) Error() string {
+	return fmt.Sprintf("transient failure: %v", e.Err)
+}
+
+//Unwrap returns e.Err.
+func (e *ErrTransient) Unwrap() error {
+	return e.Err
+}
+
+//ErrPermanent is an error that indicates that an operation on a remote source
+//failed in a way that will not succeed if tried again, e.g. a missing secret or
+//denied access.
+type ErrPermanent struct {
+	Err error
+}
+
+//Error is the error implementation for ErrPermanent.
+func (e *ErrPermanent) Error() string {
+	return fmt.Sprintf("permanent failure: %v", e.Err)
+}
+
+//Unwrap returns e.Err.
+func (e *ErrPermanent) Unwrap() error {
+	return e.Err
+}
+
+//IsTransient determines whether or not err is, or wraps, an *ErrTransient or
+//context.DeadlineExceeded.
+func IsTransient(err error) bool {
+	var transient *ErrTransient
+	return errors.As(err, &transient) || errors.Is(err, context.DeadlineExceeded)
+}
+
+//Retry is a retry and timeout policy for operations on remote sources.
+//The zero value makes a single attempt without a timeout.
+type Retry struct {
+	//Attempts is the maximum number of attempts. Values less than 1 mean 1.
+	Attempts int
+
+	//Timeout is the maximum duration of each attempt. Zero means no timeout.
+	Timeout time.Duration
+
+	//Backoff is the delay before the second attempt. Each following delay is
+	//doubled, up to MaxBackoff.
+	Backoff time.Duration
+
+	//MaxBackoff is the maximum delay between attempts. Zero means no maximum.
+	MaxBackoff time.Duration
+}
+
+//Do calls fn until it succeeds, returns an error that is not transient according
+//to IsTransient(), or r.Attempts attempts have been made.
+//Each call receives a context derived from ctx with r.Timeout applied.
+//If ctx is done, then Do stops waiting and returns an *ErrTransient wrapping the
+//last error.
+//If fn fails, then the returned error is an *ErrTransient or *ErrPermanent
+//wrapping fn's last error, unless that error already is one.
+func (r *Retry) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	attempts := r.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := r.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		if err = r.attempt(ctx, fn); err == nil {
+			return nil
+		}
+		if !IsTransient(err) || attempt >= attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return classify(err)
+		case <-time.After(backoff):
+		}
+		if backoff *= 2; r.MaxBackoff > 0 && backoff > r.MaxBackoff {
+			backoff = r.MaxBackoff
+		}
+	}
+	return classify(err)
+}
+
+//attempt calls fn once with r.Timeout applied to ctx.
+func (r *Retry) attempt(ctx context.Context, fn func(ctx context.Context) error) error {
+	if r.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, r.Timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
+
+//classify returns err wrapped in an *ErrTransient or *ErrPermanent, unless it
+//already is one.
+func classify(err error) error {
+	var transient *ErrTransient
+	var permanent *ErrPermanent
+	switch {
+	case errors.As(err, &transient) || errors.As(err, &permanent):
+		return err
+	case IsTransient(err):
+		return &ErrTransient{err}
+	}
+	return &ErrPermanent{err}
+}
+
+//WithRetry returns a Resolver that calls resolver according to r.
+//If resolver is a BatchResolver, then so is the returned Resolver, and each batch
+//is retried as a whole.
+func WithRetry(resolver Resolver, r *Retry) Resolver {
+	retrying := &retryResolver{resolver, r}
+	if _, ok := resolver.(BatchResolver); ok {
+		return &retryBatchResolver{retrying}
+	}
+	return retrying
+}
+
+//retryResolver is the Resolver returned from WithRetry.
+type retryResolver struct {
+	resolver Resolver
+	retry    *Retry
+}
+
+//Resolve is the Resolver implementation for retryResolver.
+func (r *retryResolver) Resolve(ctx context.Context, ref string) (value string, err error) {
+	err = r.retry.Do(ctx, func(ctx context.Context) (err error) {
+		value, err = r.resolver.Resolve(ctx, ref)
+		return err
+	})
+	return value, err
+}
+
+//retryBatchResolver is the BatchResolver returned from WithRetry.
+type retryBatchResolver struct {
+	*retryResolver
+}
+
+//ResolveBatch is the BatchResolver implementation for retryBatchResolver.
+func (r *retryBatchResolver) ResolveBatch(ctx context.Context, refs []string) (values map[string]string, err error) {
+	err = r.retry.Do(ctx, func(ctx context.Context) (err error) {
+		values, err = r.resolver.(BatchResolver).ResolveBatch(ctx, refs)
+		return err
+	})
+	return values, err
+}
@@ -0,0 +1,137 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestErrTransient_Error(t *testing.T) {
+	inner := errors.New("inner")
+	if err := (&ErrTransient{inner}); err.Error() != "transient failure: inner" || !errors.Is(err, inner) {
+		t.Fail()
+	}
+	if err := (&ErrPermanent{inner}); err.Error() != "permanent failure: inner" || !errors.Is(err, inner) {
+		t.Fail()
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	cases := map[error]bool{
+		errors.New("a"):                         false,
+		&ErrTransient{errors.New("a")}:          true,
+		&ErrResolve{"A", "s", &ErrTransient{}}:  true,
+		context.DeadlineExceeded:                true,
+		&ErrResolve{"A", "s", context.Canceled}: false,
+	}
+	for err, want := range cases {
+		if IsTransient(err) != want {
+			t.Errorf("IsTransient(%v) = %v", err, !want)
+		}
+	}
+}
+
+func TestRetry_Do(t *testing.T) {
+	transient := &ErrTransient{errors.New("unavailable")}
+	permanent := errors.New("denied")
+	cases := []struct {
+		errs     []error
+		attempts int
+		calls    int
+		err      error
+	}{
+		{[]error{nil}, 3, 1, nil},
+		{[]error{transient, transient, nil}, 3, 3, nil},
+		{[]error{transient, transient, transient, nil}, 3, 3, transient},
+		{[]error{transient, permanent, nil}, 3, 2, &ErrPermanent{permanent}},
+		{[]error{permanent}, 0, 1, &ErrPermanent{permanent}},
+	}
+	for i, c := range cases {
+		r := &Retry{Attempts: c.attempts, Backoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+		calls := 0
+		err := r.Do(context.Background(), func(ctx context.Context) error {
+			calls++
+			return c.errs[calls-1]
+		})
+		if calls != c.calls || !reflect.DeepEqual(err, c.err) {
+			t.Errorf("%v: calls, err = %v, %v WANT %v, %v", i, calls, err, c.calls, c.err)
+		}
+	}
+}
+
+func TestRetry_Do_timeout(t *testing.T) {
+	r := &Retry{Attempts: 2, Timeout: time.Millisecond}
+	calls := 0
+	err := r.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		<-ctx.Done()
+		return ctx.Err()
+	})
+	if calls != 2 || !reflect.DeepEqual(err, &ErrTransient{context.DeadlineExceeded}) {
+		t.Errorf("calls, err = %v, %v", calls, err)
+	}
+}
+
+func TestRetry_Do_canceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Retry{Attempts: 5, Backoff: time.Hour}
+	calls := 0
+	err := r.Do(ctx, func(ctx context.Context) error {
+		calls++
+		cancel()
+		return &ErrTransient{errors.New("unavailable")}
+	})
+	if calls != 1 || !IsTransient(err) {
+		t.Errorf("calls, err = %v, %v", calls, err)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	failures := 2
+	resolver := ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		if failures > 0 {
+			failures--
+			return "", &ErrTransient{errors.New("unavailable")}
+		}
+		return "<" + ref + ">", nil
+	})
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"vault": WithRetry(resolver, &Retry{Attempts: 3})}
+	nameVars, err := s.NameVars(strings.NewReader("A=vault:a"))
+	if err != nil || !reflect.DeepEqual(nameVars, [][2]string{{"A", "<a>"}}) {
+		t.Errorf("NameVars() = %v, %v", nameVars, err)
+	}
+
+	batch := &batchResolver{countingResolver: countingResolver{calls: map[string]int{}}}
+	if _, ok := WithRetry(batch, &Retry{}).(BatchResolver); !ok {
+		t.Error("WithRetry() must preserve BatchResolver")
+	}
+	if _, ok := WithRetry(resolver, &Retry{}).(BatchResolver); ok {
+		t.Fail()
+	}
+	values, err := WithRetry(batch, &Retry{}).(BatchResolver).ResolveBatch(context.Background(), []string{"a"})
+	if err != nil || values["a"] != "[a]" {
+		t.Errorf("ResolveBatch() = %v, %v", values, err)
+	}
+}
+
+func TestEnv_ResolveContext(t *testing.T) {
+	s := NewDefault()
+	s.Resolvers = map[string]Resolver{"vault": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		<-ctx.Done()
+		return "", ctx.Err()
+	})}
+	env, err := s.Env(strings.NewReader("A=vault:a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = env.ResolveContext(ctx)
+	if !reflect.DeepEqual(err, &ErrSourcing{1, &ErrResolve{"A", "vault", context.Canceled}}) {
+		t.Errorf("err = %v", err)
+	}
+}
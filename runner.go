@@ -0,0 +1,355 @@
+//go:build !tinygo && !js && !wasip1
+
+package dotenv
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+)
+
+//RestartPolicy determines how a Runner applies a changed environment to its
+//command.
+type RestartPolicy int
+
+const (
+	//RestartOnChange stops the command and starts it again with the changed
+	//environment.
+	RestartOnChange RestartPolicy = iota
+
+	//SignalOnChange sends Runner.ReloadSignal to the command, which keeps
+	//running with its old environment and is expected to reload the files
+	//itself.
+	SignalOnChange
+
+	//IgnoreChange leaves the command running as it is.
+	IgnoreChange
+)
+
+//Default values of the fields of a Runner.
+const (
+	DefaultWatchInterval = 500 * time.Millisecond
+	DefaultStopTimeout   = 10 * time.Second
+)
+
+//Runner runs a command with the variables of an Env in its environment, and
+//optionally supervises it, applying changes of the watched files to it as
+//determined by Policy.
+//Files are watched by polling their contents, so changes made within a single
+//Interval are applied together.
+type Runner struct {
+	//Load returns the Env of the command, e.g. Project.Load. It is called before
+	//the command is started and whenever a watched file changes.
+	Load func() (*Env, error)
+
+	//Files contains the paths of the files that are watched for changes. Files
+	//that do not exist are watched for being created.
+	Files []string
+
+	//Name and Args are the name and arguments of the command, as passed to
+	//exec.Command().
+	Name string
+	Args []string
+
+	//Stdin, Stdout, and Stderr are those of the command, as in exec.Cmd.
+	Stdin          io.Reader
+	Stdout, Stderr io.Writer
+
+	//Policy determines what happens when the environment changes. The command
+	//is left unchanged if the Env returned from Load after a change of the
+	//files has the same values as before.
+	Policy RestartPolicy
+
+	//ReloadSignal is the signal sent with SignalOnChange. It defaults to
+	//SIGHUP.
+	ReloadSignal os.Signal
+
+	//StopSignal is the signal sent to stop the command. If the command has not
+	//exited after StopTimeout, it is killed. They default to SIGTERM and
+	//DefaultStopTimeout.
+	StopSignal  os.Signal
+	StopTimeout time.Duration
+
+	//Interval is the interval at which Files are polled. It defaults to
+	//DefaultWatchInterval.
+	Interval time.Duration
+
+	//OnReload, if not nil, is called after every change of the files with the
+	//error returned from Load, if any, and whether or not the environment
+	//changed. The command is left unchanged if Load fails.
+	OnReload func(changed bool, err error)
+
+	//processGroup causes the command to be started in its own process group,
+	//where supported, and signals to be sent to the whole group.
+	processGroup bool
+}
+
+//Run loads the Env, starts the command, and supervises it until it exits or
+//ctx is done, in which case the command is stopped and ctx.Err() is returned.
+//If the command exits by itself, then the error returned from exec.Cmd.Wait()
+//is returned, e.g. an *exec.ExitError.
+//If the Env cannot be loaded initially, or the command cannot be started, then
+//that error is returned.
+func (r *Runner) Run(ctx context.Context) error {
+	environ, err := r.environ()
+	if err != nil {
+		return err
+	}
+	files := r.readFiles()
+	cmd, done, err := r.start(environ)
+	if err != nil {
+		return err
+	}
+
+	var tick <-chan time.Time
+	if len(r.Files) > 0 {
+		ticker := time.NewTicker(durationOr(r.Interval, DefaultWatchInterval))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			r.stop(cmd, done)
+			return ctx.Err()
+		case err := <-done:
+			return err
+		case <-tick:
+		}
+
+		current := r.readFiles()
+		if reflect.DeepEqual(current, files) {
+			continue
+		}
+		files = current
+		changed, err := r.reload(&environ)
+		if r.OnReload != nil {
+			r.OnReload(changed, err)
+		}
+		if err != nil || !changed {
+			continue
+		}
+		switch r.Policy {
+		case RestartOnChange:
+			if err := r.stop(cmd, done); err != nil && !isExitError(err) {
+				return err
+			}
+			if cmd, done, err = r.start(environ); err != nil {
+				return err
+			}
+		case SignalOnChange:
+			r.signal(cmd, signalOr(r.ReloadSignal, syscall.SIGHUP))
+		}
+	}
+}
+
+//StartProcesses runs all of processes concurrently with copies of r, whose
+//commands are replaced with the Commands of the processes, run with "sh -c" as
+//foreman does. Every line the processes write to their standard output and
+//standard error is written to out, prefixed with the name of the process.
+//r.Stdin, r.Stdout, and r.Stderr are not used.
+//When any process exits, the others are stopped, and the error it exited with is
+//returned, prefixed with its name, or nil if it exited successfully. If ctx is
+//done, then all processes are stopped and ctx.Err() is returned.
+//Every process is started in its own process group, where supported, and
+//signals are sent to the whole group, so that the commands started by its shell
+//are stopped with it.
+func (r *Runner) StartProcesses(ctx context.Context, processes []*Process, out io.Writer) error {
+	if len(processes) == 0 {
+		return nil
+	}
+	width := 0
+	for _, process := range processes {
+		if len(process.Name) > width {
+			width = len(process.Name)
+		}
+	}
+
+	runCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		name string
+		err  error
+	}
+	results := make(chan result, len(processes))
+	mu := &sync.Mutex{}
+	writers := make([]*prefixWriter, len(processes))
+	for i, process := range processes {
+		writers[i] = &prefixWriter{prefix: fmt.Sprintf("%-*v | ", width, process.Name), out: out, mu: mu}
+		runner := *r
+		runner.Name, runner.Args = "sh", []string{"-c", process.Command}
+		runner.Stdin, runner.Stdout, runner.Stderr = nil, writers[i], writers[i]
+		runner.processGroup = true
+		go func(name string, runner *Runner) {
+			results <- result{name, runner.Run(runCtx)}
+		}(process.Name, &runner)
+	}
+
+	first := <-results
+	cancel()
+	for range processes[1:] {
+		<-results
+	}
+	for _, w := range writers {
+		w.flush()
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if first.err != nil {
+		return fmt.Errorf("%v: %w", first.name, first.err)
+	}
+	return nil
+}
+
+//prefixWriter writes every line written to it to out, prefixed with prefix.
+//Writes to out are serialized with mu, which is shared by the prefixWriters of
+//all processes, so that lines are never interleaved.
+type prefixWriter struct {
+	prefix string
+	out    io.Writer
+	mu     *sync.Mutex
+	buf    []byte
+}
+
+//Write writes the complete lines of p and the buffered incomplete line before
+//it, and buffers the remaining incomplete line.
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	for {
+		i := bytes.IndexByte(w.buf, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		if err := w.writeLine(w.buf[:i+1]); err != nil {
+			return 0, err
+		}
+		w.buf = w.buf[i+1:]
+	}
+}
+
+//flush writes the buffered incomplete line, if any, with a newline.
+func (w *prefixWriter) flush() {
+	if len(w.buf) > 0 {
+		w.writeLine(append(w.buf, '\n'))
+		w.buf = nil
+	}
+}
+
+//writeLine writes line to w.out with w.prefix.
+func (w *prefixWriter) writeLine(line []byte) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err := io.WriteString(w.out, w.prefix+string(line))
+	return err
+}
+
+//environ loads the Env and returns the environment of the command.
+func (r *Runner) environ() ([]string, error) {
+	env, err := r.Load()
+	if err != nil {
+		return nil, err
+	}
+	return env.Environ(os.Environ())
+}
+
+//reload loads the Env and sets *environ to the new environment. changed is
+//whether or not it differs from the previous one.
+func (r *Runner) reload(environ *[]string) (changed bool, err error) {
+	current, err := r.environ()
+	if err != nil {
+		return false, err
+	}
+	changed = !reflect.DeepEqual(current, *environ)
+	*environ = current
+	return changed, nil
+}
+
+//readFiles returns the contents of r.Files by path. Files that cannot be read
+//are mapped to nil.
+func (r *Runner) readFiles() map[string][]byte {
+	result := make(map[string][]byte, len(r.Files))
+	for _, path := range r.Files {
+		data, err := os.ReadFile(path)
+		if err == nil && data == nil {
+			data = []byte{}
+		}
+		result[path] = data
+	}
+	return result
+}
+
+//start starts the command with environ. done receives the result of Wait once
+//the command exits. Wait stops waiting for the output of processes that the
+//command started after r.StopTimeout.
+func (r *Runner) start(environ []string) (*exec.Cmd, <-chan error, error) {
+	cmd := exec.Command(r.Name, r.Args...)
+	cmd.Env = environ
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = r.Stdin, r.Stdout, r.Stderr
+	cmd.WaitDelay = durationOr(r.StopTimeout, DefaultStopTimeout)
+	if r.processGroup {
+		setProcessGroup(cmd)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, nil, err
+	}
+	done := make(chan error, 1)
+	go func() {
+		done <- cmd.Wait()
+	}()
+	return cmd, done, nil
+}
+
+//stop sends r.StopSignal to cmd and waits for it to exit, killing it after
+//r.StopTimeout. It returns the result of Wait.
+func (r *Runner) stop(cmd *exec.Cmd, done <-chan error) error {
+	r.signal(cmd, signalOr(r.StopSignal, syscall.SIGTERM))
+	timer := time.NewTimer(durationOr(r.StopTimeout, DefaultStopTimeout))
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		r.signal(cmd, os.Kill)
+		return <-done
+	}
+}
+
+//signal sends sig to cmd, or to its process group if r.processGroup is true.
+func (r *Runner) signal(cmd *exec.Cmd, sig os.Signal) error {
+	if r.processGroup {
+		return signalGroup(cmd, sig)
+	}
+	return cmd.Process.Signal(sig)
+}
+
+//isExitError determines whether or not err is an *exec.ExitError, i.e. whether
+//the command ran but did not exit successfully.
+func isExitError(err error) bool {
+	var exitErr *exec.ExitError
+	return errors.As(err, &exitErr)
+}
+
+//durationOr returns d, or otherwise if d is not positive.
+func durationOr(d, otherwise time.Duration) time.Duration {
+	if d <= 0 {
+		return otherwise
+	}
+	return d
+}
+
+//signalOr returns sig, or otherwise if sig is nil.
+func signalOr(sig, otherwise os.Signal) os.Signal {
+	if sig == nil {
+		return otherwise
+	}
+	return sig
+}
@@ -0,0 +1,18 @@
+//go:build !unix && !tinygo && !js && !wasip1
+
+package dotenv
+
+import (
+	"os"
+	"os/exec"
+)
+
+//setProcessGroup does nothing, since process groups are not supported on this
+//platform.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+//signalGroup sends sig to cmd only, since process groups are not supported on
+//this platform.
+func signalGroup(cmd *exec.Cmd, sig os.Signal) error {
+	return cmd.Process.Signal(sig)
+}
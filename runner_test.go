@@ -0,0 +1,183 @@
+//go:build !tinygo && !js && !wasip1
+
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+//newRunnerTest returns a Runner of the shell script with Files containing a
+//single file with contents, and the path of the file. The script runs in a
+//temporary directory that is removed when the test finishes.
+func newRunnerTest(t *testing.T, script, contents string) (*Runner, string) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh is not available")
+	}
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.RemoveAll(dir) })
+	path := filepath.Join(dir, ".env")
+	if err := ioutil.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+	r := &Runner{
+		Load:        func() (*Env, error) { return NewDefault().EnvFiles(path) },
+		Files:       []string{path},
+		Name:        "sh",
+		Args:        []string{"-c", "cd " + dir + " && " + script},
+		Interval:    10 * time.Millisecond,
+		StopTimeout: time.Second,
+	}
+	return r, path
+}
+
+//replaceFile replaces the file at path with one containing contents, so that
+//the Runner never reads it partially written.
+func replaceFile(t *testing.T, path string, contents []byte) {
+	temp := path + ".tmp"
+	if err := ioutil.WriteFile(temp, contents, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(temp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+//waitForFile waits for the file at path to contain want.
+func waitForFile(t *testing.T, path, want string) {
+	for i := 0; i < 500; i++ {
+		if data, _ := ioutil.ReadFile(path); string(data) == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	data, _ := ioutil.ReadFile(path)
+	t.Fatalf("%v = %q, want %q", path, data, want)
+}
+
+func TestRunner_Run_exit(t *testing.T) {
+	r, _ := newRunnerTest(t, `exit $GOGOLFING_DOTENV_CODE`, "GOGOLFING_DOTENV_CODE=3\n")
+	err := r.Run(context.Background())
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 3 {
+		t.Errorf("Run() = %v", err)
+	}
+
+	r.Load = func() (*Env, error) { return nil, errors.New("load") }
+	if err := r.Run(context.Background()); err == nil || err.Error() != "load" {
+		t.Errorf("Run() = %v", err)
+	}
+}
+
+func TestRunner_Run_restart(t *testing.T) {
+	r, path := newRunnerTest(t, `echo $GOGOLFING_DOTENV_A >> out; exec sleep 10`, "GOGOLFING_DOTENV_A=1\n")
+	reloads := make(chan bool, 10)
+	r.OnReload = func(changed bool, err error) {
+		if err != nil {
+			t.Error(err)
+		}
+		reloads <- changed
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- r.Run(ctx)
+	}()
+	out := filepath.Join(filepath.Dir(path), "out")
+	waitForFile(t, out, "1\n")
+
+	replaceFile(t, path, []byte("#comment\nGOGOLFING_DOTENV_A=1\n"))
+	if changed := <-reloads; changed {
+		t.Error("an unchanged environment must not restart the command")
+	}
+	replaceFile(t, path, []byte("GOGOLFING_DOTENV_A=2\n"))
+	if changed := <-reloads; !changed {
+		t.Error("changed = false")
+	}
+	waitForFile(t, out, "1\n2\n")
+
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Errorf("Run() = %v", err)
+	}
+}
+
+func TestRunner_Run_signal(t *testing.T) {
+	script := `trap 'echo $GOGOLFING_DOTENV_A >> out' HUP; echo start >> out; while :; do sleep 0.01; done`
+	r, path := newRunnerTest(t, script, "GOGOLFING_DOTENV_A=1\n")
+	r.Policy = SignalOnChange
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- r.Run(ctx)
+	}()
+	out := filepath.Join(filepath.Dir(path), "out")
+	waitForFile(t, out, "start\n")
+
+	replaceFile(t, path, []byte("GOGOLFING_DOTENV_A=2\n"))
+	waitForFile(t, out, "start\n1\n")
+
+	cancel()
+	if err := <-result; err != context.Canceled {
+		t.Errorf("Run() = %v", err)
+	}
+	if data, _ := ioutil.ReadFile(out); strings.Count(string(data), "start") != 1 {
+		t.Errorf("out = %q", data)
+	}
+}
+
+func TestRunner_StartProcesses(t *testing.T) {
+	r, _ := newRunnerTest(t, "true", "GOGOLFING_DOTENV_A=a\n")
+	processes := []*Process{
+		{"web", "echo web $GOGOLFING_DOTENV_A; printf partial; exec sleep 10"},
+		{"worker", "echo worker $GOGOLFING_DOTENV_A >&2; sleep 0.2; exit 4"},
+	}
+	out := &strings.Builder{}
+	err := r.StartProcesses(context.Background(), processes, out)
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) || exitErr.ExitCode() != 4 || !strings.HasPrefix(err.Error(), "worker: ") {
+		t.Errorf("StartProcesses() = %v", err)
+	}
+	lines := strings.Split(out.String(), "\n")
+	sort.Strings(lines)
+	want := []string{"", "web    | partial", "web    | web a", "worker | worker a"}
+	if !reflect.DeepEqual(lines, want) {
+		t.Errorf("out = %q", out.String())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := r.StartProcesses(ctx, processes[:1], ioutil.Discard); err != context.DeadlineExceeded {
+		t.Errorf("StartProcesses() = %v", err)
+	}
+}
+
+func TestRunner_StartProcesses_children(t *testing.T) {
+	r, _ := newRunnerTest(t, "true", "")
+	r.StopTimeout = 200 * time.Millisecond
+	//sh forks sleep instead of replacing itself with it, so sleep would keep the
+	//output open if only sh were stopped.
+	processes := []*Process{
+		{"web", "sleep 10; echo done"},
+		{"worker", "sleep 0.2"},
+	}
+	start := time.Now()
+	if err := r.StartProcesses(context.Background(), processes, ioutil.Discard); err != nil {
+		t.Errorf("StartProcesses() = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("StartProcesses() took %v", elapsed)
+	}
+}
@@ -0,0 +1,24 @@
+//go:build unix && !tinygo
+
+package dotenv
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+//setProcessGroup causes cmd to be started in a new process group whose ID is
+//the process ID of cmd.
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+//signalGroup sends sig to the process group of cmd, which was started with
+//setProcessGroup.
+func signalGroup(cmd *exec.Cmd, sig os.Signal) error {
+	if s, ok := sig.(syscall.Signal); ok {
+		return syscall.Kill(-cmd.Process.Pid, s)
+	}
+	return cmd.Process.Signal(sig)
+}
@@ -0,0 +1,66 @@
+package dotenv
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+)
+
+//Add appends a definition of v.Name with the value v.Example to d, preceded by
+//the comment returned from v.Comment(), so that Schema returns v for it. The
+//definition is separated from a preceding line that is not empty by an empty
+//line.
+//If v.Name is invalid, then an ErrInvalidName is returned, and if it is already
+//defined in d, then an ErrNameDefined is returned.
+func (d *Document) Add(v *SchemaVar) error {
+	if d.sourcer.isNameInvalid(v.Name) {
+		return ErrInvalidName(v.Name)
+	}
+	if d.index(v.Name) >= 0 {
+		return ErrNameDefined(v.Name)
+	}
+	if n := len(d.lines); n > 0 && strings.Trim(d.lines[n-1].raw, SpaceTab) != "" {
+		d.lines = append(d.lines, &documentLine{})
+	}
+	d.Set(v.Name, v.Example)
+	return d.SetComment(v.Name, v.Comment())
+}
+
+//AddFiles adds v to each of the files at paths as by Document.Add, e.g. to both
+//.env and .env.example, so that they stay in lockstep. Files that do not exist
+//are created.
+//All files are parsed with s before any is written, so if a file cannot be read
+//or parsed, or v cannot be added to it, then that error is returned, prefixed
+//with the file's path, and no file is changed.
+func (s *Sourcer) AddFiles(v *SchemaVar, paths ...string) error {
+	texts := make([]string, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		d, err := s.ParseDocument(bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		if err := d.Add(v); err != nil {
+			return fmt.Errorf("%v: %w", path, err)
+		}
+		text := d.String()
+		if bytes.HasPrefix(data, []byte(byteOrderMark)) {
+			text = byteOrderMark + text
+		}
+		texts = append(texts, text)
+	}
+	for i, path := range paths {
+		mode := os.FileMode(0600)
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		if err := os.WriteFile(path, []byte(texts[i]), mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package dotenv
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Add(t *testing.T) {
+	d, err := NewDefault().ParseDocument(strings.NewReader("A=a\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := &SchemaVar{
+		Name:        "DATABASE_URL",
+		Type:        TypeURL,
+		Required:    true,
+		Secret:      true,
+		Description: "The database.",
+		Example:     "postgres://localhost/app",
+	}
+	if err := d.Add(v); err != nil {
+		t.Fatal(err)
+	}
+	want := "A=a\n\n# The database.\n# @type url\n# @required\n# @secret\nDATABASE_URL=postgres://localhost/app\n"
+	if d.String() != want {
+		t.Errorf("d = %q", d.String())
+	}
+	sc, err := d.Schema()
+	if err != nil || !reflect.DeepEqual(sc.Var(v.Name), v) {
+		t.Errorf("sc, err = %v, %v", sc, err)
+	}
+
+	if err := d.Add(v); err != ErrNameDefined(v.Name) {
+		t.Errorf("err = %v", err)
+	}
+	if err := d.Add(&SchemaVar{Name: "A B"}); err != ErrInvalidName("A B") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_AddFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	env, example := filepath.Join(dir, ".env"), filepath.Join(dir, ".env.example")
+	ioutil.WriteFile(example, []byte("#@required\nA=\n"), 0644)
+
+	v := &SchemaVar{Name: "B", Type: TypeInt, HasDefault: true, Default: "1"}
+	if err := NewDefault().AddFiles(v, env, example); err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := ioutil.ReadFile(env); string(data) != "# @type int\n# @default 1\nB=\n" {
+		t.Errorf("env = %q", data)
+	}
+	data, _ := ioutil.ReadFile(example)
+	if string(data) != "#@required\nA=\n\n# @type int\n# @default 1\nB=\n" {
+		t.Errorf("example = %q", data)
+	}
+	if info, _ := os.Stat(example); info.Mode().Perm() != 0644 {
+		t.Errorf("mode = %v", info.Mode())
+	}
+
+	before, _ := ioutil.ReadFile(env)
+	if err := NewDefault().AddFiles(&SchemaVar{Name: "A"}, env, example); err == nil || !strings.HasPrefix(err.Error(), example+": ") {
+		t.Errorf("err = %v", err)
+	}
+	if data, _ := ioutil.ReadFile(env); string(data) != string(before) {
+		t.Errorf("env = %q", data)
+	}
+}
@@ -0,0 +1,300 @@
+package dotenv
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//Type is the type of the value of a variable in a Schema.
+type Type string
+
+//Types of variable values.
+const (
+	TypeString   Type = "string"
+	TypeInt      Type = "int"
+	TypeFloat    Type = "float"
+	TypeBool     Type = "bool"
+	TypeDuration Type = "duration"
+	TypeURL      Type = "url"
+)
+
+//Check returns a non-nil error if value cannot be parsed as t.
+//Ints are parsed with strconv.Atoi(), floats with strconv.ParseFloat(), bools with
+//strconv.ParseBool(), durations with time.ParseDuration(), and URLs with
+//url.Parse(), where they must have a scheme. All values are valid strings.
+func (t Type) Check(value string) (err error) {
+	switch t {
+	case TypeString:
+	case TypeInt:
+		_, err = strconv.Atoi(value)
+	case TypeFloat:
+		_, err = strconv.ParseFloat(value, 64)
+	case TypeBool:
+		_, err = strconv.ParseBool(value)
+	case TypeDuration:
+		_, err = time.ParseDuration(value)
+	case TypeURL:
+		var u *url.URL
+		if u, err = url.Parse(value); err == nil && u.Scheme == "" {
+			err = fmt.Errorf("URL %q has no scheme", value)
+		}
+	default:
+		err = fmt.Errorf("unknown type %q", string(t))
+	}
+	return
+}
+
+//isType determines whether or not t is one of the known Types.
+func (t Type) isType() bool {
+	switch t {
+	case TypeString, TypeInt, TypeFloat, TypeBool, TypeDuration, TypeURL:
+		return true
+	}
+	return false
+}
+
+//SchemaVar describes a single variable expected by an application.
+type SchemaVar struct {
+	//Name is the name of the variable.
+	Name string
+
+	//Type is the type of the variable's value.
+	Type Type
+
+	//Required is true if the variable must be defined.
+	Required bool
+
+	//Default is the value used when the variable is not defined.
+	//It is only meaningful if HasDefault is true.
+	Default    string
+	HasDefault bool
+
+	//Pattern is a regular expression that the entire value must match. It is
+	//ignored if empty.
+	Pattern string
+
+	//Secret is true if the value is a secret that must not be displayed, e.g.
+	//when it is entered interactively.
+	Secret bool
+
+	//Description is a human readable description of the variable.
+	Description string
+
+	//Example is an example value.
+	Example string
+}
+
+//Schema describes the variables expected by an application.
+type Schema struct {
+	//Vars contains the expected variables in the order they are documented.
+	Vars []*SchemaVar
+}
+
+//Var returns the SchemaVar for name in sc, or nil if there is none.
+func (sc *Schema) Var(name string) *SchemaVar {
+	for _, v := range sc.Vars {
+		if v.Name == name {
+			return v
+		}
+	}
+	return nil
+}
+
+//ErrInvalidAnnotation is an error that occurs when a Schema annotation in a
+//comment is unknown or malformed.
+type ErrInvalidAnnotation struct {
+	//Name is the name of the annotated variable.
+	Name string
+
+	//Annotation is the text of the annotation.
+	Annotation string
+}
+
+//Error is the error implementation for ErrInvalidAnnotation.
+func (e *ErrInvalidAnnotation) Error() string {
+	return fmt.Sprintf("dotenv: invalid annotation %q for %q", e.Annotation, e.Name)
+}
+
+//ErrInvalidValue is an error that occurs when the value of a variable does not
+//satisfy its SchemaVar.
+type ErrInvalidValue struct {
+	//Name is the name of the variable.
+	Name string
+
+	//Value is the invalid value.
+	Value string
+
+	//Err describes why Value is invalid.
+	Err error
+}
+
+//Error is the error implementation for ErrInvalidValue.
+func (e *ErrInvalidValue) Error() string {
+	return fmt.Sprintf("dotenv: invalid value %q for %q: %v", e.Value, e.Name, e.Err)
+}
+
+//Schema returns the Schema described by d, which is usually a .env.example file.
+//Each name defined in d becomes a SchemaVar whose Example is the value of its
+//last definition. The comment immediately preceding that definition provides the
+//Description, except for lines beginning with @, which are annotations:
+//
+//	@type TYPE       sets Type to one of string, int, float, bool, duration, or url
+//	@required        sets Required
+//	@default VALUE   sets Default and HasDefault
+//	@pattern REGEXP  sets Pattern
+//	@secret          sets Secret
+//
+//Type defaults to TypeString. Description lines are joined with a space.
+//If an annotation is unknown or malformed, or a default value does not satisfy
+//its own SchemaVar, then an *ErrInvalidAnnotation is returned.
+func (d *Document) Schema() (*Schema, error) {
+	sc := &Schema{Vars: []*SchemaVar{}}
+	for _, name := range d.Names() {
+		v := &SchemaVar{Name: name, Type: TypeString}
+		v.Example, _ = d.Get(name)
+		description := []string{}
+		for _, line := range strings.Split(d.Comment(name), "\n") {
+			line = strings.TrimSpace(line)
+			if !strings.HasPrefix(line, "@") {
+				if line != "" {
+					description = append(description, line)
+				}
+				continue
+			}
+			if !v.annotate(line) {
+				return nil, &ErrInvalidAnnotation{name, line}
+			}
+		}
+		v.Description = strings.Join(description, " ")
+		if v.HasDefault && v.Check(v.Default) != nil {
+			return nil, &ErrInvalidAnnotation{name, "@default " + v.Default}
+		}
+		sc.Vars = append(sc.Vars, v)
+	}
+	return sc, nil
+}
+
+//annotate applies the annotation line to v and reports whether or not it is
+//valid.
+func (v *SchemaVar) annotate(line string) bool {
+	key, arg := line, ""
+	if i := strings.IndexAny(line, SpaceTab); i >= 0 {
+		key, arg = line[:i], strings.TrimSpace(line[i+1:])
+	}
+	switch key {
+	case "@type":
+		v.Type = Type(arg)
+		return v.Type.isType()
+	case "@required":
+		v.Required = true
+		return arg == ""
+	case "@default":
+		v.Default, v.HasDefault = arg, true
+	case "@pattern":
+		v.Pattern = arg
+		_, err := regexp.Compile(arg)
+		return arg != "" && err == nil
+	case "@secret":
+		v.Secret = true
+		return arg == ""
+	default:
+		return false
+	}
+	return true
+}
+
+//Comment returns the comment that documents v in a Document, such that
+//Document.Schema returns v for the definition it precedes: the Description
+//followed by the annotations of v.
+func (v *SchemaVar) Comment() string {
+	lines := []string{}
+	if v.Description != "" {
+		lines = append(lines, v.Description)
+	}
+	if v.Type != "" && v.Type != TypeString {
+		lines = append(lines, "@type "+string(v.Type))
+	}
+	if v.Required {
+		lines = append(lines, "@required")
+	}
+	if v.HasDefault {
+		lines = append(lines, "@default "+v.Default)
+	}
+	if v.Pattern != "" {
+		lines = append(lines, "@pattern "+v.Pattern)
+	}
+	if v.Secret {
+		lines = append(lines, "@secret")
+	}
+	return strings.Join(lines, "\n")
+}
+
+//Check returns an *ErrInvalidValue if value does not satisfy v's Type and
+//Pattern.
+func (v *SchemaVar) Check(value string) error {
+	if err := v.Type.Check(value); err != nil {
+		return &ErrInvalidValue{v.Name, value, err}
+	}
+	if v.Pattern != "" {
+		pattern, err := regexp.Compile("^(?:" + v.Pattern + ")$")
+		if err != nil {
+			return &ErrInvalidValue{v.Name, value, err}
+		}
+		if !pattern.MatchString(value) {
+			return &ErrInvalidValue{v.Name, value, fmt.Errorf("does not match pattern %q", v.Pattern)}
+		}
+	}
+	return nil
+}
+
+//Missing returns the SchemaVars of sc whose names are not defined in d, in
+//order, e.g. to prompt for the values a new .env file still needs.
+func (sc *Schema) Missing(d *Document) []*SchemaVar {
+	missing := []*SchemaVar{}
+	for _, v := range sc.Vars {
+		if _, ok := d.Get(v.Name); !ok {
+			missing = append(missing, v)
+		}
+	}
+	return missing
+}
+
+//Resolve looks up every variable in sc with lookup, applies defaults, and checks
+//the resulting values.
+//The returned map contains the value of every variable that is defined or has a
+//default.
+//If any required variables are neither defined nor have a default, then an
+//ErrMissingVariables is returned. Otherwise, if a value does not satisfy its
+//SchemaVar, then the first such *ErrInvalidValue is returned.
+func (sc *Schema) Resolve(lookup func(name string) (string, bool)) (map[string]string, error) {
+	values := map[string]string{}
+	missing := ErrMissingVariables{}
+	var invalid error
+	for _, v := range sc.Vars {
+		value, ok := lookup(v.Name)
+		if !ok && v.HasDefault {
+			value, ok = v.Default, true
+		}
+		if !ok {
+			if v.Required {
+				missing = append(missing, v.Name)
+			}
+			continue
+		}
+		if err := v.Check(value); err != nil && invalid == nil {
+			invalid = err
+		}
+		values[v.Name] = value
+	}
+	if len(missing) > 0 {
+		return nil, missing
+	}
+	if invalid != nil {
+		return nil, invalid
+	}
+	return values, nil
+}
@@ -0,0 +1,147 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestType_Check(t *testing.T) {
+	cases := []struct {
+		t     Type
+		value string
+		valid bool
+	}{
+		{TypeString, "anything", true},
+		{TypeInt, "12", true},
+		{TypeInt, "1.2", false},
+		{TypeFloat, "1.2", true},
+		{TypeFloat, "a", false},
+		{TypeBool, "true", true},
+		{TypeBool, "yes", false},
+		{TypeDuration, "5s", true},
+		{TypeDuration, "5", false},
+		{TypeURL, "postgres://localhost/db", true},
+		{TypeURL, "localhost", false},
+		{Type("other"), "a", false},
+	}
+	for _, c := range cases {
+		if err := c.t.Check(c.value); (err == nil) != c.valid {
+			t.Errorf("%v.Check(%q) = %v", c.t, c.value, err)
+		}
+	}
+}
+
+func TestDocument_Schema(t *testing.T) {
+	source := `# Port to listen on.
+# Must be free.
+# @type int
+# @default 8080
+PORT=3000
+
+#@required
+# @pattern [a-z]+
+NAME=gopher
+PLAIN=
+#@secret
+TOKEN=
+`
+	doc, err := NewDefault().ParseDocument(strings.NewReader(source))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sc, err := doc.Schema()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Schema{Vars: []*SchemaVar{
+		{Name: "PORT", Type: TypeInt, Default: "8080", HasDefault: true, Description: "Port to listen on. Must be free.", Example: "3000"},
+		{Name: "NAME", Type: TypeString, Required: true, Pattern: "[a-z]+", Example: "gopher"},
+		{Name: "PLAIN", Type: TypeString},
+		{Name: "TOKEN", Type: TypeString, Secret: true},
+	}}
+	if !reflect.DeepEqual(sc, want) {
+		t.Errorf("sc = %v WANT %v", sc.Vars, want.Vars)
+	}
+	if sc.Var("NAME") != sc.Vars[1] || sc.Var("OTHER") != nil {
+		t.Fail()
+	}
+}
+
+func TestSchema_Missing(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{{Name: "A"}, {Name: "B"}, {Name: "C"}}}
+	doc, err := NewDefault().ParseDocument(strings.NewReader("B=b\nD=d\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if missing := sc.Missing(doc); len(missing) != 2 || missing[0] != sc.Vars[0] || missing[1] != sc.Vars[2] {
+		t.Errorf("missing = %v", missing)
+	}
+}
+
+func TestDocument_Schema_invalidAnnotation(t *testing.T) {
+	cases := map[string]string{
+		"# @type integer\nA=1":           "@type integer",
+		"# @required yes\nA=1":           "@required yes",
+		"# @secret yes\nA=1":             "@secret yes",
+		"# @pattern (\nA=1":              "@pattern (",
+		"# @unknown\nA=1":                "@unknown",
+		"# @type int\n# @default x\nA=1": "@default x",
+	}
+	for source, annotation := range cases {
+		doc, err := NewDefault().ParseDocument(strings.NewReader(source))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sc, err := doc.Schema()
+		if sc != nil || !reflect.DeepEqual(err, &ErrInvalidAnnotation{"A", annotation}) {
+			t.Errorf("Schema() = %v, %v WANT annotation %q", sc, err, annotation)
+		}
+	}
+}
+
+func TestErrInvalidValue_Error(t *testing.T) {
+	err := &ErrInvalidValue{"A", "x", TypeInt.Check("x")}
+	if !strings.HasPrefix(err.Error(), `dotenv: invalid value "x" for "A": `) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSchema_Resolve(t *testing.T) {
+	sc := &Schema{Vars: []*SchemaVar{
+		{Name: "A", Type: TypeInt, Default: "1", HasDefault: true},
+		{Name: "B", Type: TypeString, Required: true, Pattern: "b+"},
+		{Name: "C", Type: TypeBool},
+	}}
+	lookup := func(env map[string]string) func(string) (string, bool) {
+		return func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		}
+	}
+
+	values, err := sc.Resolve(lookup(map[string]string{"B": "bb"}))
+	if err != nil || !reflect.DeepEqual(values, map[string]string{"A": "1", "B": "bb"}) {
+		t.Errorf("Resolve() = %v, %v", values, err)
+	}
+
+	_, err = sc.Resolve(lookup(map[string]string{"A": "x"}))
+	if !reflect.DeepEqual(err, ErrMissingVariables{"B"}) {
+		t.Errorf("err = %v", err)
+	}
+
+	_, err = sc.Resolve(lookup(map[string]string{"B": "a"}))
+	if e, ok := err.(*ErrInvalidValue); !ok || e.Name != "B" {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSchemaVar_Comment(t *testing.T) {
+	v := &SchemaVar{Name: "A", Type: TypeInt, Required: true, HasDefault: true, Default: "1", Pattern: "[0-9]", Description: "The a."}
+	if comment := v.Comment(); comment != "The a.\n@type int\n@required\n@default 1\n@pattern [0-9]" {
+		t.Errorf("Comment() = %q", comment)
+	}
+	if comment := (&SchemaVar{Name: "A", Type: TypeString}).Comment(); comment != "" {
+		t.Errorf("Comment() = %q", comment)
+	}
+}
@@ -0,0 +1,71 @@
+package dotenv
+
+import (
+	"os"
+)
+
+//WithSourced sources the file at path, calls fn, and then restores the variables
+//defined in the file to their previous values, unsetting those that were not
+//previously set. The variables are restored even if fn panics.
+//The file is parsed completely before any variable is set, so if an error occurs
+//while opening or parsing it, then that error is returned and fn is not called.
+//Otherwise, the error returned from fn is returned.
+//
+//The process's environment is shared by all goroutines, so fn should not be run
+//concurrently with other code that depends on the variables defined in the file.
+func (s *Sourcer) WithSourced(path string, fn func() error) error {
+	if err := checkSetenv(); err != nil {
+		return err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	nameVars, err := s.NameVars(file)
+	file.Close()
+	if err != nil {
+		return err
+	}
+
+	defer restoreEnv(saveEnv(nameVars))
+	st := s.newSetter()
+	for _, nameVar := range nameVars {
+		if _, err := st.setenv(nameVar[0], nameVar[1]); err != nil {
+			return err
+		}
+	}
+	return fn()
+}
+
+//savedVar is the value of an environment variable before it was changed.
+type savedVar struct {
+	name  string
+	value string
+	ok    bool
+}
+
+//saveEnv returns the current values of the names in nameVars.
+func saveEnv(nameVars [][2]string) []*savedVar {
+	result := []*savedVar{}
+	seen := map[string]bool{}
+	for _, nameVar := range nameVars {
+		if seen[nameVar[0]] {
+			continue
+		}
+		seen[nameVar[0]] = true
+		value, ok := os.LookupEnv(nameVar[0])
+		result = append(result, &savedVar{nameVar[0], value, ok})
+	}
+	return result
+}
+
+//restoreEnv sets or unsets each of saved to its previous value.
+func restoreEnv(saved []*savedVar) {
+	for _, v := range saved {
+		if v.ok {
+			os.Setenv(v.name, v.value)
+		} else {
+			os.Unsetenv(v.name)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSourcer_WithSourced(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_SCOPE_A=new\nGOGOLFING_DOTENV_SCOPE_B=b")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	os.Setenv("GOGOLFING_DOTENV_SCOPE_A", "old")
+	os.Unsetenv("GOGOLFING_DOTENV_SCOPE_B")
+	defer os.Unsetenv("GOGOLFING_DOTENV_SCOPE_A")
+
+	fnErr := errors.New("fn")
+	err := NewDefault().WithSourced(paths[0], func() error {
+		if os.Getenv("GOGOLFING_DOTENV_SCOPE_A") != "new" || os.Getenv("GOGOLFING_DOTENV_SCOPE_B") != "b" {
+			t.Error("variables not sourced within fn")
+		}
+		return fnErr
+	})
+	if err != fnErr {
+		t.Errorf("err = %v", err)
+	}
+	if os.Getenv("GOGOLFING_DOTENV_SCOPE_A") != "old" {
+		t.Fail()
+	}
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_SCOPE_B"); ok {
+		t.Fail()
+	}
+}
+
+func TestSourcer_WithSourced_panic(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_SCOPE_C=c")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	os.Unsetenv("GOGOLFING_DOTENV_SCOPE_C")
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("panic was not propagated")
+			}
+		}()
+		NewDefault().WithSourced(paths[0], func() error {
+			panic("fn")
+		})
+	}()
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_SCOPE_C"); ok {
+		t.Error("variables not restored after panic")
+	}
+}
+
+func TestSourcer_WithSourced_parseError(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_SCOPE_D=d\nname")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+
+	called := false
+	err := NewDefault().WithSourced(paths[0], func() error {
+		called = true
+		return nil
+	})
+	if called || err == nil {
+		t.Errorf("called, err = %v, %v", called, err)
+	}
+	if _, ok := os.LookupEnv("GOGOLFING_DOTENV_SCOPE_D"); ok {
+		t.Fail()
+	}
+}
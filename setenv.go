@@ -0,0 +1,65 @@
+package dotenv
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+//ErrUnsupportedPlatform is an error that occurs when variables are set in the
+//process's environment on a platform where that is not supported, i.e. js/wasm
+//and wasip1, whose environment cannot be changed for the host. Its value is the
+//GOOS of the platform.
+//It is returned before any input is read. Methods that only parse, such as
+//NameVars, Variables, Env, and ParseDocument, work on all platforms.
+type ErrUnsupportedPlatform string
+
+//Error is the error implementation for ErrUnsupportedPlatform.
+func (e ErrUnsupportedPlatform) Error() string {
+	return fmt.Sprintf("dotenv: setting environment variables is not supported on %v", string(e))
+}
+
+//checkSetenv returns an ErrUnsupportedPlatform if variables cannot be set in the
+//process's environment on this platform.
+func checkSetenv() error {
+	if !canSetenv {
+		return ErrUnsupportedPlatform(runtime.GOOS)
+	}
+	return nil
+}
+
+//setter sets variables for a single call of a method that sets variables, so
+//that s.NoOverride only protects the variables that were set before the call.
+type setter struct {
+	sourcer *Sourcer
+
+	//preset contains the names seen by the call so far, mapped to whether they
+	//were set before the call. It is nil if s.NoOverride is false.
+	preset map[string]bool
+}
+
+//newSetter returns a setter for a single call of a method of s.
+func (s *Sourcer) newSetter() *setter {
+	st := &setter{sourcer: s}
+	if s.NoOverride {
+		st.preset = map[string]bool{}
+	}
+	return st
+}
+
+//setenv sets name to value with s.setenv, unless s.NoOverride is true and name
+//was set in the process's environment before the call. ok is false if name was
+//left unchanged.
+func (st *setter) setenv(name, value string) (ok bool, err error) {
+	if st.preset != nil {
+		preset, seen := st.preset[name]
+		if !seen {
+			_, preset = os.LookupEnv(name)
+			st.preset[name] = preset
+		}
+		if preset {
+			return false, nil
+		}
+	}
+	return true, st.sourcer.setenv(name, value)
+}
@@ -0,0 +1,6 @@
+//go:build !js && !wasip1
+
+package dotenv
+
+//canSetenv is true if variables can be set in the process's environment.
+const canSetenv = true
@@ -0,0 +1,31 @@
+package dotenv
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestErrUnsupportedPlatform_Error(t *testing.T) {
+	err := ErrUnsupportedPlatform("js")
+	if err.Error() != "dotenv: setting environment variables is not supported on js" {
+		t.Fail()
+	}
+}
+
+func TestCheckSetenv(t *testing.T) {
+	err := checkSetenv()
+	if canSetenv && err != nil {
+		t.Errorf("checkSetenv() = %v", err)
+	}
+	if !canSetenv && err != ErrUnsupportedPlatform(runtime.GOOS) {
+		t.Errorf("checkSetenv() = %v", err)
+	}
+}
+
+//skipUnlessSetenv skips t on platforms where variables cannot be set in the
+//process's environment.
+func skipUnlessSetenv(t *testing.T) {
+	if !canSetenv {
+		t.Skip("setting environment variables is not supported on " + runtime.GOOS)
+	}
+}
@@ -0,0 +1,8 @@
+//go:build js || wasip1
+
+package dotenv
+
+//canSetenv is true if variables can be set in the process's environment. On
+//js/wasm and wasip1 the environment is a copy that the host never observes, so
+//setting variables in it is not supported.
+const canSetenv = false
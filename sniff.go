@@ -0,0 +1,58 @@
+package dotenv
+
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
+//ErrBinaryInput is an error that occurs when an input contains a NUL byte or
+//invalid UTF-8 near its beginning, which indicates that it is not a dotenv file
+//at all, e.g. when SourceFile is called with the path of an executable or an
+//image. It is returned instead of the errors from parsing the input's lines.
+type ErrBinaryInput struct {
+	//File is the name of the file, or empty if the input is not a file.
+	File string
+}
+
+//Error is the error implementation for ErrBinaryInput.
+func (e *ErrBinaryInput) Error() string {
+	if e.File == "" {
+		return "dotenv: input appears to be binary, not a dotenv file"
+	}
+	return fmt.Sprintf("dotenv: %v appears to be binary, not a dotenv file", e.File)
+}
+
+//sniffLength is the number of bytes at the beginning of an input that
+//checkBinary inspects.
+const sniffLength = 1024
+
+//checkBinary returns an *ErrBinaryInput if the bytes of line, which starts at
+//offset in its input, that are within the first sniffLength bytes of the input
+//contain a NUL byte or invalid UTF-8. A multi-byte character that is cut off by
+//sniffLength is not invalid.
+//Lines are checked as they are scanned, instead of peeking at the beginning of
+//the input, so that inputs such as pipes are not read beyond their first line
+//before it is visited.
+//The File of the error is the result of in's Name method, if it has one, such
+//as that of *os.File.
+func checkBinary(line string, offset int, in interface{}) error {
+	if offset >= sniffLength {
+		return nil
+	}
+	head, cut := line, false
+	if len(line) > sniffLength-offset {
+		head, cut = line[:sniffLength-offset], true
+	}
+	for i := 0; i < len(head); {
+		r, size := utf8.DecodeRuneInString(head[i:])
+		if r == 0 || (r == utf8.RuneError && size == 1 && (!cut || utf8.FullRuneInString(head[i:]))) {
+			err := &ErrBinaryInput{}
+			if named, ok := in.(interface{ Name() string }); ok {
+				err.File = named.Name()
+			}
+			return err
+		}
+		i += size
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package dotenv
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrBinaryInput_Error(t *testing.T) {
+	if (&ErrBinaryInput{}).Error() != "dotenv: input appears to be binary, not a dotenv file" {
+		t.Fail()
+	}
+	if (&ErrBinaryInput{"a.out"}).Error() != "dotenv: a.out appears to be binary, not a dotenv file" {
+		t.Fail()
+	}
+}
+
+func TestSourcer_NameVars_binary(t *testing.T) {
+	for _, source := range []string{
+		"\x7fELF\x02\x01\x01\x00",
+		"A=a\nB=\xff\xfe\n",
+		"A=a\x00",
+		"\xc3",
+	} {
+		_, err := NewDefault().NameVars(strings.NewReader(source))
+		if !reflect.DeepEqual(err, &ErrBinaryInput{}) {
+			t.Errorf("NameVars(%q) err = %v", source, err)
+		}
+	}
+
+	//only the beginning of an input is inspected, and characters cut off there
+	//are not invalid.
+	for _, source := range []string{
+		"A=" + strings.Repeat("a", sniffLength) + "\nB=\xff",
+		"A=" + strings.Repeat("a", sniffLength-4) + "é\n",
+		"A=" + strings.Repeat("a", sniffLength-3) + "é\n",
+		"A=héllo",
+	} {
+		if _, err := NewDefault().NameVars(strings.NewReader(source)); err != nil {
+			t.Errorf("NameVars(%q) err = %v", source, err)
+		}
+	}
+}
+
+func TestSourcer_SourceFile_binary(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "\x00\x01\x02")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	if err := NewDefault().SourceFile(paths[0]); !reflect.DeepEqual(err, &ErrBinaryInput{paths[0]}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestSourcer_Visit_binaryStreaming(t *testing.T) {
+	reader, writer := io.Pipe()
+	defer writer.Close()
+	go writer.Write([]byte("A=a\n"))
+
+	//the first variable is visited before the rest of the input is written, and
+	//visiting stops there.
+	stop := errors.New("stop")
+	err := NewDefault().Visit(reader, func(variable *Variable) error {
+		if variable.Name != "A" {
+			t.Errorf("variable = %v", variable)
+		}
+		return stop
+	})
+	if err != stop {
+		t.Errorf("err = %v", err)
+	}
+
+	//binary lines after the first are still found.
+	reader, writer = io.Pipe()
+	go func() {
+		writer.Write([]byte("A=a\n"))
+		writer.Write([]byte("B=\x00\n"))
+		writer.Close()
+	}()
+	visited := []string{}
+	err = NewDefault().Visit(reader, func(variable *Variable) error {
+		visited = append(visited, variable.Name)
+		return nil
+	})
+	if !reflect.DeepEqual(err, &ErrBinaryInput{}) || !reflect.DeepEqual(visited, []string{"A"}) {
+		t.Errorf("err, visited = %v, %v", err, visited)
+	}
+}
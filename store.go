@@ -0,0 +1,222 @@
+package dotenv
+
+import (
+	"io"
+	"os"
+	"sort"
+	"sync"
+)
+
+//Store is a set of effective variables that is safe for concurrent use.
+//Unlike sourcing, a Store does not change the process's environment, so its
+//variables can be replaced while other goroutines are reading them.
+//
+//The zero value is an empty Store that uses NewDefault() to parse input.
+type Store struct {
+	//Sourcer is used to parse input. If it is nil, then NewDefault() is used.
+	//It should not be changed once the Store is in use.
+	Sourcer *Sourcer
+
+	mu       sync.RWMutex
+	vars     map[string]string
+	watchers map[<-chan Change]*watcher
+}
+
+//NewStore returns an empty Store that parses input with s.
+func NewStore(s *Sourcer) *Store {
+	return &Store{Sourcer: s}
+}
+
+//Get returns the value of name in st.
+//ok is false if name is not defined in st.
+func (st *Store) Get(name string) (value string, ok bool) {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	value, ok = st.vars[name]
+	return
+}
+
+//Snapshot returns the variables currently in st. The returned Snapshot does not
+//change when st does.
+func (st *Store) Snapshot() Snapshot {
+	st.mu.RLock()
+	defer st.mu.RUnlock()
+	return Snapshot{st.vars}
+}
+
+//Replace replaces all variables in st with nameVars in a single step, such that
+//readers observe either all or none of the change.
+//As with sourcing, the last definition of a name in nameVars is the one kept.
+func (st *Store) Replace(nameVars [][2]string) {
+	vars := make(map[string]string, len(nameVars))
+	for _, nameVar := range nameVars {
+		vars[nameVar[0]] = nameVar[1]
+	}
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	previous := st.vars
+	st.vars = vars
+	for _, w := range st.watchers {
+		w.notify(previous, vars)
+	}
+}
+
+//Change is a change to the value of a single variable in a Store.
+type Change struct {
+	//Name is the name of the changed variable.
+	Name string
+
+	//Value is the new value of the variable.
+	Value string
+
+	//Unset is true if the variable is no longer defined, in which case Value is
+	//empty.
+	Unset bool
+}
+
+//watcher is a subscription to changes of names in a Store.
+type watcher struct {
+	names map[string]bool
+	ch    chan Change
+}
+
+//Watch returns a channel that receives a Change whenever the value of one of
+//names in st changes, including when it becomes defined or undefined.
+//
+//Changes are never blocked on slow receivers. The channel holds at most one
+//undelivered Change per name, the most recent one, so a receiver that falls
+//behind observes the latest value of every name that changed but may miss
+//intermediate values.
+//
+//The channel is closed by st.Unwatch().
+func (st *Store) Watch(names ...string) <-chan Change {
+	w := &watcher{names: map[string]bool{}}
+	for _, name := range names {
+		w.names[name] = true
+	}
+	w.ch = make(chan Change, len(w.names)+1)
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if st.watchers == nil {
+		st.watchers = map[<-chan Change]*watcher{}
+	}
+	st.watchers[w.ch] = w
+	return w.ch
+}
+
+//Unwatch stops the delivery of changes to ch, which must have been returned
+//from st.Watch(), and closes it.
+//Calling Unwatch more than once with the same channel has no effect.
+func (st *Store) Unwatch(ch <-chan Change) {
+	st.mu.Lock()
+	defer st.mu.Unlock()
+	if w, ok := st.watchers[ch]; ok {
+		delete(st.watchers, ch)
+		close(w.ch)
+	}
+}
+
+//notify sends the changes between previous and current to w.
+//It must be called with the Store's lock held, which guarantees that it is the
+//only sender on w.ch.
+func (w *watcher) notify(previous, current map[string]string) {
+	for name := range w.names {
+		oldValue, oldOk := previous[name]
+		newValue, newOk := current[name]
+		if oldOk == newOk && oldValue == newValue {
+			continue
+		}
+		w.send(Change{Name: name, Value: newValue, Unset: !newOk})
+	}
+}
+
+//send sends change on w.ch, replacing a pending undelivered Change of the same
+//name if there is one.
+//Since w.ch can hold a Change for every name, and send is its only sender, send
+//never blocks.
+func (w *watcher) send(change Change) {
+	pending := make([]Change, 0, cap(w.ch))
+drain:
+	for {
+		select {
+		case c := <-w.ch:
+			if c.Name != change.Name {
+				pending = append(pending, c)
+			}
+		default:
+			break drain
+		}
+	}
+	for _, c := range append(pending, change) {
+		w.ch <- c
+	}
+}
+
+//Source attempts to parse all variable definitions from in and then replaces all
+//variables in st with them as with Replace.
+//If an error occurs while parsing, then that *ErrSourcing is returned and st is
+//not changed.
+func (st *Store) Source(in io.Reader) error {
+	nameVars, err := st.sourcer().NameVars(in)
+	if err != nil {
+		return err
+	}
+	st.Replace(nameVars)
+	return nil
+}
+
+//SourceFile calls st.Source() with the file at path.
+//If os.Open() errors, then that error is returned and st is not changed.
+func (st *Store) SourceFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return st.Source(file)
+}
+
+//sourcer returns the Sourcer used by st to parse input.
+func (st *Store) sourcer() *Sourcer {
+	if st.Sourcer == nil {
+		return NewDefault()
+	}
+	return st.Sourcer
+}
+
+//Snapshot is an immutable set of variables taken from a Store.
+//The zero value is an empty Snapshot.
+type Snapshot struct {
+	vars map[string]string
+}
+
+//Get returns the value of name in sn.
+//ok is false if name is not defined in sn.
+func (sn Snapshot) Get(name string) (value string, ok bool) {
+	value, ok = sn.vars[name]
+	return
+}
+
+//Len returns the number of variables in sn.
+func (sn Snapshot) Len() int {
+	return len(sn.vars)
+}
+
+//Names returns the names of all variables in sn in sorted order.
+func (sn Snapshot) Names() []string {
+	result := make([]string, 0, len(sn.vars))
+	for name := range sn.vars {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+//Map returns a copy of the variables in sn.
+func (sn Snapshot) Map() map[string]string {
+	result := make(map[string]string, len(sn.vars))
+	for name, value := range sn.vars {
+		result[name] = value
+	}
+	return result
+}
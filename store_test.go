@@ -0,0 +1,139 @@
+package dotenv
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestStore_zeroValue(t *testing.T) {
+	skipUnlessSetenv(t)
+	st := &Store{}
+	if _, ok := st.Get("A"); ok {
+		t.Fail()
+	}
+	if st.Snapshot().Len() != 0 {
+		t.Fail()
+	}
+	if err := st.Source(strings.NewReader("A=a")); err != nil {
+		t.Error(err)
+	}
+	if value, ok := st.Get("A"); value != "a" || !ok {
+		t.Errorf("Get() = %q, %v", value, ok)
+	}
+}
+
+func TestStore_Replace(t *testing.T) {
+	st := NewStore(NewDefault())
+	st.Replace([][2]string{{"A", "1"}, {"B", "2"}, {"A", "3"}})
+	snapshot := st.Snapshot()
+
+	st.Replace([][2]string{{"C", "4"}})
+	if _, ok := st.Get("A"); ok {
+		t.Error("Replace() must remove previous variables")
+	}
+
+	if !reflect.DeepEqual(snapshot.Names(), []string{"A", "B"}) {
+		t.Errorf("snapshot.Names() = %v", snapshot.Names())
+	}
+	if value, _ := snapshot.Get("A"); value != "3" {
+		t.Errorf("snapshot.Get(A) = %q", value)
+	}
+	m := snapshot.Map()
+	m["A"] = "changed"
+	if value, _ := snapshot.Get("A"); value != "3" {
+		t.Error("Map() must return a copy")
+	}
+}
+
+func TestStore_Source_error(t *testing.T) {
+	skipUnlessSetenv(t)
+	st := NewStore(NewDefault())
+	st.Replace([][2]string{{"A", "a"}})
+	if err := st.Source(strings.NewReader("B=b\nname")); err == nil {
+		t.Fail()
+	}
+	if !reflect.DeepEqual(st.Snapshot().Names(), []string{"A"}) {
+		t.Error("Source() must not change the Store on error")
+	}
+	if err := st.SourceFile("does/not/exist.env"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestStore_concurrent(t *testing.T) {
+	st := NewStore(nil)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			st.Replace([][2]string{{"A", "a"}, {"B", "b"}})
+		}()
+		go func() {
+			defer wg.Done()
+			if n := st.Snapshot().Len(); n != 0 && n != 2 {
+				t.Errorf("Len() = %v", n)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestStore_Watch(t *testing.T) {
+	st := NewStore(nil)
+	st.Replace([][2]string{{"A", "1"}, {"B", "1"}})
+	ch := st.Watch("A")
+
+	st.Replace([][2]string{{"A", "1"}, {"B", "2"}})
+	select {
+	case change := <-ch:
+		t.Errorf("unexpected change %v", change)
+	default:
+	}
+
+	st.Replace([][2]string{{"A", "2"}})
+	if change := <-ch; change != (Change{"A", "2", false}) {
+		t.Errorf("change = %v", change)
+	}
+
+	st.Replace(nil)
+	st.Replace([][2]string{{"A", "3"}})
+	if change := <-ch; change != (Change{"A", "3", false}) {
+		t.Errorf("change = %v, want only the latest change", change)
+	}
+
+	st.Replace(nil)
+	if change := <-ch; change != (Change{"A", "", true}) {
+		t.Errorf("change = %v", change)
+	}
+
+	st.Unwatch(ch)
+	st.Unwatch(ch)
+	if _, ok := <-ch; ok {
+		t.Error("Unwatch() must close the channel")
+	}
+	st.Replace([][2]string{{"A", "4"}})
+}
+
+func TestStore_Watch_names(t *testing.T) {
+	st := NewStore(nil)
+	ch := st.Watch("A", "B", "A")
+	st.Replace([][2]string{{"A", "1"}, {"B", "1"}})
+	st.Replace([][2]string{{"A", "2"}, {"B", "1"}})
+	st.Replace([][2]string{{"A", "3"}, {"B", "1"}, {"C", "1"}})
+
+	changes := map[string]Change{}
+	for len(ch) > 0 {
+		change := <-ch
+		if _, ok := changes[change.Name]; ok {
+			t.Errorf("more than one pending change of %v", change.Name)
+		}
+		changes[change.Name] = change
+	}
+	want := map[string]Change{"A": {"A", "3", false}, "B": {"B", "1", false}}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+}
@@ -0,0 +1,75 @@
+package dotenv
+
+import (
+	"context"
+)
+
+//Attribute keys of the spans started by a Sourcer.
+const (
+	//AttributeFile is the path of a sourced file.
+	AttributeFile = "dotenv.file"
+
+	//AttributeVariableCount is the number of variables set or resolved.
+	AttributeVariableCount = "dotenv.variable_count"
+
+	//AttributeVariable is the name of the variable being resolved.
+	AttributeVariable = "dotenv.variable"
+
+	//AttributeBackend is the scheme of the Resolver being called.
+	AttributeBackend = "dotenv.backend"
+)
+
+//Attribute is a key, value pair describing a span. Value is a string or an int.
+type Attribute struct {
+	Key   string
+	Value interface{}
+}
+
+//Tracer starts spans around sourcing operations, e.g. to export them to a
+//distributed tracing system. See Sourcer.Tracer.
+//The following spans are started:
+//
+//	dotenv.SourceFile     for SourceFile, with AttributeFile and AttributeVariableCount
+//	dotenv.Resolve        for each Resolver call, with AttributeBackend and AttributeVariable
+//	dotenv.ResolveBatch   for each BatchResolver call, with AttributeBackend and AttributeVariableCount
+//
+//Implementations must be safe for concurrent use.
+type Tracer interface {
+	//StartSpan starts a span named name as a child of any span in ctx, and
+	//returns a context containing the new span.
+	StartSpan(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+//Span is a single operation started by a Tracer.
+type Span interface {
+	//SetAttributes adds attributes to the span.
+	SetAttributes(attributes ...Attribute)
+
+	//End ends the span. err is the operation's error, which may be nil.
+	End(err error)
+}
+
+//NopTracer is a Tracer whose spans do nothing.
+type NopTracer struct{}
+
+//StartSpan is the Tracer implementation for NopTracer. It returns ctx unchanged.
+func (NopTracer) StartSpan(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	return ctx, nopSpan{}
+}
+
+//nopSpan is the Span returned from NopTracer.
+type nopSpan struct{}
+
+//SetAttributes is the Span implementation for nopSpan.
+func (nopSpan) SetAttributes(attributes ...Attribute) {}
+
+//End is the Span implementation for nopSpan.
+func (nopSpan) End(err error) {}
+
+//tracer returns s.Tracer, or NopTracer if it is nil.
+func (s *Sourcer) tracer() Tracer {
+	if s.Tracer == nil {
+		return NopTracer{}
+	}
+	return s.Tracer
+}
@@ -0,0 +1,18 @@
+module github.com/gogolfing/dotenv/trace/otel
+
+go 1.23
+
+require (
+	github.com/gogolfing/dotenv v0.0.0-20261016144115-b9cea7ec471b
+	go.opentelemetry.io/otel v1.31.0
+	go.opentelemetry.io/otel/sdk v1.31.0
+	go.opentelemetry.io/otel/trace v1.31.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	go.opentelemetry.io/otel/metric v1.31.0 // indirect
+	golang.org/x/sys v0.26.0 // indirect
+)
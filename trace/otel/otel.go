@@ -0,0 +1,73 @@
+//Package otel provides a dotenv.Tracer implementation that starts OpenTelemetry
+//spans.
+//
+//It is a separate module that depends on go.opentelemetry.io/otel, so that the
+//dotenv module itself has no dependencies.
+package otel
+
+import (
+	"context"
+
+	"github.com/gogolfing/dotenv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//InstrumentationName is the name of the OpenTelemetry Tracer used for spans.
+const InstrumentationName = "github.com/gogolfing/dotenv"
+
+//Tracer is a dotenv.Tracer that starts OpenTelemetry spans.
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+var _ dotenv.Tracer = (*Tracer)(nil)
+
+//New returns a Tracer that starts spans with a Tracer from provider, e.g. the
+//global provider returned from go.opentelemetry.io/otel.GetTracerProvider().
+func New(provider trace.TracerProvider) *Tracer {
+	return &Tracer{provider.Tracer(InstrumentationName)}
+}
+
+//StartSpan is the dotenv.Tracer implementation for Tracer.
+func (t *Tracer) StartSpan(ctx context.Context, name string, attributes ...dotenv.Attribute) (context.Context, dotenv.Span) {
+	ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(convert(attributes)...))
+	return ctx, &Span{span}
+}
+
+//Span is the dotenv.Span returned from Tracer.StartSpan.
+type Span struct {
+	span trace.Span
+}
+
+var _ dotenv.Span = (*Span)(nil)
+
+//SetAttributes is the dotenv.Span implementation for Span.
+func (s *Span) SetAttributes(attributes ...dotenv.Attribute) {
+	s.span.SetAttributes(convert(attributes)...)
+}
+
+//End is the dotenv.Span implementation for Span.
+//A non-nil err is recorded on the span and sets its status to error.
+func (s *Span) End(err error) {
+	if err != nil {
+		s.span.RecordError(err)
+		s.span.SetStatus(codes.Error, err.Error())
+	}
+	s.span.End()
+}
+
+//convert returns attributes as OpenTelemetry attributes.
+func convert(attributes []dotenv.Attribute) []attribute.KeyValue {
+	result := make([]attribute.KeyValue, 0, len(attributes))
+	for _, a := range attributes {
+		switch value := a.Value.(type) {
+		case int:
+			result = append(result, attribute.Int(a.Key, value))
+		case string:
+			result = append(result, attribute.String(a.Key, value))
+		}
+	}
+	return result
+}
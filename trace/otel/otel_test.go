@@ -0,0 +1,90 @@
+package otel
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gogolfing/dotenv"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestTracer() (*Tracer, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return New(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))), recorder
+}
+
+func TestTracer(t *testing.T) {
+	tracer, recorder := newTestTracer()
+
+	ctx, parent := tracer.StartSpan(context.Background(), "parent", dotenv.Attribute{Key: "file", Value: ".env"})
+	_, child := tracer.StartSpan(ctx, "child", dotenv.Attribute{Key: "count", Value: 2}, dotenv.Attribute{Key: "ignored", Value: 1.5})
+	child.SetAttributes(dotenv.Attribute{Key: "variable", Value: "A"})
+	child.End(errors.New("unavailable"))
+	parent.End(nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("len(spans) = %v", len(spans))
+	}
+	child0, parent0 := spans[0], spans[1]
+
+	if parent0.Name() != "parent" || !reflect.DeepEqual(parent0.Attributes(), []attribute.KeyValue{attribute.String("file", ".env")}) {
+		t.Errorf("parent = %v, %v", parent0.Name(), parent0.Attributes())
+	}
+	if parent0.Status().Code != codes.Unset || len(parent0.Events()) != 0 {
+		t.Errorf("parent status, events = %v, %v", parent0.Status(), parent0.Events())
+	}
+	if parent0.InstrumentationScope().Name != InstrumentationName {
+		t.Errorf("scope = %v", parent0.InstrumentationScope())
+	}
+
+	want := []attribute.KeyValue{attribute.Int("count", 2), attribute.String("variable", "A")}
+	if child0.Name() != "child" || !reflect.DeepEqual(child0.Attributes(), want) {
+		t.Errorf("child = %v, %v", child0.Name(), child0.Attributes())
+	}
+	if child0.Parent().SpanID() != parent0.SpanContext().SpanID() {
+		t.Errorf("child must be a child of parent")
+	}
+	if status := child0.Status(); status.Code != codes.Error || status.Description != "unavailable" {
+		t.Errorf("child status = %v", status)
+	}
+	events := child0.Events()
+	if len(events) != 1 || events[0].Name != "exception" ||
+		!reflect.DeepEqual(events[0].Attributes[len(events[0].Attributes)-1], attribute.String("exception.message", "unavailable")) {
+		t.Errorf("child events = %v", events)
+	}
+}
+
+func TestTracer_Sourcer(t *testing.T) {
+	tracer, recorder := newTestTracer()
+	s := dotenv.NewDefault()
+	s.Tracer = tracer
+	dotenv.WithResolver("vault", dotenv.ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		return "secret", nil
+	}))(s)
+
+	env, err := s.Env(strings.NewReader("A=vault:db\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value, err := env.Get("A"); value != "secret" || err != nil {
+		t.Fatalf("value, err = %q, %v", value, err)
+	}
+	spans := recorder.Ended()
+	if len(spans) != 1 || spans[0].Name() != "dotenv.Resolve" {
+		t.Fatalf("spans = %v", spans)
+	}
+	want := []attribute.KeyValue{
+		attribute.String(dotenv.AttributeBackend, "vault"),
+		attribute.String(dotenv.AttributeVariable, "A"),
+	}
+	if !reflect.DeepEqual(spans[0].Attributes(), want) || spans[0].Status().Code != codes.Unset {
+		t.Errorf("attributes, status = %v, %v", spans[0].Attributes(), spans[0].Status())
+	}
+}
@@ -0,0 +1,103 @@
+package dotenv
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+//recordingTracer is a Tracer that records its spans.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []*recordingSpan
+}
+
+type recordingSpan struct {
+	name       string
+	parent     string
+	attributes map[string]interface{}
+	ended      bool
+	err        error
+}
+
+type spanKey struct{}
+
+func (t *recordingTracer) StartSpan(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	span := &recordingSpan{name: name, attributes: map[string]interface{}{}}
+	if parent, ok := ctx.Value(spanKey{}).(*recordingSpan); ok {
+		span.parent = parent.name
+	}
+	span.SetAttributes(attributes...)
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *recordingSpan) SetAttributes(attributes ...Attribute) {
+	for _, a := range attributes {
+		s.attributes[a.Key] = a.Value
+	}
+}
+
+func (s *recordingSpan) End(err error) {
+	s.ended, s.err = true, err
+}
+
+func TestNopTracer(t *testing.T) {
+	ctx := context.Background()
+	result, span := NopTracer{}.StartSpan(ctx, "name", Attribute{"a", 1})
+	if result != ctx {
+		t.Fail()
+	}
+	span.SetAttributes(Attribute{"b", "b"})
+	span.End(nil)
+}
+
+func TestSourcer_Tracer(t *testing.T) {
+	skipUnlessSetenv(t)
+	paths := writeLayerFiles(t, "GOGOLFING_DOTENV_TRACE_A=vault:a\nGOGOLFING_DOTENV_TRACE_B=b\n")
+	defer os.RemoveAll(filepath.Dir(paths[0]))
+	defer os.Unsetenv("GOGOLFING_DOTENV_TRACE_A")
+	defer os.Unsetenv("GOGOLFING_DOTENV_TRACE_B")
+
+	tracer := &recordingTracer{}
+	s := NewDefault()
+	s.Tracer = tracer
+	s.Resolvers = map[string]Resolver{"vault": ResolverFunc(func(ctx context.Context, ref string) (string, error) {
+		if span, _ := ctx.Value(spanKey{}).(*recordingSpan); span == nil || span.name != "dotenv.Resolve" {
+			return "", errors.New("resolver not called with span context")
+		}
+		return ref, nil
+	})}
+
+	if err := s.SourceFile(paths[0]); err != nil {
+		t.Fatal(err)
+	}
+	want := []*recordingSpan{
+		{"dotenv.SourceFile", "", map[string]interface{}{AttributeFile: paths[0], AttributeVariableCount: 2}, true, nil},
+		{"dotenv.Resolve", "dotenv.SourceFile", map[string]interface{}{AttributeBackend: "vault", AttributeVariable: "GOGOLFING_DOTENV_TRACE_A"}, true, nil},
+	}
+	if !reflect.DeepEqual(tracer.spans, want) {
+		t.Errorf("spans = %v WANT %v", spanStrings(tracer.spans), spanStrings(want))
+	}
+
+	tracer.spans = nil
+	err := s.SourceFile(paths[0] + ".missing")
+	if len(tracer.spans) != 1 || tracer.spans[0].err != err || !tracer.spans[0].ended {
+		t.Errorf("spans = %v", spanStrings(tracer.spans))
+	}
+}
+
+func spanStrings(spans []*recordingSpan) []string {
+	result := []string{}
+	for _, span := range spans {
+		result = append(result, fmt.Sprintf("%+v", *span))
+	}
+	return result
+}
@@ -0,0 +1,62 @@
+package dotenv
+
+import (
+	"os"
+	"os/user"
+	"strings"
+)
+
+//Transform is a function that changes the value of the variable name.
+//See Sourcer.TransformValue.
+type Transform func(name, value string) (string, error)
+
+//Transforms returns a Transform that calls each of transforms in order, passing
+//the result of one as the value of the next.
+//If any of transforms returns an error, then that error is returned immediately.
+//Nil transforms are skipped.
+func Transforms(transforms ...Transform) Transform {
+	return func(name, value string) (string, error) {
+		for _, transform := range transforms {
+			if transform == nil {
+				continue
+			}
+			var err error
+			if value, err = transform(name, value); err != nil {
+				return "", err
+			}
+		}
+		return value, nil
+	}
+}
+
+//ExpandTilde is a Transform that expands a leading tilde in value to a home
+//directory, in the same way a POSIX shell does for unquoted words.
+//A value of "~" or starting with "~/" is expanded using the current user's home
+//directory as reported by os.UserHomeDir().
+//A value of "~user" or starting with "~user/" is expanded using the home directory
+//of user as reported by os/user.Lookup(). If user does not exist or cannot be
+//looked up on the current platform, then value is returned unchanged.
+//All other values are returned unchanged.
+func ExpandTilde(name, value string) (string, error) {
+	if !strings.HasPrefix(value, "~") {
+		return value, nil
+	}
+	username, rest := value[1:], ""
+	if slash := strings.Index(username, "/"); slash >= 0 {
+		username, rest = username[:slash], username[slash:]
+	}
+
+	if username == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		return home + rest, nil
+	}
+
+	u, err := user.Lookup(username)
+	if err != nil || u.HomeDir == "" {
+		return value, nil
+	}
+	return u.HomeDir + rest, nil
+}
@@ -0,0 +1,97 @@
+package dotenv
+
+import (
+	"errors"
+	"os"
+	"os/user"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTransforms(t *testing.T) {
+	upper := func(name, value string) (string, error) {
+		return strings.ToUpper(value), nil
+	}
+	suffix := func(name, value string) (string, error) {
+		return value + "_" + name, nil
+	}
+
+	transform := Transforms(upper, nil, suffix)
+	if v, err := transform("name", "value"); v != "VALUE_name" || err != nil {
+		t.Errorf("transform() = %q, %v", v, err)
+	}
+
+	transform = Transforms()
+	if v, err := transform("name", "value"); v != "value" || err != nil {
+		t.Errorf("transform() = %q, %v", v, err)
+	}
+}
+
+func TestTransforms_error(t *testing.T) {
+	called := false
+	fail := func(name, value string) (string, error) {
+		return "ignored", errors.New("transform error")
+	}
+	after := func(name, value string) (string, error) {
+		called = true
+		return value, nil
+	}
+
+	v, err := Transforms(fail, after)("name", "value")
+	if v != "" || err == nil || called {
+		t.Fail()
+	}
+}
+
+func TestSourcer_TransformValue(t *testing.T) {
+	s := NewDefault()
+	s.StripPrefix = "APP_"
+	s.TransformValue = func(name, value string) (string, error) {
+		return name + ":" + value, nil
+	}
+	nameVars, err := s.NameVars(strings.NewReader("APP_a=\"b c\"\nd=e"))
+	if err != nil {
+		t.Error(err)
+	}
+	if !reflect.DeepEqual(nameVars, [][2]string{{"a", "a:b c"}, {"d", "d:e"}}) {
+		t.Errorf("nameVars = %v", nameVars)
+	}
+}
+
+func TestSourcer_TransformValue_error(t *testing.T) {
+	transformErr := errors.New("transform error")
+	s := NewDefault()
+	s.TransformValue = func(name, value string) (string, error) {
+		return "", transformErr
+	}
+	_, err := s.NameVars(strings.NewReader("\na=b"))
+	if !reflect.DeepEqual(err, &ErrSourcing{2, transformErr}) {
+		t.Errorf("err = %v", err)
+	}
+}
+
+func TestExpandTilde(t *testing.T) {
+	home := os.Getenv("HOME")
+	defer os.Setenv("HOME", home)
+	os.Setenv("HOME", "/home/gogolfing")
+
+	cases := map[string]string{
+		"":                    "",
+		"a/~/b":               "a/~/b",
+		"~":                   "/home/gogolfing",
+		"~/":                  "/home/gogolfing/",
+		"~/a/b":               "/home/gogolfing/a/b",
+		"~gogolfing_nobody/a": "~gogolfing_nobody/a",
+	}
+	if u, err := user.Current(); err == nil && u.HomeDir != "" {
+		cases["~"+u.Username] = u.HomeDir
+		cases["~"+u.Username+"/a"] = u.HomeDir + "/a"
+	}
+	for value, want := range cases {
+		result, err := ExpandTilde("name", value)
+		if result != want || err != nil {
+			t.Errorf("ExpandTilde(%q) = %q, %v WANT %q", value, result, err, want)
+		}
+	}
+}
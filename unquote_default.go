@@ -0,0 +1,8 @@
+//go:build !tinygo
+
+package dotenv
+
+import "strconv"
+
+//defaultUnquote is the Unquote of NewDefault().
+var defaultUnquote = strconv.Unquote
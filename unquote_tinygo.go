@@ -0,0 +1,7 @@
+//go:build tinygo
+
+package dotenv
+
+//defaultUnquote is the Unquote of NewDefault(). TinyGo builds use Unquote instead
+//of strconv.Unquote().
+var defaultUnquote = Unquote
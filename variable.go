@@ -0,0 +1,91 @@
+package dotenv
+
+import (
+	"context"
+	"io"
+	"os"
+)
+
+//Variable is a single variable definition along with metadata about where and how
+//it was defined.
+type Variable struct {
+	//Name is the name of the variable.
+	Name string `json:"name"`
+
+	//Value is the value of the variable.
+	Value string `json:"value"`
+
+	//File is the path of the file the variable was defined in.
+	//It is empty if the variable was not read from a file.
+	File string `json:"file,omitempty"`
+
+	//Line is the line number (1-based) the variable was defined on.
+	Line int `json:"line"`
+
+	//Quoted is true if the value was surrounded by a Sourcer's Quote.
+	Quoted bool `json:"quoted"`
+
+	//Exported is true if the definition was preceded by a Sourcer's Export
+	//keyword. See Encoder.EncodeVariables for writing it back.
+	Exported bool `json:"exported,omitempty"`
+
+	//Deprecated is the message of the "#deprecated: use NEW_NAME" comment on the
+	//line of the definition, e.g. "use NEW_NAME". It is empty if the variable is
+	//not deprecated. See Sourcer.Warn.
+	Deprecated string `json:"deprecated,omitempty"`
+}
+
+//Variables attempts to parse and return all variable definitions from in, along
+//with their metadata.
+//As soon as an error occurs while parsing, then that *ErrSourcing is returned and
+//reading stops.
+//opts change a copy of s for this call only, as described by Source.
+func (s *Sourcer) Variables(in io.Reader, opts ...Option) ([]*Variable, error) {
+	return s.with(opts).variables(in, "")
+}
+
+//VariablesFile attempts to parse and return all variable definitions in the file at
+//path, with File set to path.
+//If os.Open() errors, then that error is returned immediately.
+//If an error occurs while parsing, then an *ErrSourcing is returned.
+//If s.Include is not empty, then the variables of included files are returned in
+//place of their directives, with File set to the path of the included file, and
+//errors in included files are returned as an *ErrInclude.
+//opts change a copy of s for this call only, as described by Source.
+func (s *Sourcer) VariablesFile(path string, opts ...Option) ([]*Variable, error) {
+	s = s.with(opts)
+	if s.Include != "" {
+		return s.variablesIncludes(path)
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return s.variables(file, path)
+}
+
+//variables returns all variable definitions from in with File set to path.
+func (s *Sourcer) variables(in io.Reader, path string) ([]*Variable, error) {
+	s, in, dialectErr := s.dialectFor(in)
+	if dialectErr != nil {
+		return nil, dialectErr
+	}
+	result := []*Variable{}
+	defined := map[string]string{}
+	err := scanLines(in, func(lineNumber int, line string) error {
+		err := s.visitLine(context.Background(), lineNumber, line, defined, func(variable *Variable) error {
+			variable.File = path
+			result = append(result, variable)
+			return nil
+		})
+		if err != nil {
+			return &ErrSourcing{lineNumber, err}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
@@ -0,0 +1,54 @@
+package dotenv
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSourcer_Variables_success(t *testing.T) {
+	variables, err := NewDefault().Variables(strings.NewReader("a=1\n\nexport b=\"2 3\"\n"))
+	if err != nil {
+		t.Error(err)
+	}
+	want := []*Variable{
+		{Name: "a", Value: "1", Line: 1},
+		{Name: "b", Value: "2 3", Line: 3, Quoted: true, Exported: true},
+	}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("variables = %v WANT %v", variables, want)
+	}
+}
+
+func TestSourcer_Variables_error(t *testing.T) {
+	variables, err := NewDefault().Variables(strings.NewReader("a=1\nb"))
+	if variables != nil || !reflect.DeepEqual(err, &ErrSourcing{2, ErrNonVariableLine("b")}) {
+		t.Errorf("variables, err = %v, %v", variables, err)
+	}
+}
+
+func TestSourcer_VariablesFile(t *testing.T) {
+	file, err := ioutil.TempFile("", "gogolfing.dotenv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file.Name())
+	fmt.Fprint(file, "a=1\n")
+	file.Close()
+
+	variables, err := NewDefault().VariablesFile(file.Name())
+	if err != nil {
+		t.Error(err)
+	}
+	want := []*Variable{{Name: "a", Value: "1", File: file.Name(), Line: 1}}
+	if !reflect.DeepEqual(variables, want) {
+		t.Errorf("variables = %v WANT %v", variables, want)
+	}
+
+	if _, err := NewDefault().VariablesFile(file.Name() + ".missing"); !os.IsNotExist(err) {
+		t.Errorf("err = %v", err)
+	}
+}